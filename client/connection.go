@@ -59,6 +59,9 @@ type ConnectionArgs struct {
 	// OpenID Connect tokens
 	OIDCTokens *oidc.Tokens[*oidc.IDTokenClaims]
 
+	// Authentication token passed as a bearer token
+	BearerToken string
+
 	// Skip automatic GetServer request upon connection
 	SkipGetServer bool
 
@@ -375,6 +378,7 @@ func httpsIncus(ctx context.Context, requestURL string, args *ConnectionArgs) (I
 		ctxConnectedCancel: ctxConnectedCancel,
 		eventConns:         make(map[string]*websocket.Conn),
 		eventListeners:     make(map[string][]*EventListener),
+		bearerToken:        args.BearerToken,
 	}
 
 	if slices.Contains([]string{api.AuthenticationMethodOIDC}, args.AuthType) {
@@ -49,7 +49,8 @@ type ProtocolIncus struct {
 	clusterTarget string
 	project       string
 
-	oidcClient *oidcClient
+	oidcClient  *oidcClient
+	bearerToken string
 }
 
 // Disconnect gets rid of any background goroutines.
@@ -183,6 +184,7 @@ func (r *ProtocolIncus) DoWebsocket(dialer websocket.Dialer, uri string, req *ht
 // User-Agent (if r.httpUserAgent is set).
 // X-Incus-authenticated (if r.requireAuthenticated is set).
 // OIDC Authorization header (if r.oidcClient is set).
+// Bearer Authorization header (if r.bearerToken is set).
 func (r *ProtocolIncus) addClientHeaders(req *http.Request) {
 	if r.httpUserAgent != "" {
 		req.Header.Set("User-Agent", r.httpUserAgent)
@@ -194,6 +196,8 @@ func (r *ProtocolIncus) addClientHeaders(req *http.Request) {
 
 	if r.oidcClient != nil {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.oidcClient.getAccessToken()))
+	} else if r.bearerToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.bearerToken))
 	}
 }
 
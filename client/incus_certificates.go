@@ -75,6 +75,21 @@ func (r *ProtocolIncus) UpdateCertificate(fingerprint string, certificate api.Ce
 	return nil
 }
 
+// RenewCertificate atomically replaces a certificate in the Incus trust store by a renewed one.
+func (r *ProtocolIncus) RenewCertificate(fingerprint string, certificate api.CertificateRenewPost) error {
+	if !r.HasExtension("certificate_renewal") {
+		return fmt.Errorf("The server is missing the required \"certificate_renewal\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("POST", fmt.Sprintf("/certificates/%s/renew", url.PathEscape(fingerprint)), certificate, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteCertificate removes a certificate from the Incus trust store.
 func (r *ProtocolIncus) DeleteCertificate(fingerprint string) error {
 	// Send the request
@@ -203,6 +203,52 @@ func (r *ProtocolIncus) UpdateClusterMemberState(name string, state api.ClusterM
 	return op, nil
 }
 
+// GetClusterRollout returns the currently running cluster rollout.
+func (r *ProtocolIncus) GetClusterRollout() (*api.ClusterRollout, string, error) {
+	err := r.CheckExtension("cluster_rollout")
+	if err != nil {
+		return nil, "", err
+	}
+
+	rollout := api.ClusterRollout{}
+	etag, err := r.queryStruct("GET", "/cluster/rollout", nil, "", &rollout)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &rollout, etag, nil
+}
+
+// CreateClusterRollout starts rolling out the cluster members.
+func (r *ProtocolIncus) CreateClusterRollout(rollout api.ClusterRolloutPost) (Operation, error) {
+	err := r.CheckExtension("cluster_rollout")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation("POST", "/cluster/rollout", rollout, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// UpdateClusterRollout pauses, resumes or continues the currently running cluster rollout.
+func (r *ProtocolIncus) UpdateClusterRollout(rollout api.ClusterRolloutPut) error {
+	err := r.CheckExtension("cluster_rollout")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query("PUT", "/cluster/rollout", rollout, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetClusterGroups returns the cluster groups.
 func (r *ProtocolIncus) GetClusterGroups() ([]api.ClusterGroup, error) {
 	if !r.HasExtension("clustering_groups") {
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -2172,6 +2173,29 @@ func (r *ProtocolIncus) GetInstanceState(name string) (*api.InstanceState, strin
 	return &state, etag, nil
 }
 
+// GetInstanceStateWithHistory returns the state of the instance along with its resource usage history over the given duration.
+func (r *ProtocolIncus) GetInstanceStateWithHistory(name string, history time.Duration) (*api.InstanceState, string, error) {
+	err := r.CheckExtension("instance_state_history")
+	if err != nil {
+		return nil, "", err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, "", err
+	}
+
+	state := api.InstanceState{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("%s/%s/state?history=%s", path, url.PathEscape(name), url.QueryEscape(history.String())), nil, "", &state)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &state, etag, nil
+}
+
 // UpdateInstanceState updates the instance to match the requested state.
 func (r *ProtocolIncus) UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2279,6 +2303,73 @@ func (r *ProtocolIncus) DeleteInstanceLogfile(name string, filename string) erro
 	return nil
 }
 
+// GetInstanceSessions returns the recorded sessions for the instance.
+func (r *ProtocolIncus) GetInstanceSessions(name string) ([]api.InstanceSession, error) {
+	err := r.CheckExtension("instance_session_recording")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []api.InstanceSession{}
+
+	// Fetch the raw value.
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/sessions?recursion=1", path, url.PathEscape(name)), nil, "", &sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// GetInstanceSessionRecording returns the asciinema recording of the requested session.
+//
+// Note that it's the caller's responsibility to close the returned ReadCloser.
+func (r *ProtocolIncus) GetInstanceSessionRecording(name string, id string) (io.ReadCloser, error) {
+	err := r.CheckExtension("instance_session_recording")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request
+	url := fmt.Sprintf("%s/1.0%s/%s/sessions/%s", r.httpBaseURL.String(), path, url.PathEscape(name), url.PathEscape(id))
+
+	url, err = r.setQueryAttributes(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := incusParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, err
+}
+
 // getInstanceExecOutputLogFile returns the content of the requested exec logfile.
 //
 // Note that it's the caller's responsibility to close the returned ReadCloser.
@@ -2830,6 +2921,13 @@ func (r *ProtocolIncus) CreateInstanceBackup(instanceName string, backup api.Ins
 		return nil, fmt.Errorf("The server is missing the required \"container_backup\" API extension")
 	}
 
+	if backup.Stateful {
+		err := r.CheckExtension("instance_backup_stateful")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups", path, url.PathEscape(instanceName)), backup, "")
 	if err != nil {
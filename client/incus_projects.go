@@ -3,6 +3,7 @@ package incus
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -78,6 +79,28 @@ func (r *ProtocolIncus) GetProjectState(name string) (*api.ProjectState, error)
 	return &projectState, nil
 }
 
+// GetProjectUsage returns the resources consumed by the project between the provided times.
+func (r *ProtocolIncus) GetProjectUsage(name string, from time.Time, to time.Time) (*api.ProjectUsage, error) {
+	err := r.CheckExtension("projects_usage_report")
+	if err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+	v.Set("from", from.UTC().Format(time.RFC3339))
+	v.Set("to", to.UTC().Format(time.RFC3339))
+
+	usage := api.ProjectUsage{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct("GET", fmt.Sprintf("/projects/%s/usage?%s", url.PathEscape(name), v.Encode()), nil, "", &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
 // GetProjectAccess returns an Access entry for the specified project.
 func (r *ProtocolIncus) GetProjectAccess(name string) (api.Access, error) {
 	access := api.Access{}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -85,12 +86,32 @@ type InstanceServer interface {
 	UseTarget(name string) (client InstanceServer)
 	UseProject(name string) (client InstanceServer)
 
+	// Authorization functions
+	GetAuthGroupNames() (names []string, err error)
+	GetAuthGroups() (groups []api.AuthGroup, err error)
+	GetAuthGroup(name string) (group *api.AuthGroup, ETag string, err error)
+	CreateAuthGroup(group api.AuthGroupsPost) (err error)
+	UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) (err error)
+	RenameAuthGroup(name string, group api.AuthGroupPost) (err error)
+	DeleteAuthGroup(name string) (err error)
+	GetAuthIdentities() (identities []api.AuthIdentity, err error)
+	GetAuthIdentity(authenticationMethod string, identifier string) (identity *api.AuthIdentity, ETag string, err error)
+	CreateAuthIdentity(identity api.AuthIdentitiesPost) (err error)
+	UpdateAuthIdentity(authenticationMethod string, identifier string, identity api.AuthIdentityPut, ETag string) (err error)
+	DeleteAuthIdentity(authenticationMethod string, identifier string) (err error)
+	GetAuthTokens() (tokens []api.AuthToken, err error)
+	GetAuthTokensAllProjects() (tokens []api.AuthToken, err error)
+	GetAuthToken(id string) (token *api.AuthToken, err error)
+	CreateAuthToken(token api.AuthTokensPost) (secret *api.AuthTokenSecret, err error)
+	DeleteAuthToken(id string) (err error)
+
 	// Certificate functions
 	GetCertificateFingerprints() (fingerprints []string, err error)
 	GetCertificates() (certificates []api.Certificate, err error)
 	GetCertificate(fingerprint string) (certificate *api.Certificate, ETag string, err error)
 	CreateCertificate(certificate api.CertificatesPost) (err error)
 	UpdateCertificate(fingerprint string, certificate api.CertificatePut, ETag string) (err error)
+	RenewCertificate(fingerprint string, certificate api.CertificateRenewPost) (err error)
 	DeleteCertificate(fingerprint string) (err error)
 	CreateCertificateToken(certificate api.CertificatesPost) (op Operation, err error)
 
@@ -152,6 +173,7 @@ type InstanceServer interface {
 	CreateInstanceFromBackup(args InstanceBackupArgs) (op Operation, err error)
 
 	GetInstanceState(name string) (state *api.InstanceState, ETag string, err error)
+	GetInstanceStateWithHistory(name string, history time.Duration) (state *api.InstanceState, ETag string, err error)
 	UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (op Operation, err error)
 
 	GetInstanceAccess(name string) (access api.Access, err error)
@@ -160,6 +182,9 @@ type InstanceServer interface {
 	GetInstanceLogfile(name string, filename string) (content io.ReadCloser, err error)
 	DeleteInstanceLogfile(name string, filename string) (err error)
 
+	GetInstanceSessions(name string) (sessions []api.InstanceSession, err error)
+	GetInstanceSessionRecording(name string, id string) (content io.ReadCloser, err error)
+
 	GetInstanceMetadata(name string) (metadata *api.ImageMetadata, ETag string, err error)
 	UpdateInstanceMetadata(name string, metadata api.ImageMetadata, ETag string) (err error)
 
@@ -292,6 +317,7 @@ type InstanceServer interface {
 	GetProjects() (projects []api.Project, err error)
 	GetProject(name string) (project *api.Project, ETag string, err error)
 	GetProjectState(name string) (project *api.ProjectState, err error)
+	GetProjectUsage(name string, from time.Time, to time.Time) (usage *api.ProjectUsage, err error)
 	GetProjectAccess(name string) (access api.Access, err error)
 	CreateProject(project api.ProjectsPost) (err error)
 	UpdateProject(name string, project api.ProjectPut, ETag string) (err error)
@@ -299,6 +325,15 @@ type InstanceServer interface {
 	DeleteProject(name string) (err error)
 	DeleteProjectForce(name string) (err error)
 
+	// Project template functions ("project_templates" API extension)
+	GetProjectTemplateNames() (names []string, err error)
+	GetProjectTemplates() (templates []api.ProjectTemplate, err error)
+	GetProjectTemplate(name string) (template *api.ProjectTemplate, ETag string, err error)
+	CreateProjectTemplate(template api.ProjectTemplatesPost) (err error)
+	UpdateProjectTemplate(name string, template api.ProjectTemplatePut, ETag string) (err error)
+	RenameProjectTemplate(name string, template api.ProjectTemplatePost) (err error)
+	DeleteProjectTemplate(name string) (err error)
+
 	// Storage pool functions ("storage" API extension)
 	GetStoragePoolNames() (names []string, err error)
 	GetStoragePools() (pools []api.StoragePool, err error)
@@ -381,6 +416,9 @@ type InstanceServer interface {
 	UpdateClusterCertificate(certs api.ClusterCertificatePut, ETag string) (err error)
 	GetClusterMemberState(name string) (*api.ClusterMemberState, string, error)
 	UpdateClusterMemberState(name string, state api.ClusterMemberStatePost) (op Operation, err error)
+	GetClusterRollout() (rollout *api.ClusterRollout, ETag string, err error)
+	CreateClusterRollout(rollout api.ClusterRolloutPost) (op Operation, err error)
+	UpdateClusterRollout(rollout api.ClusterRolloutPut) (err error)
 	GetClusterGroups() ([]api.ClusterGroup, error)
 	GetClusterGroupNames() ([]string, error)
 	RenameClusterGroup(name string, group api.ClusterGroupPost) error
@@ -31,6 +31,8 @@ var api10 = []APIEndpoint{
 	api10Cmd,
 	execCmd,
 	eventsCmd,
+	filesystemsFreezeCmd,
+	filesystemsThawCmd,
 	metricsCmd,
 	operationsCmd,
 	operationCmd,
@@ -2,6 +2,7 @@ package main
 
 import (
 	"sync"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/events"
 )
@@ -22,6 +23,12 @@ type Daemon struct {
 	DevIncusRunning bool
 	DevIncusMu      sync.Mutex
 	DevIncusEnabled bool
+
+	// Mount points frozen on request of the host, the timer thawing them automatically and whether it fired.
+	filesystemsFrozen    []string
+	filesystemsThawTimer *time.Timer
+	filesystemsTimedOut  bool
+	filesystemsMu        sync.Mutex
 }
 
 // newDaemon returns a new Daemon object with the given configuration.
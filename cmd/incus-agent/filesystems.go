@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+	agentAPI "github.com/lxc/incus/v6/shared/api/agent"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Ioctls used to freeze and thaw a file system (_IOWR('X', 119, int) and _IOWR('X', 120, int)).
+const (
+	ioctlFIFreeze = 0xC0045877
+	ioctlFIThaw   = 0xC0045878
+)
+
+// Directories holding the scripts run before freezing and after thawing the file systems.
+const (
+	filesystemsPreFreezeHooksPath = "/etc/incus-agent/pre-snapshot.d"
+	filesystemsPostThawHooksPath  = "/etc/incus-agent/post-snapshot.d"
+)
+
+// filesystemsDefaultTimeout is used when the host doesn't request a specific timeout.
+const filesystemsDefaultTimeout = 30 * time.Second
+
+var filesystemsFreezeCmd = APIEndpoint{
+	Name: "filesystems-freeze",
+	Path: "filesystems/freeze",
+
+	Post: APIEndpointAction{Handler: filesystemsFreezePost},
+}
+
+var filesystemsThawCmd = APIEndpoint{
+	Name: "filesystems-thaw",
+	Path: "filesystems/thaw",
+
+	Post: APIEndpointAction{Handler: filesystemsThawPost},
+}
+
+func filesystemsFreezePost(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.FilesystemsFreezePost{}
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = json.Unmarshal(buf, &req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	timeout := filesystemsDefaultTimeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	d.filesystemsMu.Lock()
+	defer d.filesystemsMu.Unlock()
+
+	if d.filesystemsThawTimer != nil {
+		return response.Conflict(fmt.Errorf("File systems are already frozen"))
+	}
+
+	// Give the applications a chance to get into a consistent state.
+	err = filesystemsRunHooks(filesystemsPreFreezeHooksPath, req.Reason, timeout)
+	if err != nil {
+		_ = filesystemsRunHooks(filesystemsPostThawHooksPath, req.Reason, timeout)
+		return response.InternalError(err)
+	}
+
+	frozen, err := filesystemsFreeze()
+	if err != nil {
+		_ = filesystemsRunHooks(filesystemsPostThawHooksPath, req.Reason, timeout)
+		return response.InternalError(err)
+	}
+
+	d.filesystemsFrozen = frozen
+	d.filesystemsTimedOut = false
+
+	// Never leave the guest frozen, even if the host fails to thaw it.
+	d.filesystemsThawTimer = time.AfterFunc(timeout, func() {
+		d.filesystemsMu.Lock()
+		defer d.filesystemsMu.Unlock()
+
+		logger.Warn("Thawing file systems after timeout", logger.Ctx{"timeout": timeout})
+
+		err := d.filesystemsThaw(req.Reason, timeout)
+		if err != nil {
+			logger.Error("Failed thawing file systems", logger.Ctx{"err": err})
+		}
+
+		// Let the host know that the file systems didn't stay frozen for as long as it needed them.
+		d.filesystemsTimedOut = true
+	})
+
+	return response.SyncResponse(true, agentAPI.Filesystems{Frozen: frozen})
+}
+
+func filesystemsThawPost(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.FilesystemsThawPost{}
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = json.Unmarshal(buf, &req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	d.filesystemsMu.Lock()
+	defer d.filesystemsMu.Unlock()
+
+	if d.filesystemsTimedOut {
+		d.filesystemsTimedOut = false
+		return response.InternalError(fmt.Errorf("File systems were already thawed after the timeout"))
+	}
+
+	err = d.filesystemsThaw(req.Reason, filesystemsDefaultTimeout)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.SyncResponse(true, agentAPI.Filesystems{Frozen: []string{}})
+}
+
+// filesystemsThaw thaws the frozen file systems and runs the post-thaw hooks.
+// The caller must hold filesystemsMu.
+func (d *Daemon) filesystemsThaw(reason string, timeout time.Duration) error {
+	if d.filesystemsThawTimer == nil {
+		return nil
+	}
+
+	d.filesystemsThawTimer.Stop()
+	d.filesystemsThawTimer = nil
+
+	var errs []error
+
+	// Thaw in the reverse order of the freeze.
+	for i := len(d.filesystemsFrozen) - 1; i >= 0; i-- {
+		err := filesystemsIoctl(d.filesystemsFrozen[i], ioctlFIThaw)
+		if err != nil && !errors.Is(err, unix.EINVAL) {
+			errs = append(errs, fmt.Errorf("Failed thawing %q: %w", d.filesystemsFrozen[i], err))
+		}
+	}
+
+	d.filesystemsFrozen = nil
+
+	err := filesystemsRunHooks(filesystemsPostThawHooksPath, reason, timeout)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// filesystemsFreeze freezes all the writable block based file systems, returning their mount points.
+// On failure, the file systems which were already frozen get thawed.
+func filesystemsFreeze() ([]string, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	mountPoints, err := filesystemsList(f)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen := []string{}
+
+	// Freeze nested mounts before their parents.
+	for i := len(mountPoints) - 1; i >= 0; i-- {
+		err := filesystemsIoctl(mountPoints[i], ioctlFIFreeze)
+		if err != nil {
+			// Skip file systems which don't support being frozen.
+			if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.ENOTTY) {
+				continue
+			}
+
+			for j := len(frozen) - 1; j >= 0; j-- {
+				_ = filesystemsIoctl(frozen[j], ioctlFIThaw)
+			}
+
+			return nil, fmt.Errorf("Failed freezing %q: %w", mountPoints[i], err)
+		}
+
+		frozen = append(frozen, mountPoints[i])
+	}
+
+	return frozen, nil
+}
+
+// filesystemsList returns the mount points of the writable block based file systems from the mountinfo
+// content, in mount order. Only the first mount point of a given file system is returned.
+func filesystemsList(mountInfo io.Reader) ([]string, error) {
+	mountPoints := []string{}
+	devices := []string{}
+
+	scanner := bufio.NewScanner(mountInfo)
+	for scanner.Scan() {
+		// Format is: ID PARENT MAJOR:MINOR ROOT MOUNTPOINT OPTIONS [OPTIONAL...] - FSTYPE SOURCE SUPEROPTIONS
+		fields := strings.Fields(scanner.Text())
+		sep := slices.Index(fields, "-")
+		if sep < 6 || len(fields) < sep+3 {
+			continue
+		}
+
+		device := fields[2]
+		mountPoint := filesystemsUnescape(fields[4])
+		options := strings.Split(fields[5], ",")
+		fsType := fields[sep+1]
+		source := fields[sep+2]
+
+		if !strings.HasPrefix(source, "/dev/") || slices.Contains(options, "ro") {
+			continue
+		}
+
+		// Read-only by design.
+		if slices.Contains([]string{"iso9660", "squashfs"}, fsType) {
+			continue
+		}
+
+		// Bind mounts share the file system of the original mount.
+		if slices.Contains(devices, device) {
+			continue
+		}
+
+		devices = append(devices, device)
+		mountPoints = append(mountPoints, mountPoint)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return mountPoints, nil
+}
+
+// filesystemsUnescape decodes the octal escapes used in mountinfo for characters like spaces.
+func filesystemsUnescape(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			value, err := strconv.ParseUint(path[i+1:i+4], 8, 8)
+			if err == nil {
+				b.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+
+		b.WriteByte(path[i])
+	}
+
+	return b.String()
+}
+
+// filesystemsIoctl runs a freeze or thaw ioctl against the file system mounted at the given path.
+func filesystemsIoctl(mountPoint string, request uint) error {
+	fd, err := unix.Open(mountPoint, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = unix.Close(fd) }()
+
+	return unix.IoctlSetInt(fd, request, 0)
+}
+
+// filesystemsRunHooks runs the executables found in the hook directory in lexical order.
+func filesystemsRunHooks(path string, reason string, timeout time.Duration) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+
+		hookPath := filepath.Join(path, entry.Name())
+
+		output, err := exec.CommandContext(ctx, hookPath, reason).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("Failed running hook %q: %w (%s)", hookPath, err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	return nil
+}
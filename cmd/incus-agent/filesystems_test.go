@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesystemsList(t *testing.T) {
+	mountInfo := `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+23 22 0:5 / /dev rw,nosuid shared:2 - devtmpfs udev rw
+24 22 0:21 / /proc rw,nosuid,nodev,noexec shared:3 - proc proc rw
+25 22 8:2 / /var/lib/my\040data rw,relatime shared:4 - xfs /dev/sda2 rw
+26 22 8:1 /srv /mnt/srv rw,relatime shared:1 - ext4 /dev/sda1 rw
+27 22 8:3 / /boot ro,relatime shared:5 - ext4 /dev/sda3 ro
+28 22 11:0 / /media/cdrom rw,relatime shared:6 - iso9660 /dev/sr0 ro
+29 22 7:0 / /snap/core rw,relatime shared:7 - squashfs /dev/loop0 ro
+30 22 0:45 / /tmp rw,nosuid,nodev shared:8 - tmpfs tmpfs rw
+31 25 8:4 / /var/lib/my\040data/nested rw,relatime shared:9 - ext4 /dev/sda4 rw
+malformed line
+`
+
+	mountPoints, err := filesystemsList(strings.NewReader(mountInfo))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/", "/var/lib/my data", "/var/lib/my data/nested"}, mountPoints)
+}
+
+func TestFilesystemsUnescape(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/var/lib/data", want: "/var/lib/data"},
+		{path: `/mnt/my\040disk`, want: "/mnt/my disk"},
+		{path: `/mnt/tab\011and\012newline`, want: "/mnt/tab\tand\nnewline"},
+		{path: `/mnt/back\134slash`, want: `/mnt/back\slash`},
+		{path: `/mnt/not\08escape`, want: `/mnt/not\08escape`},
+		{path: `/mnt/trailing\04`, want: `/mnt/trailing\04`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, filesystemsUnescape(tt.path))
+		})
+	}
+}
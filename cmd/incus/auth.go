@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type cmdAuth struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuth) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("auth")
+	cmd.Short = i18n.G("Manage authentication")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage authentication`))
+
+	// Token
+	authTokenCmd := cmdAuthToken{global: c.global}
+	cmd.AddCommand(authTokenCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+type cmdAuthToken struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAuthToken) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("token")
+	cmd.Short = i18n.G("Manage authentication tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage authentication tokens
+
+Authentication tokens are short-lived bearer tokens restricted to a project
+and a set of entitlements. Clients use them when the INCUS_AUTH_TOKEN
+environment variable is set.`))
+
+	// Create
+	authTokenCreateCmd := cmdAuthTokenCreate{global: c.global, authToken: c}
+	cmd.AddCommand(authTokenCreateCmd.Command())
+
+	// Delete
+	authTokenDeleteCmd := cmdAuthTokenDelete{global: c.global, authToken: c}
+	cmd.AddCommand(authTokenDeleteCmd.Command())
+
+	// List
+	authTokenListCmd := cmdAuthTokenList{global: c.global, authToken: c}
+	cmd.AddCommand(authTokenListCmd.Command())
+
+	// Show
+	authTokenShowCmd := cmdAuthTokenShow{global: c.global, authToken: c}
+	cmd.AddCommand(authTokenShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdAuthTokenCreate struct {
+	global    *cmdGlobal
+	authToken *cmdAuthToken
+
+	flagDescription  string
+	flagEntitlements string
+	flagExpiry       string
+}
+
+func (c *cmdAuthTokenCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]"))
+	cmd.Short = i18n.G("Create an authentication token")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create an authentication token
+
+The token is restricted to the current project and to the given entitlements.
+It is only displayed once.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus auth token create --project frontend --expiry 1h --entitlements can_view,can_exec
+    Create a token valid for an hour, allowing to view and run commands in the instances of the frontend project.`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Token description")+"``")
+	cmd.Flags().StringVar(&c.flagEntitlements, "entitlements", "can_view", i18n.G("Comma-separated list of entitlements granted by the token")+"``")
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "1h", i18n.G("Token lifetime (e.g. 30m, 1h, 24h)")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthTokenCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	expiry, err := time.ParseDuration(c.flagExpiry)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid expiry %q: %w"), c.flagExpiry, err)
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	remoteName, _, err := c.global.conf.ParseRemote(remote)
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	token := api.AuthTokensPost{
+		Description:  c.flagDescription,
+		Entitlements: strings.Split(c.flagEntitlements, ","),
+		ExpiresAt:    time.Now().Add(expiry),
+	}
+
+	secret, err := remoteServer.CreateAuthToken(token)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(secret.Token)
+
+	return nil
+}
+
+// Delete.
+type cmdAuthTokenDelete struct {
+	global    *cmdGlobal
+	authToken *cmdAuthToken
+}
+
+func (c *cmdAuthTokenDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<id>"))
+	cmd.Aliases = []string{"rm", "revoke"}
+	cmd.Short = i18n.G("Revoke an authentication token")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Revoke an authentication token`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthTokenDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remoteName, id, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	err = remoteServer.DeleteAuthToken(id)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Authentication token %s revoked")+"\n", id)
+	}
+
+	return nil
+}
+
+// List.
+type cmdAuthTokenList struct {
+	global    *cmdGlobal
+	authToken *cmdAuthToken
+
+	flagFormat      string
+	flagAllProjects bool
+}
+
+func (c *cmdAuthTokenList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List authentication tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List authentication tokens`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.Flags().BoolVar(&c.flagAllProjects, "all-projects", false, i18n.G("Display authentication tokens from all projects"))
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthTokenList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	remoteName, _, err := c.global.conf.ParseRemote(remote)
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	var tokens []api.AuthToken
+	if c.flagAllProjects {
+		tokens, err = remoteServer.GetAuthTokensAllProjects()
+	} else {
+		tokens, err = remoteServer.GetAuthTokens()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, token := range tokens {
+		row := []string{token.ID, token.Description, strings.Join(token.Entitlements, ", "), token.ExpiresAt.Local().Format(dateLayout)}
+		if c.flagAllProjects {
+			row = append([]string{token.Project}, row...)
+		}
+
+		data = append(data, row)
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("ID"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("ENTITLEMENTS"),
+		i18n.G("EXPIRES AT"),
+	}
+
+	if c.flagAllProjects {
+		header = append([]string{i18n.G("PROJECT")}, header...)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, tokens)
+}
+
+// Show.
+type cmdAuthTokenShow struct {
+	global    *cmdGlobal
+	authToken *cmdAuthToken
+}
+
+func (c *cmdAuthTokenShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<id>"))
+	cmd.Short = i18n.G("Show authentication token details")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show authentication token details`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthTokenShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remoteName, id, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	token, err := remoteServer.GetAuthToken(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&token)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -105,6 +105,9 @@ func (c *cmdCluster) Command() *cobra.Command {
 	clusterRoleCmd := cmdClusterRole{global: c.global, cluster: c}
 	cmd.AddCommand(clusterRoleCmd.Command())
 
+	clusterRolloutCmd := cmdClusterRollout{global: c.global, cluster: c}
+	cmd.AddCommand(clusterRolloutCmd.Command())
+
 	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
 	cmd.Args = cobra.NoArgs
 	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+type cmdClusterRollout struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+}
+
+func (c *cmdClusterRollout) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rollout")
+	cmd.Short = i18n.G("Manage cluster rollouts")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage cluster rollouts
+
+A rollout evacuates each cluster member in turn, waits for it to be upgraded or restarted,
+restores it and checks that it's healthy before moving on to the next member.`))
+
+	// Start
+	clusterRolloutStartCmd := cmdClusterRolloutStart{global: c.global, cluster: c.cluster}
+	cmd.AddCommand(clusterRolloutStartCmd.Command())
+
+	// Show
+	clusterRolloutShowCmd := cmdClusterRolloutShow{global: c.global, cluster: c.cluster}
+	cmd.AddCommand(clusterRolloutShowCmd.Command())
+
+	// Pause
+	clusterRolloutPauseCmd := cmdClusterRolloutAction{global: c.global, cluster: c.cluster}
+	cmd.AddCommand(clusterRolloutPauseCmd.Command("pause", i18n.G("Pause the cluster rollout before the next member")))
+
+	// Resume
+	clusterRolloutResumeCmd := cmdClusterRolloutAction{global: c.global, cluster: c.cluster}
+	cmd.AddCommand(clusterRolloutResumeCmd.Command("resume", i18n.G("Resume a paused cluster rollout")))
+
+	// Continue
+	clusterRolloutContinueCmd := cmdClusterRolloutAction{global: c.global, cluster: c.cluster}
+	cmd.AddCommand(clusterRolloutContinueCmd.Command("continue", i18n.G("Continue a cluster rollout waiting on its hook")))
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Start.
+type cmdClusterRolloutStart struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+
+	flagMembers string
+	flagMode    string
+	flagWait    string
+	flagTimeout int64
+}
+
+func (c *cmdClusterRolloutStart) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("start", i18n.G("[<remote>:]"))
+	cmd.Short = i18n.G("Start a cluster rollout")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Start a cluster rollout
+
+The wait condition controls what happens once a member is evacuated:
+ - version: Wait for the member to report a new version (default)
+ - restart: Wait for the member to restart
+ - hook: Wait for the rollout to be continued with "incus cluster rollout continue"`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus cluster rollout start --wait=restart
+    Evacuate, restart and restore all cluster members one at a time.
+
+incus cluster rollout start --members=server01,server02 --mode=live-migrate
+    Roll out two members, live-migrating their instances.`))
+
+	cmd.Flags().StringVar(&c.flagMembers, "members", "", i18n.G("Comma separated list of members to roll out")+"``")
+	cmd.Flags().StringVar(&c.flagMode, "action", "", i18n.G("Force a particular evacuation action")+"``")
+	cmd.Flags().StringVar(&c.flagWait, "wait", "version", i18n.G("Condition to wait for on each member (version, restart or hook)")+"``")
+	cmd.Flags().Int64Var(&c.flagTimeout, "timeout", 0, i18n.G("How long to wait (in seconds) for each member")+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterRolloutStart) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	req := api.ClusterRolloutPost{
+		Mode:    c.flagMode,
+		Wait:    c.flagWait,
+		Timeout: c.flagTimeout,
+	}
+
+	if c.flagMembers != "" {
+		req.Members = util.SplitNTrimSpace(c.flagMembers, ",", -1, false)
+	}
+
+	op, err := resource.server.CreateClusterRollout(req)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Rolling out cluster: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done(i18n.G("Cluster rollout completed"))
+	return nil
+}
+
+// Show.
+type cmdClusterRolloutShow struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+}
+
+func (c *cmdClusterRolloutShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]"))
+	cmd.Short = i18n.G("Show the running cluster rollout")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the running cluster rollout`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterRolloutShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	rollout, _, err := resource.server.GetClusterRollout()
+	if err != nil {
+		return err
+	}
+
+	// Render as YAML.
+	data, err := yaml.Marshal(&rollout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+	return nil
+}
+
+// Pause, resume and continue.
+type cmdClusterRolloutAction struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+}
+
+func (c *cmdClusterRolloutAction) Command(action string, description string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage(action, i18n.G("[<remote>:]"))
+	cmd.Short = description
+	cmd.Long = cli.FormatSection(i18n.G("Description"), description)
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterRolloutAction) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	return resource.server.UpdateClusterRollout(api.ClusterRolloutPut{Action: cmd.Name()})
+}
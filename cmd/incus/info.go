@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -732,6 +734,10 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, remote config.Remote, nam
 			memoryInfo += fmt.Sprintf("    %s: %s\n", i18n.G("Swap (peak)"), units.GetByteSizeStringIEC(inst.State.Memory.SwapUsagePeak, 2))
 		}
 
+		if inst.State.Memory.Hotplugged != 0 {
+			memoryInfo += fmt.Sprintf("    %s: %s\n", i18n.G("Memory (hotplugged)"), units.GetByteSizeStringIEC(inst.State.Memory.Hotplugged, 2))
+		}
+
 		if memoryInfo != "" {
 			fmt.Printf("  %s\n", i18n.G("Memory usage:"))
 			fmt.Print(memoryInfo)
@@ -786,6 +792,36 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, remote config.Remote, nam
 			fmt.Printf("  %s\n", i18n.G("Network usage:"))
 			fmt.Print(networkInfo)
 		}
+
+		// Usage history
+		if d.HasExtension("instance_state_history") {
+			state, _, err := d.GetInstanceStateWithHistory(name, time.Hour)
+			if err != nil {
+				return err
+			}
+
+			if len(state.History) > 1 {
+				history := state.History
+				width := 60
+
+				cpu := historyRates(history, func(sample api.InstanceStateSample) int64 { return sample.CPUUsage })
+				for i := range cpu {
+					cpu[i] /= 1000000000
+				}
+
+				memory := historyValues(history, func(sample api.InstanceStateSample) int64 { return sample.MemoryUsage })
+				disk := historyValues(history, func(sample api.InstanceStateSample) int64 { return sample.DiskUsage })
+				received := historyRates(history, func(sample api.InstanceStateSample) int64 { return sample.NetworkBytesReceived })
+				sent := historyRates(history, func(sample api.InstanceStateSample) int64 { return sample.NetworkBytesSent })
+
+				fmt.Printf("  %s\n", i18n.G("Usage history (last hour):"))
+				fmt.Printf("    %s: %s (%s: %.2f)\n", i18n.G("CPU"), sparkline(cpu, width), i18n.G("peak"), slices.Max(cpu))
+				fmt.Printf("    %s: %s (%s: %s)\n", i18n.G("Memory"), sparkline(memory, width), i18n.G("peak"), units.GetByteSizeStringIEC(int64(slices.Max(memory)), 2))
+				fmt.Printf("    %s: %s (%s: %s)\n", i18n.G("Disk"), sparkline(disk, width), i18n.G("peak"), units.GetByteSizeStringIEC(int64(slices.Max(disk)), 2))
+				fmt.Printf("    %s: %s (%s: %s/s)\n", i18n.G("Network received"), sparkline(received, width), i18n.G("peak"), units.GetByteSizeString(int64(slices.Max(received)), 2))
+				fmt.Printf("    %s: %s (%s: %s/s)\n", i18n.G("Network sent"), sparkline(sent, width), i18n.G("peak"), units.GetByteSizeString(int64(slices.Max(sent)), 2))
+			}
+		}
 	}
 
 	// List snapshots
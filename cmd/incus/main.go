@@ -137,6 +137,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	adminCmd := cmdAdmin{global: &globalCmd}
 	app.AddCommand(adminCmd.Command())
 
+	// auth sub-command
+	authCmd := cmdAuth{global: &globalCmd}
+	app.AddCommand(authCmd.Command())
+
 	// cluster sub-command
 	clusterCmd := cmdCluster{global: &globalCmd}
 	app.AddCommand(clusterCmd.Command())
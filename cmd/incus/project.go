@@ -8,6 +8,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -76,6 +77,14 @@ func (c *cmdProject) Command() *cobra.Command {
 	projectGetInfo := cmdProjectInfo{global: c.global, project: c}
 	cmd.AddCommand(projectGetInfo.Command())
 
+	// Usage
+	projectUsageCmd := cmdProjectUsage{global: c.global, project: c}
+	cmd.AddCommand(projectUsageCmd.Command())
+
+	// Template
+	projectTemplateCmd := cmdProjectTemplate{global: c.global, project: c}
+	cmd.AddCommand(projectTemplateCmd.Command())
+
 	// Set default
 	projectSwitchCmd := cmdProjectSwitch{global: c.global, project: c}
 	cmd.AddCommand(projectSwitchCmd.Command())
@@ -96,6 +105,7 @@ type cmdProjectCreate struct {
 	project         *cmdProject
 	flagConfig      []string
 	flagDescription string
+	flagTemplate    string
 }
 
 func (c *cmdProjectCreate) Command() *cobra.Command {
@@ -108,10 +118,14 @@ func (c *cmdProjectCreate) Command() *cobra.Command {
     Create a project named p1
 
 incus project create p1 < config.yaml
-    Create a project named p1 with configuration from config.yaml`))
+    Create a project named p1 with configuration from config.yaml
+
+incus project create p1 --template team
+    Create a project named p1 from the team project template`))
 
 	cmd.Flags().StringArrayVarP(&c.flagConfig, "config", "c", nil, i18n.G("Config key/value to apply to the new project")+"``")
 	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Project description")+"``")
+	cmd.Flags().StringVar(&c.flagTemplate, "template", "", i18n.G("Project template to create the project from")+"``")
 
 	cmd.RunE = c.Run
 
@@ -181,6 +195,8 @@ func (c *cmdProjectCreate) Run(cmd *cobra.Command, args []string) error {
 		project.Description = c.flagDescription
 	}
 
+	project.Template = c.flagTemplate
+
 	err = resource.server.CreateProject(project)
 	if err != nil {
 		return err
@@ -1164,6 +1180,119 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, projectState)
 }
 
+// Usage.
+type cmdProjectUsage struct {
+	global  *cmdGlobal
+	project *cmdProject
+
+	flagFrom   string
+	flagTo     string
+	flagFormat string
+}
+
+func (c *cmdProjectUsage) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("usage", i18n.G("[<remote>:]<project>"))
+	cmd.Short = i18n.G("Get the resources consumed by a project")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Get the resources consumed by a project
+
+The CPU time, memory and storage allocation and network transfer of the project
+are reported over the requested period, which defaults to the last 30 days.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus project usage foo --from 2024-11-01 --to 2024-12-01 --format csv
+    Report the resources consumed by project "foo" in November 2024 as CSV.`))
+	cmd.Flags().StringVar(&c.flagFrom, "from", "", i18n.G("Start of the period (YYYY-MM-DD or RFC3339)")+"``")
+	cmd.Flags().StringVar(&c.flagTo, "to", "", i18n.G("End of the period (YYYY-MM-DD or RFC3339)")+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjects(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// parseTime parses a date or a RFC3339 timestamp.
+func (c *cmdProjectUsage) parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.DateOnly, value)
+	if err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+func (c *cmdProjectUsage) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project name"))
+	}
+
+	// Parse the period
+	to := time.Now()
+	if c.flagTo != "" {
+		to, err = c.parseTime(c.flagTo)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Invalid end of period: %w"), err)
+		}
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if c.flagFrom != "" {
+		from, err = c.parseTime(c.flagFrom)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Invalid start of period: %w"), err)
+		}
+	}
+
+	// Get the usage
+	projectUsage, err := resource.server.GetProjectUsage(resource.name, from, to)
+	if err != nil {
+		return err
+	}
+
+	// Render the output
+	data := [][]string{}
+	for _, v := range projectUsage.Resources {
+		data = append(data, []string{strings.ToUpper(v.Type), v.Pool, fmt.Sprintf("%.2f", v.Usage), v.Unit})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("RESOURCE"),
+		i18n.G("POOL"),
+		i18n.G("USAGE"),
+		i18n.G("UNIT"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, projectUsage)
+}
+
 // Get current project.
 type cmdProjectGetCurrent struct {
 	global  *cmdGlobal
@@ -74,6 +74,10 @@ func (c *cmdRemote) Command() *cobra.Command {
 	remoteRemoveCmd := cmdRemoteRemove{global: c.global, remote: c}
 	cmd.AddCommand(remoteRemoveCmd.Command())
 
+	// Renew certificate
+	remoteRenewCertificateCmd := cmdRemoteRenewCertificate{global: c.global, remote: c}
+	cmd.AddCommand(remoteRenewCertificateCmd.Command())
+
 	// Set default
 	remoteSwitchCmd := cmdRemoteSwitch{global: c.global, remote: c}
 	cmd.AddCommand(remoteSwitchCmd.Command())
@@ -1033,6 +1037,154 @@ func (c *cmdRemoteRemove) Run(cmd *cobra.Command, args []string) error {
 	return conf.SaveConfig(c.global.confPath)
 }
 
+// Renew certificate.
+type cmdRemoteRenewCertificate struct {
+	global *cmdGlobal
+	remote *cmdRemote
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdRemoteRenewCertificate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("renew-cert", i18n.G("[<remote>]"))
+	cmd.Short = i18n.G("Renew the client certificate trusted by a remote")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Renew the client certificate trusted by a remote
+
+A new client certificate is generated and atomically replaces the current one in the trust store
+of the remote, keeping its restrictions and projects.
+
+The new certificate is only used for this remote, other remotes keep using the current one.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemoteNames()
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run is used in the RunE field of the cobra.Command returned by Command.
+func (c *cmdRemoteRenewCertificate) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	name := conf.DefaultRemote
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	remote, ok := conf.Remotes[name]
+	if !ok {
+		return fmt.Errorf(i18n.G("Remote %s doesn't exist"), name)
+	}
+
+	if remote.Static {
+		return fmt.Errorf(i18n.G("Remote %s is static and cannot be modified"), name)
+	}
+
+	if remote.Protocol != "incus" || remote.AuthType == api.AuthenticationMethodOIDC {
+		return fmt.Errorf(i18n.G("Remote %s doesn't use a client certificate"), name)
+	}
+
+	if remote.Global {
+		err := conf.CopyGlobalCert(name, name)
+		if err != nil {
+			return err
+		}
+
+		remote.Global = false
+		conf.Remotes[name] = remote
+	}
+
+	d, err := conf.GetInstanceServer(name)
+	if err != nil {
+		return err
+	}
+
+	server, _, err := d.GetServer()
+	if err != nil {
+		return err
+	}
+
+	if server.Auth != "trusted" || server.AuthUserMethod != api.AuthenticationMethodTLS {
+		return fmt.Errorf(i18n.G("The client certificate isn't trusted by remote %s"), name)
+	}
+
+	// Generate the new certificate.
+	certPEM, keyPEM, err := localtls.GenerateMemCert(true, false)
+	if err != nil {
+		return err
+	}
+
+	dnam := conf.ConfigPath("clientcerts")
+	err = os.MkdirAll(dnam, 0750)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Could not create client cert dir"))
+	}
+
+	// Write the new certificate before renewing it so that it can't be lost.
+	certf := conf.ConfigPath("clientcerts", fmt.Sprintf("%s.crt", name))
+	keyf := conf.ConfigPath("clientcerts", fmt.Sprintf("%s.key", name))
+
+	err = os.WriteFile(certf+".new", certPEM, 0644)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed to create %q: %w"), certf+".new", err)
+	}
+
+	err = os.WriteFile(keyf+".new", keyPEM, 0600)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed to create %q: %w"), keyf+".new", err)
+	}
+
+	err = d.RenewCertificate(server.AuthUserName, api.CertificateRenewPost{Certificate: string(certPEM)})
+	if err != nil {
+		_ = os.Remove(certf + ".new")
+		_ = os.Remove(keyf + ".new")
+
+		return err
+	}
+
+	// Keep using the CA of the shared client certificate.
+	if !conf.HasRemoteClientCertificate(name) && util.PathExists(conf.ConfigPath("client.ca")) {
+		content, err := os.ReadFile(conf.ConfigPath("client.ca"))
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(conf.ConfigPath("clientcerts", fmt.Sprintf("%s.ca", name)), content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.Rename(keyf+".new", keyf)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(certf+".new", certf)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Client certificate renewed for remote %s")+"\n", name)
+	}
+
+	return conf.SaveConfig(c.global.confPath)
+}
+
 // Set default.
 type cmdRemoteSwitch struct {
 	global *cmdGlobal
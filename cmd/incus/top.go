@@ -17,6 +17,7 @@ import (
 	incus "github.com/lxc/incus/v6/client"
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/units"
 )
 
@@ -25,6 +26,9 @@ type topColumn struct {
 	Data func(displayData) string
 }
 
+// Width of the resource usage history columns.
+const topHistoryWidth = 20
+
 type cmdTop struct {
 	global  *cmdGlobal
 	targets []string
@@ -57,8 +61,10 @@ Column shorthand chars:
   D - disk usage
   e - Project name
   m - Memory usage
+  M - Memory usage over the last hour
   n - Instance name
-  u - CPU usage (in seconds)`))
+  u - CPU usage (in seconds)
+  U - CPU usage over the last hour`))
 
 	cmd.Flags().BoolVar(&c.flagAllProjects, "all-projects", false, i18n.G("Display instances from all projects"))
 	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultTopColumns, i18n.G("Columns")+"``")
@@ -79,6 +85,8 @@ func (c *cmdTop) parseColumns() ([]topColumn, error) {
 		'u': {i18n.G("CPU TIME(s)"), c.cpuUsageColumnData},
 		'm': {i18n.G("MEMORY"), c.memoryUsageColumnData},
 		'D': {i18n.G("DISK"), c.diskUsageColumnData},
+		'U': {i18n.G("CPU HISTORY"), c.cpuHistoryColumnData},
+		'M': {i18n.G("MEMORY HISTORY"), c.memoryHistoryColumnData},
 	}
 
 	columnList := strings.Split(c.flagColumns, ",")
@@ -131,6 +139,22 @@ func (c *cmdTop) diskUsageColumnData(dd displayData) string {
 	return ""
 }
 
+func (c *cmdTop) cpuHistoryColumnData(dd displayData) string {
+	if dd.history == nil {
+		return ""
+	}
+
+	return sparkline(historyRates(dd.history, func(sample api.InstanceStateSample) int64 { return sample.CPUUsage }), topHistoryWidth)
+}
+
+func (c *cmdTop) memoryHistoryColumnData(dd displayData) string {
+	if dd.history == nil {
+		return ""
+	}
+
+	return sparkline(historyValues(dd.history, func(sample api.InstanceStateSample) int64 { return sample.MemoryUsage }), topHistoryWidth)
+}
+
 // Run is a method of the cmdTop structure. It implements the logic to call `incus top`.
 // This function implements the `top` command. It queries the metrics API at (/1.0/metrics) and renders a list of
 // instances with their CPU, memory and disk usage columns.
@@ -333,6 +357,7 @@ type displayData struct {
 	cpuUsage     float64
 	memoryUsage  float64
 	diskUsage    float64
+	history      []api.InstanceStateSample
 }
 
 func sortBySortingType(data []displayData, sortingType sortType) {
@@ -391,6 +416,15 @@ func (c *cmdTop) updateDisplay(d incus.InstanceServer, refreshInterval time.Dura
 		return err
 	}
 
+	// Process the columns
+	columns, err := c.parseColumns()
+	if err != nil {
+		return err
+	}
+
+	// Only fetch the resource usage history when it's displayed.
+	withHistory := strings.ContainsAny(c.flagColumns, "UM") && d.HasExtension("instance_state_history")
+
 	data := []displayData{}
 	for projectName, names := range entries {
 		for _, currentName := range names {
@@ -402,12 +436,21 @@ func (c *cmdTop) updateDisplay(d incus.InstanceServer, refreshInterval time.Dura
 			diskTotal := metricSet.getMetricValue(filesystemSizeBytes, currentName)
 			diskFree := metricSet.getMetricValue(filesystemFreeBytes, currentName)
 
+			var history []api.InstanceStateSample
+			if withHistory {
+				state, _, err := d.UseProject(projectName).GetInstanceStateWithHistory(currentName, time.Hour)
+				if err == nil {
+					history = state.History
+				}
+			}
+
 			data = append(data, displayData{
 				project:      projectName,
 				instanceName: currentName,
 				cpuUsage:     cpuSeconds,
 				memoryUsage:  memoryTotal - memoryFree,
 				diskUsage:    diskTotal - diskFree,
+				history:      history,
 			})
 		}
 	}
@@ -415,12 +458,6 @@ func (c *cmdTop) updateDisplay(d incus.InstanceServer, refreshInterval time.Dura
 	// Perform sort operation
 	sortBySortingType(data, sortingType)
 
-	// Process the columns
-	columns, err := c.parseColumns()
-	if err != nil {
-		return err
-	}
-
 	dataFormatted := [][]string{}
 	for _, d := range data {
 		row := []string{}
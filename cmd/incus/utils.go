@@ -582,3 +582,76 @@ func removeElementsFromSlice[T comparable](list []T, elements ...T) []T {
 
 	return list
 }
+
+// sparkline renders values as a line of block characters, averaging them down to at most width characters.
+func sparkline(values []float64, width int) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+
+	// Average the values into buckets if there are too many of them.
+	if len(values) > width {
+		buckets := make([]float64, width)
+		for i := range buckets {
+			start := i * len(values) / width
+			end := (i + 1) * len(values) / width
+
+			sum := 0.0
+			for _, value := range values[start:end] {
+				sum += value
+			}
+
+			buckets[i] = sum / float64(end-start)
+		}
+
+		values = buckets
+	}
+
+	ticks := []rune("▁▂▃▄▅▆▇█")
+
+	low, high := values[0], values[0]
+	for _, value := range values {
+		low = min(low, value)
+		high = max(high, value)
+	}
+
+	var sb strings.Builder
+	for _, value := range values {
+		tick := 0
+		if high > low {
+			tick = int((value - low) / (high - low) * float64(len(ticks)-1))
+		}
+
+		sb.WriteRune(ticks[tick])
+	}
+
+	return sb.String()
+}
+
+// historyRates converts a counter from the resource usage history into per-second rates between consecutive samples.
+// Counter resets (such as on instance restart) are reported as a zero rate.
+func historyRates(history []api.InstanceStateSample, counter func(api.InstanceStateSample) int64) []float64 {
+	rates := []float64{}
+	for i := 1; i < len(history); i++ {
+		elapsed := history[i].Timestamp.Sub(history[i-1].Timestamp).Seconds()
+		delta := counter(history[i]) - counter(history[i-1])
+		if elapsed <= 0 || delta < 0 {
+			rates = append(rates, 0)
+			continue
+		}
+
+		rates = append(rates, float64(delta)/elapsed)
+	}
+
+	return rates
+}
+
+// historyValues extracts a gauge from the resource usage history.
+func historyValues(history []api.InstanceStateSample, gauge func(api.InstanceStateSample) int64) []float64 {
+	values := make([]float64, 0, len(history))
+	for _, sample := range history {
+		values = append(values, float64(gauge(sample)))
+	}
+
+	return values
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -82,3 +83,22 @@ func (s *utilsTestSuite) TestGetServerSupportedFilters() {
 	s.Equal([]string{"type=container"}, supportedFilters)
 	s.Equal([]string{"foo", "user.blah=a", "status=running,stopped"}, unsupportedFilters)
 }
+
+func (s *utilsTestSuite) TestSparkline() {
+	s.Equal("", sparkline(nil, 10))
+	s.Equal("▁▁▁", sparkline([]float64{5, 5, 5}, 10))
+	s.Equal("▁▄█", sparkline([]float64{0, 5, 10}, 10))
+	s.Equal("▁█", sparkline([]float64{0, 0, 10, 10}, 2))
+}
+
+func (s *utilsTestSuite) TestHistoryRates() {
+	now := time.Now()
+	history := []api.InstanceStateSample{
+		{Timestamp: now, CPUUsage: 0},
+		{Timestamp: now.Add(time.Minute), CPUUsage: 60},
+		{Timestamp: now.Add(2 * time.Minute), CPUUsage: 10},
+	}
+
+	rates := historyRates(history, func(sample api.InstanceStateSample) int64 { return sample.CPUUsage })
+	s.Equal([]float64{1, 0}, rates)
+}
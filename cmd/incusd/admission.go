@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/admission"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// admissionCheck runs the admission scriptlet, if configured, against an object being created or updated.
+// The configuration returned by the scriptlet replaces the one pointed to by config.
+// Requests between cluster members were already checked by the member which first received them.
+func admissionCheck(s *state.State, r *http.Request, req *apiScriptlet.AdmissionRequest, config *map[string]string) error {
+	if s.GlobalConfig.AdmissionScriptlet() == "" || isClusterMember(r) {
+		return nil
+	}
+
+	req.Identity = request.CreateRequestor(r)
+
+	newConfig, err := admission.AdmissionRun(logger.Log, req)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusForbidden) {
+			return err
+		}
+
+		return fmt.Errorf("Failed admission scriptlet: %w", err)
+	}
+
+	if newConfig != nil {
+		*config = newConfig
+	}
+
+	return nil
+}
+
+// admissionCheckFixed runs the admission scriptlet against an object whose configuration is applied as-is,
+// such as one restored from a backup or a snapshot. The scriptlet can still reject the request but any
+// configuration change it makes can't be honored and so also causes the request to be rejected.
+func admissionCheckFixed(s *state.State, r *http.Request, req *apiScriptlet.AdmissionRequest, config map[string]string) error {
+	newConfig := config
+
+	err := admissionCheck(s, r, req, &newConfig)
+	if err != nil {
+		return err
+	}
+
+	if !maps.Equal(newConfig, config) {
+		return api.StatusErrorf(http.StatusForbidden, "Admission scriptlet attempted to modify a configuration which is applied as-is")
+	}
+
+	return nil
+}
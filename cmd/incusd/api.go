@@ -426,6 +426,14 @@ func isClusterInternal(r *http.Request) bool {
 	return r.Header.Get("User-Agent") == clusterRequest.UserAgentClient
 }
 
+// isClusterMember returns whether the request was authenticated as coming from another cluster member.
+// Unlike isClusterNotification and isClusterInternal, this doesn't rely on the user agent set by the client.
+func isClusterMember(r *http.Request) bool {
+	protocol, _ := r.Context().Value(request.CtxProtocol).(string)
+
+	return protocol == "cluster"
+}
+
 type uiHttpDir struct {
 	http.FileSystem
 }
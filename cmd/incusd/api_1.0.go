@@ -16,12 +16,15 @@ import (
 	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
 	"github.com/lxc/incus/v6/internal/server/config"
 	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/node"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -41,7 +44,15 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	auditCmd,
+	authGroupCmd,
+	authGroupsCmd,
+	authIdentityCmd,
+	authIdentitiesCmd,
+	authTokenCmd,
+	authTokensCmd,
 	certificateCmd,
+	certificateRenewCmd,
 	certificatesCmd,
 	clusterCmd,
 	clusterGroupCmd,
@@ -49,6 +60,7 @@ var api10 = []APIEndpoint{
 	clusterNodeCmd,
 	clusterNodeStateCmd,
 	clusterNodesCmd,
+	clusterRolloutCmd,
 	clusterCertificateCmd,
 	instanceBackupCmd,
 	instanceBackupExportCmd,
@@ -65,6 +77,8 @@ var api10 = []APIEndpoint{
 	instanceMetadataTemplatesCmd,
 	instancesCmd,
 	instanceRebuildCmd,
+	instanceSessionCmd,
+	instanceSessionsCmd,
 	instanceSFTPCmd,
 	instanceSnapshotCmd,
 	instanceSnapshotsCmd,
@@ -111,6 +125,9 @@ var api10 = []APIEndpoint{
 	projectsCmd,
 	projectStateCmd,
 	projectAccessCmd,
+	projectUsageCmd,
+	projectTemplatesCmd,
+	projectTemplateCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
 	storagePoolsCmd,
@@ -227,7 +244,7 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 	// Get the authentication methods.
 	authMethods := []string{api.AuthenticationMethodTLS}
 
-	oidcIssuer, oidcClientID, _, _, _ := s.GlobalConfig.OIDCServer()
+	oidcIssuer, oidcClientID, _, _, _, _ := s.GlobalConfig.OIDCServer()
 	if oidcIssuer != "" && oidcClientID != "" {
 		authMethods = append(authMethods, api.AuthenticationMethodOIDC)
 	}
@@ -632,6 +649,13 @@ func doApi10Update(d *Daemon, r *http.Request, req api.ServerPut, patch bool) re
 			}
 		}
 
+		if nodeValues["storage.recordings_volume"] != "" && nodeValues["storage.recordings_volume"] != newNodeConfig.StorageRecordingsVolume() {
+			err := daemonStorageValidate(s, nodeValues["storage.recordings_volume"])
+			if err != nil {
+				return fmt.Errorf("Failed validation of %q: %w", "storage.recordings_volume", err)
+			}
+		}
+
 		if patch {
 			nodeChanged, err = newNodeConfig.Patch(nodeValues)
 		} else {
@@ -793,8 +817,10 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	lokiChanged := false
 	oidcChanged := false
 	openFGAChanged := false
+	otlpChanged := false
 	ovnChanged := false
 	ovsChanged := false
+	revocationChanged := false
 	syslogChanged := false
 
 	for key := range clusterChanged {
@@ -802,6 +828,12 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "acme.ca_url", "acme.domain":
 			acmeChanged = true
 
+		case "audit.syslog":
+			err := d.setupAuditSyslog(clusterConfig.AuditSyslog())
+			if err != nil {
+				return err
+			}
+
 		case "cluster.images_minimal_replica":
 			err := autoSyncImages(s.ShutdownCtx, s)
 			if err != nil {
@@ -820,6 +852,10 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 
 		case "core.proxy_http", "core.proxy_https", "core.proxy_ignore_hosts":
 			daemonConfigSetProxy(d, clusterConfig)
+			revocationChanged = true
+
+		case "core.trust_ca_crl_url", "core.trust_ca_ocsp":
+			revocationChanged = true
 
 		case "images.auto_update_interval", "images.remote_cache_expiry":
 			if !s.OS.MockMode {
@@ -832,9 +868,12 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "network.ovn.northbound_connection", "network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key":
 			ovnChanged = true
 
-		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim":
+		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim", "oidc.groups.claim":
 			oidcChanged = true
 
+		case "otlp.endpoint", "otlp.headers", "otlp.ca_cert":
+			otlpChanged = true
+
 		case "openfga.api.url", "openfga.api.token", "openfga.store.id":
 			openFGAChanged = true
 		}
@@ -920,6 +959,14 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	value, ok = nodeChanged["storage.recordings_volume"]
+	if ok {
+		err := daemonStorageMove(s, "recordings", value)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Apply larger changes.
 	if acmeChanged {
 		err := autoRenewCertificate(s.ShutdownCtx, d, true)
@@ -953,22 +1000,43 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 
 		if lokiURL == "" || lokiLoglevel == "" || len(lokiTypes) == 0 {
 			d.internalListener.RemoveHandler("loki")
+			d.internalListener.RemoveHandler("instance-logs")
+			d.auditLog.RemoveHandler("loki")
+
+			d.stopLoki()
 		} else {
 			err := d.setupLoki(lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes)
 			if err != nil {
 				return err
 			}
 		}
+
+		// Start or stop shipping instance logs accordingly.
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			return fmt.Errorf("Failed loading local instances: %w", err)
+		}
+
+		instanceLogsRegister(d, instances)
+	}
+
+	if otlpChanged {
+		otlpEndpoint, otlpHeaders, otlpCACert := clusterConfig.OTLPServer()
+
+		err := tracing.Setup(otlpEndpoint, otlpHeaders, otlpCACert, d.serverName)
+		if err != nil {
+			return fmt.Errorf("Failed setting up OpenTelemetry exporter: %w", err)
+		}
 	}
 
 	if oidcChanged {
-		oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := clusterConfig.OIDCServer()
+		oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim := clusterConfig.OIDCServer()
 
 		if oidcIssuer == "" || oidcClientID == "" {
 			d.oidcVerifier = nil
 		} else {
 			var err error
-			d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
+			d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim)
 			if err != nil {
 				return fmt.Errorf("Failed creating verifier: %w", err)
 			}
@@ -1004,6 +1072,15 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	if revocationChanged {
+		daemonConfigSetRevocation(d, clusterConfig)
+
+		err := localUtil.RefreshRevocationList(s.ShutdownCtx, s.Endpoints.NetworkCert().CA())
+		if err != nil {
+			logger.Warn("Failed refreshing certificate revocation list", logger.Ctx{"err": err})
+		}
+	}
+
 	// Compile and load the instance placement scriptlet.
 	value, ok = clusterChanged["instances.placement.scriptlet"]
 	if ok {
@@ -1013,6 +1090,24 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	// Compile and load the cluster re-balancing scriptlet.
+	value, ok = clusterChanged["cluster.rebalance.scriptlet"]
+	if ok {
+		err := scriptletLoad.ClusterRebalanceSet(value)
+		if err != nil {
+			return fmt.Errorf("Failed saving cluster re-balancing scriptlet: %w", err)
+		}
+	}
+
+	// Compile and load the admission scriptlet.
+	value, ok = clusterChanged["admission.scriptlet"]
+	if ok {
+		err := scriptletLoad.AdmissionSet(value)
+		if err != nil {
+			return fmt.Errorf("Failed saving admission scriptlet: %w", err)
+		}
+	}
+
 	// Setup the authorization scriptlet.
 	value, ok = clusterChanged["authorization.scriptlet"]
 	if ok {
@@ -1022,5 +1117,14 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	// Setup the built-in role based authorization.
+	value, ok = clusterChanged["authorization.rbac"]
+	if ok {
+		err := d.setupRBAC(util.IsTrue(value))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"runtime"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -324,6 +325,14 @@ func restoreClusterMember(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		// Start the dependencies (boot.depends_on) before the instances depending on them.
+		levels := instanceDependencyLevels(append(slices.Clone(localInstances), instances...), false)
+		for _, list := range [][]instance.Instance{localInstances, instances} {
+			sort.SliceStable(list, func(i, j int) bool {
+				return levels[list[i].Project().Name+"/"+list[i].Name()] < levels[list[j].Project().Name+"/"+list[j].Name()]
+			})
+		}
+
 		// Restart the local instances.
 		for _, inst := range localInstances {
 			// Don't start instances which were stopped by the user.
@@ -341,6 +350,12 @@ func restoreClusterMember(d *Daemon, r *http.Request) response.Response {
 			metadata["evacuation_progress"] = fmt.Sprintf("Starting %q in project %q", inst.Name(), inst.Project().Name)
 			_ = op.UpdateMetadata(metadata)
 
+			// Wait for the instances it depends on.
+			err = instanceDependenciesWait(s, inst)
+			if err != nil {
+				return fmt.Errorf("Failed to start instance %q: %w", inst.Name(), err)
+			}
+
 			// If configured for stateful stop, try restoring its state.
 			action := inst.CanMigrate()
 			if action == "stateful-stop" {
@@ -561,6 +576,12 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 			return err
 		}
 
+		// Apply the instance affinity and anti-affinity rules.
+		candidateMembers, err = instancePlacementApplyRules(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), candidateMembers)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
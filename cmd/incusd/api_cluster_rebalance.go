@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"time"
 
+	incus "github.com/lxc/incus/v6/client"
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -16,9 +19,13 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/scriptlet"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
 	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
 )
 
@@ -26,15 +33,21 @@ import (
 type ServerScore struct {
 	NodeInfo  db.NodeInfo
 	Resources *api.Resources
+	Usage     *ServerUsage
 	Score     uint8
 }
 
 // ServerUsage represents current server load.
 type ServerUsage struct {
-	MemoryUsage uint64
-	MemoryTotal uint64
-	CPUUsage    float64
-	CPUTotal    uint64
+	MemoryUsage  uint64
+	MemoryTotal  uint64
+	CPUUsage     float64
+	CPUTotal     uint64
+	StorageUsage uint64
+	StorageTotal uint64
+	Pressure     float64
+	NetworkUsage uint64
+	NetworkTotal uint64
 }
 
 // sortAndGroupByArch sorts servers by its score and groups them by cpu architecture.
@@ -57,27 +70,112 @@ func sortAndGroupByArch(servers []*ServerScore) map[string][]*ServerScore {
 	return result
 }
 
-// calculateScore calculates score for single server.
-func calculateScore(su *ServerUsage, au *ServerUsage) uint8 {
-	memoryUsage := su.MemoryUsage
-	memoryTotal := su.MemoryTotal
-	cpuUsage := su.CPUUsage
-	cpuTotal := su.CPUTotal
+// usagePercentage returns the usage as a percentage of the total, capped to 100.
+func usagePercentage(usage float64, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	return min(usage*100/total, 100)
+}
 
+// calculateScore calculates score for single server.
+func calculateScore(s *state.State, member string, res *api.Resources, su *ServerUsage, au *ServerUsage) (uint8, error) {
+	usage := *su
 	if au != nil {
-		memoryUsage += au.MemoryUsage
-		memoryTotal += au.MemoryTotal
-		cpuUsage += au.CPUUsage
-		cpuTotal += au.CPUTotal
+		usage.MemoryUsage += au.MemoryUsage
+		usage.MemoryTotal += au.MemoryTotal
+		usage.CPUUsage += au.CPUUsage
+		usage.CPUTotal += au.CPUTotal
+		usage.StorageUsage += au.StorageUsage
+		usage.StorageTotal += au.StorageTotal
+		usage.NetworkUsage += au.NetworkUsage
+		usage.NetworkTotal += au.NetworkTotal
+	}
+
+	components := &apiScriptlet.ClusterRebalanceUsage{
+		CPU:      usagePercentage(usage.CPUUsage, float64(usage.CPUTotal)),
+		Memory:   usagePercentage(float64(usage.MemoryUsage), float64(usage.MemoryTotal)),
+		Storage:  usagePercentage(float64(usage.StorageUsage), float64(usage.StorageTotal)),
+		Pressure: min(usage.Pressure, 100),
+		Network:  usagePercentage(float64(usage.NetworkUsage), float64(usage.NetworkTotal)),
+	}
+
+	// Let the scriptlet do the scoring if one is configured.
+	if s.GlobalConfig.ClusterRebalanceScriptlet() != "" {
+		score, err := scriptlet.ClusterRebalanceScoreRun(logger.Log, member, res, components)
+		if err != nil {
+			return 0, fmt.Errorf("Failed running cluster re-balancing scriptlet: %w", err)
+		}
+
+		return score, nil
+	}
+
+	// Otherwise compute the weighted average of the components.
+	values := map[string]float64{
+		"cpu":      components.CPU,
+		"memory":   components.Memory,
+		"storage":  components.Storage,
+		"pressure": components.Pressure,
+		"network":  components.Network,
+	}
+
+	var total float64
+	var totalWeight int64
+	for name, weight := range s.GlobalConfig.ClusterRebalanceWeights() {
+		total += values[name] * float64(weight)
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return 0, nil
+	}
+
+	return uint8(total / float64(totalWeight)), nil
+}
+
+// getServerUsage returns the current resource usage of a cluster member.
+func getServerUsage(client incus.InstanceServer, res *api.Resources) (*ServerUsage, error) {
+	su := &ServerUsage{
+		MemoryUsage: res.Memory.Used,
+		MemoryTotal: res.Memory.Total,
+		CPUUsage:    res.Load.Average1Min,
+		CPUTotal:    res.CPU.Total,
+	}
+
+	if res.Load.Pressure != nil {
+		su.Pressure = (res.Load.Pressure.CPU + res.Load.Pressure.Memory + res.Load.Pressure.IO) / 3
+	}
+
+	if res.Load.Network != nil {
+		su.NetworkUsage = res.Load.Network.Throughput
+		su.NetworkTotal = res.Load.Network.Capacity
+	}
+
+	// Only local storage pools matter as remote ones are shared by all members.
+	pools, err := client.GetStoragePools()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get storage pools: %w", err)
 	}
 
-	memoryScore := uint8(float64(memoryUsage) * 100 / float64(memoryTotal))
-	cpuScore := uint8((cpuUsage * 100) / float64(cpuTotal))
+	for _, pool := range pools {
+		if slices.Contains(storageDrivers.RemoteDriverNames(), pool.Driver) || pool.Status != api.StoragePoolStatusCreated {
+			continue
+		}
+
+		poolRes, err := client.GetStoragePoolResources(pool.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get resources for storage pool %q: %w", pool.Name, err)
+		}
+
+		su.StorageUsage += poolRes.Space.Used
+		su.StorageTotal += poolRes.Space.Total
+	}
 
-	return (memoryScore + cpuScore) / 2
+	return su, nil
 }
 
-// calculateServersScore calculates score based on memory and CPU usage for servers in cluster.
+// calculateServersScore calculates score based on resource usage for servers in cluster.
 func calculateServersScore(s *state.State, members []db.NodeInfo) (map[string][]*ServerScore, error) {
 	scores := []*ServerScore{}
 	for _, member := range members {
@@ -91,15 +189,17 @@ func calculateServersScore(s *state.State, members []db.NodeInfo) (map[string][]
 			return nil, fmt.Errorf("Failed to get resources for cluster member: %w", err)
 		}
 
-		su := &ServerUsage{
-			MemoryUsage: res.Memory.Used,
-			MemoryTotal: res.Memory.Total,
-			CPUUsage:    res.Load.Average1Min,
-			CPUTotal:    res.CPU.Total,
+		su, err := getServerUsage(clusterMember, res)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get usage for cluster member %q: %w", member.Name, err)
 		}
 
-		serverScore := calculateScore(su, nil)
-		scores = append(scores, &ServerScore{NodeInfo: member, Resources: res, Score: serverScore})
+		serverScore, err := calculateScore(s, member.Name, res, su, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		scores = append(scores, &ServerScore{NodeInfo: member, Resources: res, Usage: su, Score: serverScore})
 	}
 
 	return sortAndGroupByArch(scores), nil
@@ -188,26 +288,37 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 			}
 		}
 
+		// Check that the move doesn't go against the instance affinity and anti-affinity rules.
+		var candidates []db.NodeInfo
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			candidates, err = instancePlacementApplyRules(ctx, tx, inst.Project().Name, inst.Name(), inst.ExpandedConfig(), []db.NodeInfo{dstServer.NodeInfo, srcServer.NodeInfo})
+
+			return err
+		})
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return -1, fmt.Errorf("Failed to apply placement rules: %w", err)
+		}
+
+		if len(candidates) == 0 || candidates[0].Name != dstServer.NodeInfo.Name {
+			// The instance is better off on its current server.
+			continue
+		}
+
 		instances = append(instances, inst)
 	}
 
 	// Calculate current and target scores.
 	targetScore := (srcServer.Score + dstServer.Score) / 2
 	currentScore := dstServer.Score
-	targetServerUsage := &ServerUsage{
-		MemoryUsage: dstServer.Resources.Memory.Used,
-		MemoryTotal: dstServer.Resources.Memory.Total,
-		CPUUsage:    dstServer.Resources.Load.Average1Min,
-		CPUTotal:    dstServer.Resources.CPU.Total,
-	}
+	targetServerUsage := *dstServer.Usage
 
 	// Prepare the API client.
-	srcNode, err := cluster.Connect(srcServer.NodeInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	srcClient, err := cluster.Connect(srcServer.NodeInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
 	if err != nil {
 		return -1, fmt.Errorf("Failed to connect to cluster member: %w", err)
 	}
 
-	srcNode = srcNode.UseTarget(dstServer.NodeInfo.Name)
+	srcNode := srcClient.UseTarget(dstServer.NodeInfo.Name)
 
 	for _, inst := range instances {
 		if numOfMigrated >= maxToMigrate {
@@ -228,11 +339,32 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 
 		// Calculate impact of migration.
 		additionalUsage := &ServerUsage{
-			MemoryUsage: uint64(cpuUsage),
-			CPUUsage:    float64(memUsage),
+			MemoryUsage: uint64(memUsage),
+			CPUUsage:    float64(cpuUsage),
+		}
+
+		// Prefer the instance's actual usage over its configured limits.
+		instState, _, err := srcClient.GetInstanceState(inst.Name())
+		if err == nil {
+			if instState.Memory.Usage > 0 {
+				additionalUsage.MemoryUsage = uint64(instState.Memory.Usage)
+			}
+
+			// Local root disks get copied to the target as part of the migration.
+			pool, err := storagePools.LoadByInstance(s, inst)
+			if err == nil && !pool.Driver().Info().Remote {
+				rootDisk, ok := instState.Disk["root"]
+				if ok && rootDisk.Usage > 0 {
+					additionalUsage.StorageUsage = uint64(rootDisk.Usage)
+				}
+			}
+		}
+
+		expectedScore, err := calculateScore(s, dstServer.NodeInfo.Name, dstServer.Resources, &targetServerUsage, additionalUsage)
+		if err != nil {
+			return -1, err
 		}
 
-		expectedScore := calculateScore(targetServerUsage, additionalUsage)
 		if expectedScore >= targetScore {
 			// Skip the instance as it would have too big an impact.
 			continue
@@ -265,6 +397,7 @@ func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *Ser
 		currentScore = expectedScore
 		targetServerUsage.MemoryUsage += additionalUsage.MemoryUsage
 		targetServerUsage.CPUUsage += additionalUsage.CPUUsage
+		targetServerUsage.StorageUsage += additionalUsage.StorageUsage
 	}
 
 	return numOfMigrated, nil
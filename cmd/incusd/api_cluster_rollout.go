@@ -0,0 +1,787 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	incus "github.com/lxc/incus/v6/client"
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// clusterRolloutDefaultTimeout is the default time (in seconds) to wait for a member to meet the wait condition.
+const clusterRolloutDefaultTimeout = 3600
+
+// clusterRolloutPollInterval is how often the state of a member is checked while waiting on it.
+const clusterRolloutPollInterval = 5 * time.Second
+
+var clusterRolloutCmd = APIEndpoint{
+	Path: "cluster/rollout",
+
+	Get:  APIEndpointAction{Handler: clusterRolloutGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: clusterRolloutPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:  APIEndpointAction{Handler: clusterRolloutPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// clusterRolloutState tracks the rollout coordinated by this member.
+type clusterRolloutState struct {
+	mu       sync.Mutex
+	rollout  api.ClusterRollout
+	op       *operations.Operation
+	paused   bool
+	proceed  bool
+	notify   chan struct{}
+	finished bool
+}
+
+// clusterRolloutCurrent is the rollout coordinated by this member (if any).
+var clusterRolloutCurrent *clusterRolloutState
+var clusterRolloutMu sync.Mutex
+
+// signal wakes up the rollout if it's waiting on a state change.
+func (r *clusterRolloutState) signal() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// render returns a copy of the current rollout state.
+func (r *clusterRolloutState) render() api.ClusterRollout {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rollout := r.rollout
+	rollout.Members = slices.Clone(r.rollout.Members)
+
+	return rollout
+}
+
+// setStatus updates the overall rollout status and the operation metadata.
+func (r *clusterRolloutState) setStatus(status string) {
+	r.mu.Lock()
+	r.rollout.Status = status
+	r.mu.Unlock()
+
+	r.updateMetadata("")
+}
+
+// setMember updates the status of a member and the operation metadata.
+func (r *clusterRolloutState) setMember(index int, status string, message string) {
+	r.mu.Lock()
+	r.rollout.Members[index].Status = status
+	r.rollout.Members[index].Message = message
+	name := r.rollout.Members[index].Name
+	total := len(r.rollout.Members)
+	r.mu.Unlock()
+
+	r.updateMetadata(fmt.Sprintf("%s: %s (%d/%d)", name, status, index+1, total))
+}
+
+// updateMetadata refreshes the operation metadata.
+func (r *clusterRolloutState) updateMetadata(progress string) {
+	if r.op == nil {
+		return
+	}
+
+	metadata := map[string]any{"rollout": r.render()}
+	if progress != "" {
+		metadata["rollout_progress"] = progress
+	}
+
+	_ = r.op.UpdateMetadata(metadata)
+}
+
+// waitIfPaused blocks while the rollout is paused.
+func (r *clusterRolloutState) waitIfPaused(ctx context.Context) error {
+	for {
+		r.mu.Lock()
+		paused := r.paused
+		r.mu.Unlock()
+
+		if !paused {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.notify:
+		}
+	}
+}
+
+// clusterRolloutRemoteAddress returns the address of the remote member coordinating a rollout (if any).
+func clusterRolloutRemoteAddress(ctx context.Context, s *state.State) (string, error) {
+	var address string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		ops, err := dbCluster.GetOperations(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, op := range ops {
+			if op.Type != operationtype.ClusterRollout {
+				continue
+			}
+
+			address = op.NodeAddress
+			if address == s.LocalConfig.ClusterAddress() {
+				address = ""
+			}
+
+			return nil
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed loading operations: %w", err)
+	}
+
+	return address, nil
+}
+
+// clusterRolloutGetLocal returns the rollout coordinated by this member if it's still running.
+func clusterRolloutGetLocal() *clusterRolloutState {
+	clusterRolloutMu.Lock()
+	defer clusterRolloutMu.Unlock()
+
+	if clusterRolloutCurrent == nil {
+		return nil
+	}
+
+	clusterRolloutCurrent.mu.Lock()
+	finished := clusterRolloutCurrent.finished
+	clusterRolloutCurrent.mu.Unlock()
+
+	if finished {
+		return nil
+	}
+
+	return clusterRolloutCurrent
+}
+
+// clusterRolloutForward forwards the request to the member coordinating the rollout if not local.
+func clusterRolloutForward(s *state.State, r *http.Request) response.Response {
+	if clusterRolloutGetLocal() != nil {
+		return nil
+	}
+
+	address, err := clusterRolloutRemoteAddress(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if address == "" {
+		return nil
+	}
+
+	client, err := cluster.Connect(address, s.Endpoints.NetworkCert(), s.ServerCert(), r, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.ForwardedResponse(client, r)
+}
+
+// swagger:operation GET /1.0/cluster/rollout cluster cluster_rollout_get
+//
+//	Get the cluster rollout
+//
+//	Gets the state of the currently running cluster rollout.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Cluster rollout
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterRollout"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRolloutGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(fmt.Errorf("This server isn't clustered"))
+	}
+
+	resp := clusterRolloutForward(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	rollout := clusterRolloutGetLocal()
+	if rollout == nil {
+		return response.NotFound(fmt.Errorf("No cluster rollout is currently running"))
+	}
+
+	return response.SyncResponse(true, rollout.render())
+}
+
+// swagger:operation POST /1.0/cluster/rollout cluster cluster_rollout_post
+//
+//	Start a cluster rollout
+//
+//	Evacuates, waits on and restores each cluster member in turn.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: rollout
+//	    description: Cluster rollout request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterRolloutPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRolloutPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(fmt.Errorf("This server isn't clustered"))
+	}
+
+	// Parse the request.
+	req := api.ClusterRolloutPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Validate the request.
+	if req.Mode != "" {
+		// Use the validator from the instance logic.
+		validator := internalInstance.InstanceConfigKeysAny["cluster.evacuate"]
+		err = validator(req.Mode)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	if req.Wait == "" {
+		req.Wait = "version"
+	}
+
+	if !slices.Contains([]string{"version", "restart", "hook"}, req.Wait) {
+		return response.BadRequest(fmt.Errorf("Invalid wait condition %q", req.Wait))
+	}
+
+	if req.Timeout < 0 {
+		return response.BadRequest(fmt.Errorf("Invalid timeout %d", req.Timeout))
+	} else if req.Timeout == 0 {
+		req.Timeout = clusterRolloutDefaultTimeout
+	}
+
+	// Validate the members.
+	var members []db.NodeInfo
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		allMembers, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		if len(req.Members) == 0 {
+			members = allMembers
+			return nil
+		}
+
+		for _, name := range req.Members {
+			idx := slices.IndexFunc(allMembers, func(member db.NodeInfo) bool { return member.Name == name })
+			if idx < 0 {
+				return api.StatusErrorf(http.StatusBadRequest, "Cluster member %q doesn't exist", name)
+			}
+
+			members = append(members, allMembers[idx])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, member := range members {
+		if member.State != db.ClusterMemberStateCreated {
+			return response.BadRequest(fmt.Errorf("Cluster member %q is evacuated or pending", member.Name))
+		}
+
+		if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			return response.BadRequest(fmt.Errorf("Cluster member %q is offline", member.Name))
+		}
+	}
+
+	if len(members) == 1 && members[0].Name == s.ServerName {
+		return response.BadRequest(fmt.Errorf("A member can't roll out itself, send the request to another cluster member"))
+	}
+
+	clusterRolloutSortMembers(members, s.ServerName)
+
+	req.Members = make([]string, 0, len(members))
+	rollout := &clusterRolloutState{
+		rollout: api.ClusterRollout{
+			Coordinator: s.ServerName,
+			Status:      "running",
+			Members:     make([]api.ClusterRolloutMember, 0, len(members)),
+		},
+		notify: make(chan struct{}, 1),
+	}
+
+	for _, member := range members {
+		req.Members = append(req.Members, member.Name)
+		rollout.rollout.Members = append(rollout.rollout.Members, api.ClusterRolloutMember{Name: member.Name, Status: "pending"})
+	}
+
+	rollout.rollout.Options = req
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	run := func(op *operations.Operation) error {
+		defer cancel()
+
+		err := clusterRolloutRun(ctx, s, rollout)
+
+		rollout.mu.Lock()
+		rollout.finished = true
+		rollout.mu.Unlock()
+
+		if err != nil {
+			logger.Error("Failed cluster rollout", logger.Ctx{"err": err})
+			return err
+		}
+
+		return nil
+	}
+
+	onCancel := func(op *operations.Operation) error {
+		cancel()
+		return nil
+	}
+
+	// Only allow a single rollout at a time, which the database enforces when registering the operation.
+	// A member handing off the remainder of its own rollout is allowed through.
+	var op *operations.Operation
+	if isClusterNotification(r) && isClusterMember(r) {
+		op, err = operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterRollout, nil, nil, run, onCancel, nil, r)
+	} else {
+		op, err = operations.OperationCreateExclusive(s, "", operations.OperationClassTask, operationtype.ClusterRollout, nil, nil, run, onCancel, nil, r)
+	}
+
+	if err != nil {
+		cancel()
+
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return response.Conflict(fmt.Errorf("A cluster rollout is already running"))
+		}
+
+		return response.SmartError(err)
+	}
+
+	rollout.op = op
+	rollout.rollout.Operation = op.ID()
+
+	clusterRolloutMu.Lock()
+	clusterRolloutCurrent = rollout
+	clusterRolloutMu.Unlock()
+
+	return operations.OperationResponse(op)
+}
+
+// clusterRolloutSortMembers orders the members to roll out, processing the local member last so it can hand off to
+// a member which was already rolled out.
+func clusterRolloutSortMembers(members []db.NodeInfo, localName string) {
+	slices.SortStableFunc(members, func(a db.NodeInfo, b db.NodeInfo) int {
+		if a.Name == localName && b.Name != localName {
+			return 1
+		}
+
+		if b.Name == localName && a.Name != localName {
+			return -1
+		}
+
+		return 0
+	})
+}
+
+// swagger:operation PUT /1.0/cluster/rollout cluster cluster_rollout_put
+//
+//	Control the cluster rollout
+//
+//	Pauses, resumes or continues the currently running cluster rollout.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: rollout
+//	    description: Cluster rollout action
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterRolloutPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRolloutPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(fmt.Errorf("This server isn't clustered"))
+	}
+
+	resp := clusterRolloutForward(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.ClusterRolloutPut{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	rollout := clusterRolloutGetLocal()
+	if rollout == nil {
+		return response.NotFound(fmt.Errorf("No cluster rollout is currently running"))
+	}
+
+	rollout.mu.Lock()
+	switch req.Action {
+	case "pause":
+		rollout.paused = true
+		rollout.rollout.Status = "paused"
+	case "resume":
+		rollout.paused = false
+		rollout.rollout.Status = "running"
+	case "continue":
+		rollout.proceed = true
+	default:
+		rollout.mu.Unlock()
+		return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
+	}
+
+	rollout.mu.Unlock()
+
+	rollout.signal()
+	rollout.updateMetadata("")
+
+	return response.EmptySyncResponse
+}
+
+// clusterRolloutRun rolls out each member in turn.
+func clusterRolloutRun(ctx context.Context, s *state.State, rollout *clusterRolloutState) error {
+	options := rollout.render().Options
+
+	for i, name := range options.Members {
+		err := rollout.waitIfPaused(ctx)
+		if err != nil {
+			rollout.setMember(i, "failed", err.Error())
+			rollout.setStatus("failed")
+			return err
+		}
+
+		if name == s.ServerName {
+			err = clusterRolloutHandOff(s, rollout, i)
+		} else {
+			err = clusterRolloutMember(ctx, s, rollout, i)
+		}
+
+		if err != nil {
+			rollout.setMember(i, "failed", err.Error())
+			rollout.setStatus("failed")
+			return fmt.Errorf("Failed rolling out cluster member %q: %w", name, err)
+		}
+	}
+
+	rollout.setStatus("completed")
+
+	return nil
+}
+
+// clusterRolloutConnect returns the database record of a member along with a client connected to it.
+func clusterRolloutConnect(ctx context.Context, s *state.State, name string) (*db.NodeInfo, incus.InstanceServer, error) {
+	var member db.NodeInfo
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		member, err = tx.GetNodeByName(ctx, name)
+
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed getting cluster member: %w", err)
+	}
+
+	client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return &member, nil, fmt.Errorf("Failed connecting to cluster member: %w", err)
+	}
+
+	return &member, client, nil
+}
+
+// clusterRolloutMember evacuates a member, waits for the configured condition, restores it and checks its health.
+func clusterRolloutMember(ctx context.Context, s *state.State, rollout *clusterRolloutState, index int) error {
+	options := rollout.render().Options
+	name := options.Members[index]
+
+	// Record the current state of the member.
+	member, client, err := clusterRolloutConnect(ctx, s, name)
+	if err != nil {
+		return err
+	}
+
+	oldVersion := member.Version()
+
+	oldServer, _, err := client.GetServer()
+	if err != nil {
+		return fmt.Errorf("Failed getting server information: %w", err)
+	}
+
+	rollout.mu.Lock()
+	rollout.rollout.Members[index].OldVersion = oldServer.Environment.ServerVersion
+	rollout.proceed = false
+	rollout.mu.Unlock()
+
+	// Evacuate the member.
+	rollout.setMember(index, "evacuating", "")
+
+	op, err := client.UpdateClusterMemberState(name, api.ClusterMemberStatePost{Action: "evacuate", Mode: options.Mode})
+	if err != nil {
+		return fmt.Errorf("Failed evacuating: %w", err)
+	}
+
+	err = op.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed evacuating: %w", err)
+	}
+
+	// Wait for the member to be upgraded, restarted or for the hook to tell us to proceed.
+	switch options.Wait {
+	case "version":
+		rollout.setMember(index, "waiting", "Waiting for the member to report a new version")
+	case "restart":
+		rollout.setMember(index, "waiting", "Waiting for the member to restart")
+	case "hook":
+		rollout.setMember(index, "waiting", "Waiting for the rollout to be continued")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(options.Timeout)*time.Second)
+	defer cancel()
+
+	newServer, err := clusterRolloutWait(timeoutCtx, s, rollout, name, options.Wait, oldVersion, oldServer)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("Timed out waiting for the member")
+		}
+
+		return err
+	}
+
+	rollout.mu.Lock()
+	rollout.rollout.Members[index].NewVersion = newServer.Environment.ServerVersion
+	rollout.mu.Unlock()
+
+	// Restore the member (reconnecting as it may have restarted).
+	rollout.setMember(index, "restoring", "")
+
+	_, client, err = clusterRolloutConnect(ctx, s, name)
+	if err != nil {
+		return err
+	}
+
+	op, err = client.UpdateClusterMemberState(name, api.ClusterMemberStatePost{Action: "restore"})
+	if err != nil {
+		return fmt.Errorf("Failed restoring: %w", err)
+	}
+
+	err = op.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed restoring: %w", err)
+	}
+
+	// Check the health of the member before moving on.
+	rollout.setMember(index, "checking", "")
+
+	err = clusterRolloutCheckHealth(ctx, s, name)
+	if err != nil {
+		return err
+	}
+
+	rollout.setMember(index, "done", "")
+
+	return nil
+}
+
+// clusterRolloutWait waits for the member to meet the wait condition and returns its new server information.
+func clusterRolloutWait(ctx context.Context, s *state.State, rollout *clusterRolloutState, name string, wait string, oldVersion [2]int, oldServer *api.Server) (*api.Server, error) {
+	ticker := time.NewTicker(clusterRolloutPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		case <-rollout.notify:
+		}
+
+		if wait == "hook" {
+			rollout.mu.Lock()
+			proceed := rollout.proceed
+			rollout.mu.Unlock()
+
+			if !proceed {
+				continue
+			}
+		}
+
+		// Skip members that aren't reporting through heartbeats.
+		member, client, err := clusterRolloutConnect(ctx, s, name)
+		if err != nil || member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			continue
+		}
+
+		newServer, _, err := client.GetServer()
+		if err != nil {
+			continue
+		}
+
+		versionChanged := member.Version() != oldVersion || newServer.Environment.ServerVersion != oldServer.Environment.ServerVersion
+
+		switch wait {
+		case "version":
+			if !versionChanged {
+				continue
+			}
+
+		case "restart":
+			if !versionChanged && newServer.Environment.ServerPid == oldServer.Environment.ServerPid {
+				continue
+			}
+		}
+
+		return newServer, nil
+	}
+}
+
+// clusterRolloutCheckHealth waits for a restored member to be reported as online.
+func clusterRolloutCheckHealth(ctx context.Context, s *state.State, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	ticker := time.NewTicker(clusterRolloutPollInterval)
+	defer ticker.Stop()
+
+	for {
+		_, client, err := clusterRolloutConnect(ctx, s, name)
+		if err == nil {
+			member, _, err := client.GetClusterMember(name)
+			if err == nil && member.Status == "Online" {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Cluster member isn't reported as online after restore")
+		case <-ticker.C:
+		}
+	}
+}
+
+// clusterRolloutHandOff asks an already rolled out member to take care of rolling out the local member.
+func clusterRolloutHandOff(s *state.State, rollout *clusterRolloutState, index int) error {
+	current := rollout.render()
+
+	// Find a member which was already rolled out.
+	var target string
+	for _, member := range current.Members {
+		if member.Status == "done" {
+			target = member.Name
+			break
+		}
+	}
+
+	if target == "" {
+		return fmt.Errorf("No rolled out member available to hand off to")
+	}
+
+	_, client, err := clusterRolloutConnect(context.Background(), s, target)
+	if err != nil {
+		return err
+	}
+
+	req := current.Options
+	req.Members = []string{s.ServerName}
+
+	op, err := client.CreateClusterRollout(req)
+	if err != nil {
+		return fmt.Errorf("Failed handing off to %q: %w", target, err)
+	}
+
+	opAPI := op.Get()
+	rollout.setMember(index, "handed-off", fmt.Sprintf("Continued by %q in operation %s", target, opAPI.ID))
+
+	return nil
+}
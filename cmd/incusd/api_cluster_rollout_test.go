@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+)
+
+func TestClusterRolloutSortMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  []string
+		local    string
+		expected []string
+	}{
+		{"Local member first", []string{"a", "b", "c"}, "a", []string{"b", "c", "a"}},
+		{"Local member in the middle", []string{"a", "b", "c"}, "b", []string{"a", "c", "b"}},
+		{"Local member last", []string{"a", "b", "c"}, "c", []string{"a", "b", "c"}},
+		{"Local member not rolled out", []string{"a", "b", "c"}, "d", []string{"a", "b", "c"}},
+		{"Single member", []string{"a"}, "a", []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := make([]db.NodeInfo, 0, len(tt.members))
+			for _, name := range tt.members {
+				members = append(members, db.NodeInfo{Name: name})
+			}
+
+			clusterRolloutSortMembers(members, tt.local)
+
+			names := make([]string, 0, len(members))
+			for _, member := range members {
+				names = append(names, member.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
@@ -63,6 +63,7 @@ var apiInternal = []APIEndpoint{
 	internalImageRefreshCmd,
 	internalRAFTSnapshotCmd,
 	internalRebalanceLoadCmd,
+	internalAuthRefreshCmd,
 	internalReadyCmd,
 	internalShutdownCmd,
 	internalSQLCmd,
@@ -95,6 +96,12 @@ var internalRebalanceLoadCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: internalRebalanceLoad, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
 }
 
+var internalAuthRefreshCmd = APIEndpoint{
+	Path: "auth/refresh",
+
+	Post: APIEndpointAction{Handler: internalAuthRefresh, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
 var internalSQLCmd = APIEndpoint{
 	Path: "sql",
 
@@ -1118,6 +1125,13 @@ func internalBGPState(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, s.BGP.Debug())
 }
 
+// internalAuthRefresh drops the cached authorization groups following a change on another cluster member.
+func internalAuthRefresh(d *Daemon, r *http.Request) response.Response {
+	d.authGroupsCache.invalidate()
+
+	return response.EmptySyncResponse
+}
+
 func internalRebalanceLoad(d *Daemon, r *http.Request) response.Response {
 	err := autoRebalanceCluster(context.TODO(), d)
 	if err != nil {
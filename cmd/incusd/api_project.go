@@ -40,7 +40,7 @@ var projectsCmd = APIEndpoint{
 	Path: "projects",
 
 	Get:  APIEndpointAction{Handler: projectsGet, AccessHandler: allowAuthenticated},
-	Post: APIEndpointAction{Handler: projectsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateProjects)},
+	Post: APIEndpointAction{Handler: projectsPost, AccessHandler: allowAuthenticated},
 }
 
 var projectCmd = APIEndpoint{
@@ -334,11 +334,70 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 	// Parse the request.
 	project := api.ProjectsPost{}
 
-	// Set default features.
+	err := json.NewDecoder(r.Body).Decode(&project)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = projectValidateName(project.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Identities without the permission to create projects may only create them from a self-service template.
+	selfService := false
+	err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectServer(), auth.EntitlementCanCreateProjects)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusForbidden) {
+			return response.SmartError(err)
+		}
+
+		if project.Template == "" || !slices.Contains(s.GlobalConfig.ProjectsTemplatesSelfService(), project.Template) {
+			return response.SmartError(err)
+		}
+
+		if len(project.Config) > 0 {
+			return response.Forbidden(fmt.Errorf("Projects created from a self-service template can't override its configuration"))
+		}
+
+		err = projectCheckSelfServiceLimit(r.Context(), s, r)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		selfService = true
+	}
+
 	if project.Config == nil {
 		project.Config = map[string]string{}
 	}
 
+	// Apply the template configuration.
+	var template *api.ProjectTemplate
+	if project.Template != "" {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			template, err = tx.GetProjectTemplate(ctx, project.Template)
+
+			return err
+		})
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading project template %q: %w", project.Template, err))
+		}
+
+		for key, value := range template.Config {
+			_, ok := project.Config[key]
+			if !ok {
+				project.Config[key] = value
+			}
+		}
+
+		if project.Description == "" {
+			project.Description = template.Description
+		}
+	}
+
+	// Set default features.
 	for featureName, featureInfo := range cluster.ProjectFeatures {
 		_, ok := project.Config[featureName]
 		if !ok && featureInfo.DefaultEnabled {
@@ -346,19 +405,13 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&project)
+	// Validate the configuration.
+	err = projectValidateConfig(s, project.Config)
 	if err != nil {
 		return response.BadRequest(err)
 	}
 
-	// Quick checks.
-	err = projectValidateName(project.Name)
-	if err != nil {
-		return response.BadRequest(err)
-	}
-
-	// Validate the configuration.
-	err = projectValidateConfig(s, project.Config)
+	err = projecthelpers.ValidateNetworkCountLimits(&api.Project{Name: project.Name, ProjectPut: project.ProjectPut})
 	if err != nil {
 		return response.BadRequest(err)
 	}
@@ -400,6 +453,18 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		logger.Error("Failed to add project to authorizer", logger.Ctx{"name": project.Name, "error": err})
 	}
 
+	if template != nil {
+		err = projectCreateFromTemplate(r.Context(), s, r, project.Name, template, selfService)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed creating project %q: %w", project.Name, err))
+		}
+
+		// Self-service projects come with a new authorization group.
+		if selfService {
+			authGroupsChanged(d)
+		}
+	}
+
 	requestor := request.CreateRequestor(r)
 	lc := lifecycle.ProjectCreated.Event(project.Name, requestor, nil)
 	s.Events.SendLifecycle(project.Name, lc)
@@ -573,6 +638,11 @@ func projectPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = projectCheckRestrictionsChange(r.Context(), s, r, project, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
@@ -689,12 +759,38 @@ func projectPatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = projectCheckRestrictionsChange(r.Context(), s, r, project, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
 	return projectChange(r.Context(), s, project, req)
 }
 
+// projectCheckRestrictionsChange returns an error if the request changes the restrictions or limits of the project
+// and the requestor isn't allowed to edit the server. Those keys protect the server from the project users, so
+// project administrators may not change them.
+func projectCheckRestrictionsChange(ctx context.Context, s *state.State, r *http.Request, project *api.Project, req api.ProjectPut) error {
+	key := projecthelpers.RestrictionsChanged(project.Config, req.Config)
+	if key == "" {
+		return nil
+	}
+
+	err := s.Authorizer.CheckPermission(ctx, r, auth.ObjectServer(), auth.EntitlementCanEdit)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusForbidden) {
+			return api.StatusErrorf(http.StatusForbidden, "Changing %q requires permission to edit the server", key)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 // Common logic between PUT and PATCH.
 func projectChange(ctx context.Context, s *state.State, project *api.Project, req api.ProjectPut) response.Response {
 	// Make a list of config keys that have changed.
@@ -754,6 +850,11 @@ func projectChange(ctx context.Context, s *state.State, project *api.Project, re
 		return response.BadRequest(err)
 	}
 
+	err = projecthelpers.ValidateNetworkCountLimits(&api.Project{Name: project.Name, ProjectPut: req})
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	// Update the database entry.
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		err := projecthelpers.AllowProjectUpdate(tx, project.Name, req.Config, configChanged)
@@ -883,7 +984,29 @@ func projectPost(d *Daemon, r *http.Request) response.Response {
 				return err
 			}
 
-			return cluster.RenameProject(ctx, tx.Tx(), name, req.Name)
+			err = cluster.RenameProject(ctx, tx.Tx(), name, req.Name)
+			if err != nil {
+				return err
+			}
+
+			// Keep the name of the group of self-service projects in line with the project.
+			groupName, err := tx.GetProjectOwnerGroup(ctx, req.Name)
+			if err != nil {
+				return err
+			}
+
+			if groupName != projectAdminGroupName(name) {
+				return nil
+			}
+
+			_, err = tx.GetAuthGroup(ctx, projectAdminGroupName(req.Name))
+			if err == nil {
+				return fmt.Errorf("An authorization group named %q already exists", projectAdminGroupName(req.Name))
+			} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			return tx.RenameAuthGroup(ctx, groupName, projectAdminGroupName(req.Name))
 		})
 		if err != nil {
 			return err
@@ -894,6 +1017,9 @@ func projectPost(d *Daemon, r *http.Request) response.Response {
 			logger.Error("Failed to rename project in authorizer", logger.Ctx{"name": name, "new_name": req.Name, "err": err})
 		}
 
+		// The roles of the authorization groups refer to projects by name.
+		authGroupsChanged(d)
+
 		requestor := request.CreateRequestor(r)
 		s.Events.SendLifecycle(req.Name, lifecycle.ProjectRenamed.Event(req.Name, requestor, logger.Ctx{"old_name": name}))
 
@@ -1217,6 +1343,19 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 	}
 
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Delete the group granting access to a self-service project along with it.
+		groupName, err := tx.GetProjectOwnerGroup(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if groupName != "" {
+			err = tx.DeleteAuthGroup(ctx, groupName)
+			if err != nil {
+				return err
+			}
+		}
+
 		return cluster.DeleteProject(ctx, tx.Tx(), name)
 	})
 	if err != nil {
@@ -1228,6 +1367,9 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 		logger.Error("Failed to remove project from authorizer", logger.Ctx{"name": name, "err": err})
 	}
 
+	// The roles of the authorization groups refer to projects by name.
+	authGroupsChanged(d)
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(name, lifecycle.ProjectDeleted.Event(name, requestor, nil))
 
@@ -1482,6 +1624,44 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum number of networks that the project can have
 		"limits.networks": validate.Optional(validate.IsUint32),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.networks.forwards)
+		// This value is the maximum number of network forwards on the networks of the project.
+		// It can only be set on projects with their own networks (see {config:option}`project-features:features.networks`).
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of network forwards that the project can have
+		"limits.networks.forwards": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.network_load_balancers)
+		// This value is the maximum number of network load balancers on the networks of the project.
+		// It can only be set on projects with their own networks (see {config:option}`project-features:features.networks`).
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of network load balancers that the project can have
+		"limits.network_load_balancers": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.snapshots)
+		// This value is the maximum number of instance and custom volume snapshots in the project.
+		// Scheduled snapshots are skipped once the limit is reached.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of snapshots that the project can have
+		"limits.snapshots": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.devices.gpu)
+		// This value is the maximum number of `gpu` devices of the instances of the project, either set directly or through profiles.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of GPU devices that the project can use
+		"limits.devices.gpu": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.devices.pci)
+		// This value is the maximum number of `pci` devices of the instances of the project, either set directly or through profiles.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of PCI devices that the project can use
+		"limits.devices.pci": validate.Optional(validate.IsUint32),
+
 		// gendoc:generate(entity=project, group=restricted, key=restricted)
 		// This option must be enabled to allow the `restricted.*` keys to take effect.
 		// To temporarily remove the restrictions, you can disable this option instead of clearing the related keys.
@@ -1802,6 +1982,22 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 			continue
 		}
 
+		// gendoc:generate(entity=project, group=limits, key=limits.devices.gpu.VENDOR_ID)
+		// This value is the maximum number of `gpu` devices of the instances of the project for the GPU vendor with this PCI vendor ID (for example, `10de` for NVIDIA).
+		// GPU devices that don't set `vendorid` count against all vendor-specific limits.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of GPU devices of a given vendor that the project can use
+		vendorID, ok := strings.CutPrefix(key, "limits.devices.gpu.")
+		if ok {
+			err := validate.IsDeviceID(vendorID)
+			if err != nil {
+				return fmt.Errorf("Invalid project configuration key %q: %w", k, err)
+			}
+
+			key = "limits.devices.gpu"
+		}
+
 		// Then validate.
 		validator, ok := projectConfigKeys[key]
 		if !ok {
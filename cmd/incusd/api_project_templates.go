@@ -0,0 +1,696 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var projectTemplatesCmd = APIEndpoint{
+	Path: "project-templates",
+
+	Get:  APIEndpointAction{Handler: projectTemplatesGet, AccessHandler: allowAuthenticated},
+	Post: APIEndpointAction{Handler: projectTemplatesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var projectTemplateCmd = APIEndpoint{
+	Path: "project-templates/{name}",
+
+	Delete: APIEndpointAction{Handler: projectTemplateDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: projectTemplateGet, AccessHandler: allowAuthenticated},
+	Post:   APIEndpointAction{Handler: projectTemplatePost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: projectTemplatePut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/project-templates project-templates project_templates_get
+//
+//	Get the project templates
+//
+//	Returns a list of project templates (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/project-templates/team",
+//	              "/1.0/project-templates/team-small"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/project-templates?recursion=1 project-templates project_templates_get_recursion1
+//
+//	Get the project templates
+//
+//	Returns a list of project templates (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of project templates
+//	          items:
+//	            $ref: "#/definitions/ProjectTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var templates []api.ProjectTemplate
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		templates, err = tx.GetProjectTemplates(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, templates)
+	}
+
+	urls := make([]string, 0, len(templates))
+	for _, template := range templates {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "project-templates", template.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/project-templates project-templates project_templates_post
+//
+//	Add a project template
+//
+//	Creates a new project template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: template
+//	    description: Project template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ProjectTemplatesPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = projectValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectTemplateValidate(s, req.ProjectTemplatePut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetProjectTemplate(ctx, req.Name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Project template %q already exists", req.Name)
+		}
+
+		return tx.CreateProjectTemplate(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.ProjectTemplateCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/project-templates/{name} project-templates project_template_get
+//
+//	Get the project template
+//
+//	Gets a specific project template.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Project template
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProjectTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var template *api.ProjectTemplate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		template, err = tx.GetProjectTemplate(ctx, name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, template, template.Writable())
+}
+
+// swagger:operation PUT /1.0/project-templates/{name} project-templates project_template_put
+//
+//	Update the project template
+//
+//	Updates the entire project template. Existing projects created from it are left unchanged.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: template
+//	    description: Project template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.ProjectTemplatePut{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectTemplateValidate(s, req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		template, err := tx.GetProjectTemplate(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		// Validate the ETag.
+		err = localUtil.EtagCheck(r, template.Writable())
+		if err != nil {
+			return err
+		}
+
+		return tx.UpdateProjectTemplate(ctx, name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/project-templates/{name} project-templates project_template_post
+//
+//	Rename the project template
+//
+//	Renames an existing project template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: template
+//	    description: Project template rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.ProjectTemplatePost{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check that the name isn't already in use.
+		_, err := tx.GetProjectTemplate(ctx, req.Name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Name %q already in use", req.Name)
+		}
+
+		return tx.RenameProjectTemplate(ctx, name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.ProjectTemplateRenamed.Event(req.Name, request.CreateRequestor(r), logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/project-templates/{name} project-templates project_template_delete
+//
+//	Delete the project template
+//
+//	Removes the project template. Existing projects created from it are left unchanged.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteProjectTemplate(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// projectTemplateValidate checks the configuration of a project template.
+// The default profile devices, networks and network ACLs are only fully validated when creating a project,
+// as they may refer to each other.
+func projectTemplateValidate(s *state.State, req api.ProjectTemplatePut) error {
+	err := projectValidateConfig(s, req.Config)
+	if err != nil {
+		return err
+	}
+
+	err = instance.ValidConfig(s.OS, req.DefaultProfile.Config, false, instancetype.Any)
+	if err != nil {
+		return fmt.Errorf("Invalid default profile configuration: %w", err)
+	}
+
+	for i, network := range req.Networks {
+		if network.Name == "" {
+			return fmt.Errorf("No name provided for network %d", i)
+		}
+
+		if slices.ContainsFunc(req.Networks[:i], func(n api.NetworksPost) bool { return n.Name == network.Name }) {
+			return fmt.Errorf("Duplicate network %q", network.Name)
+		}
+	}
+
+	for i, acl := range req.NetworkACLs {
+		if acl.Name == "" {
+			return fmt.Errorf("No name provided for network ACL %d", i)
+		}
+
+		if slices.ContainsFunc(req.NetworkACLs[:i], func(a api.NetworkACLsPost) bool { return a.Name == acl.Name }) {
+			return fmt.Errorf("Duplicate network ACL %q", acl.Name)
+		}
+	}
+
+	return nil
+}
+
+// projectCreateFromTemplate creates the network ACLs and networks of a template in a new project and configures its
+// default profile. The requestor is granted the admin role on projects created from a self-service template.
+// The project is deleted if any of this fails.
+func projectCreateFromTemplate(ctx context.Context, s *state.State, r *http.Request, projectName string, template *api.ProjectTemplate, selfService bool) error {
+	// Connect to the local server.
+	client, err := incus.ConnectIncusUnix(s.OS.GetUnixSocket(), nil)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() {
+		err := client.DeleteProjectForce(projectName)
+		if err != nil {
+			logger.Error("Failed deleting project created from template", logger.Ctx{"project": projectName, "template": template.Name, "err": err})
+		}
+	})
+
+	target := client.UseProject(projectName)
+
+	// Create the network ACLs first as the networks may refer to them.
+	for _, acl := range template.NetworkACLs {
+		err = target.CreateNetworkACL(acl)
+		if err != nil {
+			return fmt.Errorf("Failed creating network ACL %q: %w", acl.Name, err)
+		}
+	}
+
+	for _, network := range template.Networks {
+		err = target.CreateNetwork(network)
+		if err != nil {
+			return fmt.Errorf("Failed creating network %q: %w", network.Name, err)
+		}
+	}
+
+	// Configure the default profile last as its devices may refer to the networks.
+	if len(template.DefaultProfile.Config) > 0 || len(template.DefaultProfile.Devices) > 0 {
+		project, _, err := target.GetProject(projectName)
+		if err != nil {
+			return err
+		}
+
+		if util.IsFalseOrEmpty(project.Config["features.profiles"]) {
+			return fmt.Errorf("The template configures the default profile but the project doesn't have the profiles feature enabled")
+		}
+
+		profile, etag, err := target.GetProfile(api.ProjectDefaultName)
+		if err != nil {
+			return err
+		}
+
+		req := template.DefaultProfile
+		if req.Description == "" {
+			req.Description = profile.Description
+		}
+
+		err = target.UpdateProfile(api.ProjectDefaultName, req, etag)
+		if err != nil {
+			return fmt.Errorf("Failed configuring the default profile: %w", err)
+		}
+	}
+
+	if selfService {
+		err = projectGrantAdmin(ctx, s, r, projectName)
+		if err != nil {
+			return fmt.Errorf("Failed granting access to the project: %w", err)
+		}
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// projectCheckSelfServiceLimit returns an error if the requestor already reached the number of projects it may
+// create from self-service templates.
+func projectCheckSelfServiceLimit(ctx context.Context, s *state.State, r *http.Request) error {
+	limit := s.GlobalConfig.ProjectsTemplatesSelfServiceLimit()
+	if limit <= 0 {
+		return nil
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		count, err := tx.GetProjectOwnerCount(ctx, requestor.Protocol, requestor.Username)
+		if err != nil {
+			return err
+		}
+
+		if int64(count) >= limit {
+			return api.StatusErrorf(http.StatusForbidden, "Self-service project limit of %d has been reached", limit)
+		}
+
+		return nil
+	})
+}
+
+// projectAdminGroupName returns the name of the authorization group granting the admin role on a self-service project.
+func projectAdminGroupName(projectName string) string {
+	return fmt.Sprintf("project-%s-admin", projectName)
+}
+
+// projectGrantAdmin grants the requestor the admin role on a project it created from a self-service template.
+//
+// The requestor is added to a new authorization group holding the role and recorded as the owner of the project.
+// For restricted TLS clients, the project is also added to the projects of their certificate.
+func projectGrantAdmin(ctx context.Context, s *state.State, r *http.Request, projectName string) error {
+	requestor := request.CreateRequestor(r)
+	if requestor.Username == "" {
+		return fmt.Errorf("Unable to identify the requestor")
+	}
+
+	groupName := projectAdminGroupName(projectName)
+
+	var certificate *api.Certificate
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.CreateAuthGroup(ctx, api.AuthGroupsPost{
+			AuthGroupPut: api.AuthGroupPut{
+				Description: fmt.Sprintf("Administrators of project %s", projectName),
+				Roles:       []api.AuthGroupRole{{Role: api.AuthRoleAdmin, Project: projectName}},
+			},
+			Name: groupName,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed creating authorization group %q: %w", groupName, err)
+		}
+
+		identity, err := tx.GetAuthIdentity(ctx, requestor.Protocol, requestor.Username)
+		if err != nil {
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			err = tx.CreateAuthIdentity(ctx, api.AuthIdentitiesPost{
+				AuthIdentityPut:      api.AuthIdentityPut{Groups: []string{groupName}},
+				AuthenticationMethod: requestor.Protocol,
+				Identifier:           requestor.Username,
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			identity.Groups = append(identity.Groups, groupName)
+
+			err = tx.UpdateAuthIdentity(ctx, requestor.Protocol, requestor.Username, identity.AuthIdentityPut)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.CreateProjectOwner(ctx, projectName, requestor.Protocol, requestor.Username, groupName)
+		if err != nil {
+			return err
+		}
+
+		if requestor.Protocol != api.AuthenticationMethodTLS {
+			return nil
+		}
+
+		dbCert, err := dbCluster.GetCertificateByFingerprintPrefix(ctx, tx.Tx(), requestor.Username)
+		if err != nil {
+			return err
+		}
+
+		if !dbCert.Restricted {
+			return nil
+		}
+
+		certificate, err = dbCert.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		certificate.Projects = append(certificate.Projects, projectName)
+
+		return dbCluster.UpdateCertificateProjects(ctx, tx.Tx(), dbCert.ID, certificate.Projects)
+	})
+	if err != nil {
+		return err
+	}
+
+	if certificate != nil {
+		// Notify other members about the updated certificate.
+		notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UpdateCertificate(certificate.Fingerprint, certificate.Writable(), "")
+		})
+		if err != nil {
+			return err
+		}
+
+		s.UpdateCertificateCache()
+	}
+
+	return nil
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var projectUsageCmd = APIEndpoint{
+	Path: "projects/{name}/usage",
+
+	Get: APIEndpointAction{Handler: projectUsageGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
+}
+
+// Project usage is accounted every 15 minutes, reported for the last 30 days by default and kept for a year.
+const (
+	projectUsageInterval      = 15 * time.Minute
+	projectUsageDefaultPeriod = 30 * 24 * time.Hour
+	projectUsageRetention     = 366 * 24 * time.Hour
+)
+
+// Resources accounted in the project usage.
+const (
+	projectUsageCPU             = "cpu"
+	projectUsageMemory          = "memory"
+	projectUsageStorage         = "storage"
+	projectUsageNetworkReceived = "network-received"
+	projectUsageNetworkSent     = "network-sent"
+)
+
+// projectUsageUnits are the units in which the project usage is recorded.
+var projectUsageUnits = map[string]string{
+	projectUsageCPU:             "seconds",
+	projectUsageMemory:          "GB-hours",
+	projectUsageStorage:         "GB-hours",
+	projectUsageNetworkReceived: "bytes",
+	projectUsageNetworkSent:     "bytes",
+}
+
+// swagger:operation GET /1.0/projects/{name}/usage projects project_usage_get
+//
+//	Get the project usage
+//
+//	Gets the resources consumed by the project over a period of time.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: from
+//	    description: Start of the period (RFC3339, defaults to 30 days before the end)
+//	    type: string
+//	    example: 2024-11-01T00:00:00Z
+//	  - in: query
+//	    name: to
+//	    description: End of the period (RFC3339, defaults to now)
+//	    type: string
+//	    example: 2024-12-01T00:00:00Z
+//	responses:
+//	  "200":
+//	    description: Project usage
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProjectUsage"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectUsageGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	to := time.Now().UTC()
+	if r.FormValue("to") != "" {
+		to, err = time.Parse(time.RFC3339, r.FormValue("to"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid end of period %q", r.FormValue("to")))
+		}
+	}
+
+	from := to.Add(-projectUsageDefaultPeriod)
+	if r.FormValue("from") != "" {
+		from, err = time.Parse(time.RFC3339, r.FormValue("from"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid start of period %q", r.FormValue("from")))
+		}
+	}
+
+	if !from.Before(to) {
+		return response.BadRequest(fmt.Errorf("The start of the period must be before its end"))
+	}
+
+	var entries []db.ProjectUsageEntry
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		entries, err = tx.GetProjectUsage(ctx, name, from, to)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	usage := api.ProjectUsage{
+		From:      from.UTC(),
+		To:        to.UTC(),
+		Resources: make([]api.ProjectUsageResource, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		usage.Resources = append(usage.Resources, api.ProjectUsageResource{
+			Type:  entry.Type,
+			Pool:  entry.Pool,
+			Usage: entry.Value,
+			Unit:  projectUsageUnits[entry.Type],
+		})
+	}
+
+	return response.SyncResponse(true, &usage)
+}
+
+// projectUsageCounterDelta returns the increase of a cumulative counter, accounting for counters reset by a restart.
+func projectUsageCounterDelta(previous int64, current int64) int64 {
+	if current < previous {
+		return current
+	}
+
+	return current - previous
+}
+
+// projectUsageAccounting integrates the resource usage of the local instances and volumes between runs.
+type projectUsageAccounting struct {
+	lastRun     time.Time
+	lastSamples map[string]instanceHistorySample
+	entries     map[db.ProjectUsageEntry]float64
+}
+
+// add accounts an amount of a resource to a project.
+func (a *projectUsageAccounting) add(projectName string, resource string, pool string, value float64) {
+	if value <= 0 {
+		return
+	}
+
+	a.entries[db.ProjectUsageEntry{Project: projectName, Type: resource, Pool: pool}] += value
+}
+
+// addInstance accounts the CPU and network usage of an instance along with the memory usage of a running one.
+// The counters come from the persisted usage history, so the usage of an instance which stopped since the
+// previous run is accounted up to its last sample.
+func (a *projectUsageAccounting) addInstance(inst instance.Instance, elapsed time.Duration, current map[string]instanceHistorySample, running bool) {
+	projectName := inst.Project().Name
+	key := project.Instance(projectName, inst.Name())
+
+	// Stopped instances only need accounting once, if they were running during the previous run.
+	previous, ok := a.lastSamples[key]
+	if !ok && !running {
+		return
+	}
+
+	sample, found := instanceHistoryLast(projectName, inst.Name())
+	if !found {
+		return
+	}
+
+	if running {
+		current[key] = sample
+	}
+
+	if ok && sample.timestamp > previous.timestamp {
+		a.add(projectName, projectUsageCPU, "", float64(projectUsageCounterDelta(previous.cpu, sample.cpu))/float64(time.Second))
+		a.add(projectName, projectUsageNetworkReceived, "", float64(projectUsageCounterDelta(previous.networkRecv, sample.networkRecv)))
+		a.add(projectName, projectUsageNetworkSent, "", float64(projectUsageCounterDelta(previous.networkSent, sample.networkSent)))
+	}
+
+	if !running {
+		return
+	}
+
+	// Account the memory limit when set and the current usage otherwise.
+	memory := sample.memory
+	if inst.ExpandedConfig()["limits.memory"] != "" {
+		limit, err := instanceDrivers.ParseMemoryStr(inst.ExpandedConfig()["limits.memory"])
+		if err == nil {
+			memory = limit
+		}
+	}
+
+	a.add(projectName, projectUsageMemory, "", float64(memory)/1e9*elapsed.Hours())
+}
+
+// addVolume accounts the storage used by a volume.
+func (a *projectUsageAccounting) addVolume(projectName string, poolName string, usage *storagePools.VolumeUsage, elapsed time.Duration) {
+	if usage == nil || usage.Used <= 0 {
+		return
+	}
+
+	a.add(projectName, projectUsageStorage, poolName, float64(usage.Used)/1e9*elapsed.Hours())
+}
+
+// run accounts the usage of the local instances and volumes since the previous run and records it.
+func (a *projectUsageAccounting) run(ctx context.Context, s *state.State) error {
+	now := time.Now()
+	a.entries = map[db.ProjectUsageEntry]float64{}
+
+	// The first run only records the counters, as there is nothing to integrate over yet.
+	var elapsed time.Duration
+	if !a.lastRun.IsZero() {
+		elapsed = now.Sub(a.lastRun)
+	}
+
+	a.lastRun = now
+
+	instances, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return fmt.Errorf("Failed loading instances: %w", err)
+	}
+
+	current := map[string]instanceHistorySample{}
+	for _, inst := range instances {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if inst.IsSnapshot() {
+			continue
+		}
+
+		a.addInstance(inst, elapsed, current, inst.IsRunning())
+
+		if elapsed == 0 {
+			continue
+		}
+
+		pool, err := storagePools.LoadByInstance(s, inst)
+		if err != nil {
+			logger.Debug("Failed loading instance storage pool for project usage", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+			continue
+		}
+
+		usage, err := pool.GetInstanceUsage(inst)
+		if err != nil {
+			logger.Debug("Failed getting instance storage usage for project usage", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+			continue
+		}
+
+		a.addVolume(inst.Project().Name, pool.Name(), usage, elapsed)
+	}
+
+	a.lastSamples = current
+
+	isLeader := true
+	if s.ServerClustered {
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil {
+			return err
+		}
+
+		isLeader = leader == s.LocalConfig.ClusterAddress()
+	}
+
+	if elapsed > 0 {
+		err = a.addCustomVolumes(ctx, s, elapsed, isLeader)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Prune the usage which is past retention.
+	if isLeader {
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteProjectUsageBefore(ctx, now.Add(-projectUsageRetention))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(a.entries) == 0 {
+		return nil
+	}
+
+	entries := make([]db.ProjectUsageEntry, 0, len(a.entries))
+	for entry, value := range a.entries {
+		entry.Date = now
+		entry.Value = value
+		entries = append(entries, entry)
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.AddProjectUsage(ctx, entries)
+	})
+}
+
+// addCustomVolumes accounts the storage used by the custom volumes of this member.
+// Volumes of remote storage pools are only accounted by the leader.
+func (a *projectUsageAccounting) addCustomVolumes(ctx context.Context, s *state.State, elapsed time.Duration, isLeader bool) error {
+	var volumes []db.StorageVolumeArgs
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		volumes, err = tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading custom volumes: %w", err)
+	}
+
+	pools := map[string]storagePools.Pool{}
+	for _, vol := range volumes {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if vol.NodeID < 0 && !isLeader {
+			continue
+		}
+
+		pool, ok := pools[vol.PoolName]
+		if !ok {
+			pool, err = storagePools.LoadByName(s, vol.PoolName)
+			if err != nil {
+				logger.Debug("Failed loading storage pool for project usage", logger.Ctx{"pool": vol.PoolName, "err": err})
+				continue
+			}
+
+			pools[vol.PoolName] = pool
+		}
+
+		usage, err := pool.GetCustomVolumeUsage(vol.ProjectName, vol.Name)
+		if err != nil {
+			logger.Debug("Failed getting custom volume usage for project usage", logger.Ctx{"project": vol.ProjectName, "pool": vol.PoolName, "volume": vol.Name, "err": err})
+			continue
+		}
+
+		a.addVolume(vol.ProjectName, vol.PoolName, usage, elapsed)
+	}
+
+	return nil
+}
+
+// projectUsageTask accounts the resources consumed by the local instances and volumes to their projects.
+func projectUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	accounting := &projectUsageAccounting{lastSamples: map[string]instanceHistorySample{}}
+
+	f := func(ctx context.Context) {
+		err := accounting.run(ctx, d.State())
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("Failed accounting project usage", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(projectUsageInterval)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	clusterRequest "github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/request"
+)
+
+func TestIsClusterMember(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		protocol  string
+		expected  bool
+	}{
+		{"Regular client", "", "tls", false},
+		{"Spoofed notifier user agent", clusterRequest.UserAgentNotifier, "tls", false},
+		{"Spoofed internal user agent", clusterRequest.UserAgentClient, "oidc", false},
+		{"Cluster member", clusterRequest.UserAgentNotifier, "cluster", true},
+		{"Unauthenticated", clusterRequest.UserAgentNotifier, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/1.0/instances", nil)
+			r.Header.Set("User-Agent", tt.userAgent)
+
+			if tt.protocol != "" {
+				r = r.WithContext(context.WithValue(r.Context(), request.CtxProtocol, tt.protocol))
+			}
+
+			assert.Equal(t, tt.expected, isClusterMember(r))
+		})
+	}
+}
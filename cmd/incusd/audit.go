@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/audit"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+var auditCmd = APIEndpoint{
+	Path: "audit",
+
+	Get: APIEndpointAction{Handler: auditGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+}
+
+// swagger:operation GET /1.0/audit server audit_get
+//
+//	Get the audit log
+//
+//	Returns the entries of the audit log of the cluster member, oldest first.
+//	The integrity of the whole log is checked before returning them.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: query
+//	    name: since
+//	    description: Only return entries recorded at or after this time (RFC3339)
+//	    type: string
+//	    example: 2024-10-19T00:00:00Z
+//	  - in: query
+//	    name: until
+//	    description: Only return entries recorded at or before this time (RFC3339)
+//	    type: string
+//	    example: 2024-10-20T00:00:00Z
+//	  - in: query
+//	    name: method
+//	    description: Only return entries with this HTTP method
+//	    type: string
+//	    example: DELETE
+//	  - in: query
+//	    name: project
+//	    description: Only return entries targeting this project
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: username
+//	    description: Only return entries from this requestor
+//	    type: string
+//	    example: jane.doe@example.com
+//	  - in: query
+//	    name: protocol
+//	    description: Only return entries using this authentication protocol
+//	    type: string
+//	    example: oidc
+//	  - in: query
+//	    name: limit
+//	    description: Only return the most recent matching entries
+//	    type: integer
+//	    example: 100
+//	responses:
+//	  "200":
+//	    description: Audit log entries
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of audit log entries
+//	          items:
+//	            $ref: "#/definitions/AuditEntry"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func auditGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Forward if requested.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	if d.auditLog == nil {
+		return response.Unavailable(errors.New("The audit log isn't available"))
+	}
+
+	filter := audit.Filter{
+		Method:   strings.ToUpper(request.QueryParam(r, "method")),
+		Project:  request.QueryParam(r, "project"),
+		Username: request.QueryParam(r, "username"),
+		Protocol: request.QueryParam(r, "protocol"),
+	}
+
+	var err error
+
+	since := request.QueryParam(r, "since")
+	if since != "" {
+		filter.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid %q value: %w", "since", err))
+		}
+	}
+
+	until := request.QueryParam(r, "until")
+	if until != "" {
+		filter.Until, err = time.Parse(time.RFC3339, until)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid %q value: %w", "until", err))
+		}
+	}
+
+	limit := request.QueryParam(r, "limit")
+	if limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit < 0 {
+			return response.BadRequest(fmt.Errorf("Invalid %q value %q", "limit", limit))
+		}
+	}
+
+	entries, err := d.auditLog.Entries(filter)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, entries)
+}
+
+// auditRecord appends a handled API request to the audit log.
+func (d *Daemon) auditRecord(r *http.Request, statusCode int, location string) {
+	requestor := request.CreateRequestor(r)
+
+	entry := api.AuditEntry{
+		Timestamp:  time.Now().UTC(),
+		Method:     r.Method,
+		URL:        r.URL.RequestURI(),
+		Project:    request.ProjectParam(r),
+		Username:   requestor.Username,
+		Protocol:   requestor.Protocol,
+		Address:    requestor.Address,
+		StatusCode: statusCode,
+		Location:   d.serverName,
+	}
+
+	operationID, ok := strings.CutPrefix(location, "/1.0/operations/")
+	if ok {
+		entry.OperationID = operationID
+	}
+
+	err := d.auditLog.Append(entry)
+	if err != nil {
+		logger.Error("Failed recording request in the audit log", logger.Ctx{"url": entry.URL, "err": err})
+	}
+}
+
+// auditKey derives the key of the audit log from the private key of the server certificate.
+func auditKey(cert *localtls.CertInfo) []byte {
+	key := sha256.Sum256(append([]byte("incus-audit:"), cert.PrivateKey()...))
+	return key[:]
+}
+
+// setupAuditSyslog starts or stops forwarding the audit log to syslog.
+func (d *Daemon) setupAuditSyslog(target string) error {
+	if d.auditSyslog != nil {
+		d.auditLog.RemoveHandler("syslog")
+		_ = d.auditSyslog.Close()
+		d.auditSyslog = nil
+	}
+
+	if target == "" {
+		return nil
+	}
+
+	forwarder, err := audit.NewSyslogForwarder(target)
+	if err != nil {
+		return err
+	}
+
+	d.auditSyslog = forwarder
+	d.auditLog.AddHandler("syslog", forwarder.Handle)
+
+	return nil
+}
@@ -0,0 +1,567 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// authGroupsCacheExpiry is how long the authorization groups are cached for, in case a change notification from
+// another cluster member was missed.
+const authGroupsCacheExpiry = time.Minute
+
+// authGroupsCache caches the authorization groups used to check permissions.
+type authGroupsCache struct {
+	mu         sync.Mutex
+	groups     []api.AuthGroup
+	expiry     time.Time
+	generation uint64
+}
+
+// get returns the cached groups if still valid, along with the generation of the cache.
+func (c *authGroupsCache) get() ([]api.AuthGroup, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.groups == nil || time.Now().After(c.expiry) {
+		return nil, c.generation
+	}
+
+	return c.groups, c.generation
+}
+
+// set caches the groups loaded at the given generation, unless they changed since.
+func (c *authGroupsCache) set(groups []api.AuthGroup, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if groups == nil {
+		groups = []api.AuthGroup{}
+	}
+
+	c.groups = groups
+	c.expiry = time.Now().Add(authGroupsCacheExpiry)
+}
+
+// invalidate drops the cached groups.
+func (c *authGroupsCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.groups = nil
+	c.generation++
+}
+
+// authGroupsChanged drops the cached authorization groups after a change to the groups, identities or projects,
+// and tells the other cluster members to do the same.
+func authGroupsChanged(d *Daemon) {
+	d.authGroupsCache.invalidate()
+
+	s := d.State()
+
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	if err == nil {
+		err = notifier(func(client incus.InstanceServer) error {
+			_, _, err := client.RawQuery("POST", "/internal/auth/refresh", nil, "")
+			return err
+		})
+	}
+
+	if err != nil {
+		logger.Warn("Failed notifying cluster members of authorization changes", logger.Ctx{"err": err})
+	}
+}
+
+var authGroupsCmd = APIEndpoint{
+	Path: "auth/groups",
+
+	Get:  APIEndpointAction{Handler: authGroupsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authGroupsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authGroupCmd = APIEndpoint{
+	Path: "auth/groups/{name}",
+
+	Delete: APIEndpointAction{Handler: authGroupDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: authGroupGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post:   APIEndpointAction{Handler: authGroupPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: authGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/auth/groups auth auth_groups_get
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/groups/developers",
+//	              "/1.0/auth/groups/operators"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/groups?recursion=1 auth auth_groups_get_recursion1
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of authorization groups
+//	          items:
+//	            $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var groups []api.AuthGroup
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		groups, err = tx.GetAuthGroups(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, groups)
+	}
+
+	urls := make([]string, 0, len(groups))
+	for _, group := range groups {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "groups", group.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/groups auth auth_groups_post
+//
+//	Add an authorization group
+//
+//	Creates a new authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group to create
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthGroupsPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = authGroupValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authGroupValidate(req.AuthGroupPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateAuthGroup(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	authGroupsChanged(d)
+
+	lc := lifecycle.AuthGroupCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/groups/{name} auth auth_group_get
+//
+//	Get the authorization group
+//
+//	Gets a specific authorization group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Authorization group
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var group *api.AuthGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err = tx.GetAuthGroup(ctx, name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, group, group.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/groups/{name} auth auth_group_put
+//
+//	Update the authorization group
+//
+//	Updates the description, roles and identity provider groups of the authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthGroupPut{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authGroupValidate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err := tx.GetAuthGroup(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		// Validate the ETag.
+		err = localUtil.EtagCheck(r, group.Writable())
+		if err != nil {
+			return err
+		}
+
+		return tx.UpdateAuthGroup(ctx, name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	authGroupsChanged(d)
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/auth/groups/{name} auth auth_group_post
+//
+//	Rename the authorization group
+//
+//	Renames an existing authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthGroupPost{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authGroupValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check that the name isn't already in use.
+		_, err := tx.GetAuthGroup(ctx, req.Name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Name %q already in use", req.Name)
+		}
+
+		return tx.RenameAuthGroup(ctx, name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	authGroupsChanged(d)
+
+	lc := lifecycle.AuthGroupRenamed.Event(req.Name, request.CreateRequestor(r), logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/auth/groups/{name} auth auth_group_delete
+//
+//	Delete the authorization group
+//
+//	Removes the authorization group, its members lose the roles it granted.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteAuthGroup(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	authGroupsChanged(d)
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// authGroupValidateName checks that the group name is valid.
+func authGroupValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("No name provided")
+	}
+
+	if strings.ContainsAny(name, "/ '\"") {
+		return fmt.Errorf("Group names may not contain slashes, spaces or quotes")
+	}
+
+	if slices.Contains([]string{".", ".."}, name) {
+		return fmt.Errorf("Invalid group name %q", name)
+	}
+
+	return nil
+}
+
+// authGroupValidate checks the roles and identity provider groups of a group.
+func authGroupValidate(req api.AuthGroupPut) error {
+	for i, role := range req.Roles {
+		if !slices.Contains([]string{api.AuthRoleViewer, api.AuthRoleOperator, api.AuthRoleAdmin}, role.Role) {
+			return fmt.Errorf("Invalid role %q", role.Role)
+		}
+
+		if slices.Contains(req.Roles[:i], role) {
+			return fmt.Errorf("Duplicate role %q", role.Role)
+		}
+	}
+
+	for i, name := range req.IdentityProviderGroups {
+		if name == "" {
+			return fmt.Errorf("Identity provider group names may not be empty")
+		}
+
+		if slices.Contains(req.IdentityProviderGroups[:i], name) {
+			return fmt.Errorf("Duplicate identity provider group %q", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var authIdentitiesCmd = APIEndpoint{
+	Path: "auth/identities",
+
+	Get:  APIEndpointAction{Handler: authIdentitiesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authIdentitiesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authIdentityCmd = APIEndpoint{
+	Path: "auth/identities/{authenticationMethod}/{identifier}",
+
+	Delete: APIEndpointAction{Handler: authIdentityDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: authIdentityGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: authIdentityPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/auth/identities auth auth_identities_get
+//
+//	Get the identities
+//
+//	Returns a list of identities (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/identities/oidc/jane.doe@example.com"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/identities?recursion=1 auth auth_identities_get_recursion1
+//
+//	Get the identities
+//
+//	Returns a list of identities (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of identities
+//	          items:
+//	            $ref: "#/definitions/AuthIdentity"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentitiesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var identities []api.AuthIdentity
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		identities, err = tx.GetAuthIdentities(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, identities)
+	}
+
+	urls := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "identities", identity.AuthenticationMethod, identity.Identifier).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/identities auth auth_identities_post
+//
+//	Add an identity
+//
+//	Adds a new identity, usually to make it a member of some authorization groups.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity to add
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthIdentitiesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentitiesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthIdentitiesPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	if !slices.Contains([]string{api.AuthenticationMethodTLS, api.AuthenticationMethodOIDC}, req.AuthenticationMethod) {
+		return response.BadRequest(fmt.Errorf("Invalid authentication method %q", req.AuthenticationMethod))
+	}
+
+	if req.Identifier == "" {
+		return response.BadRequest(fmt.Errorf("No identifier provided"))
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateAuthIdentity(ctx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	authGroupsChanged(d)
+
+	lc := lifecycle.AuthIdentityCreated.Event(req.AuthenticationMethod, req.Identifier, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_get
+//
+//	Get the identity
+//
+//	Gets a specific identity.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Identity
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthIdentity"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var identity *api.AuthIdentity
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err = tx.GetAuthIdentity(ctx, authenticationMethod, identifier)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, identity, identity.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_put
+//
+//	Update the identity
+//
+//	Updates the name and group membership of the identity.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: identity
+//	    description: Identity configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthIdentityPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthIdentityPut{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		identity, err := tx.GetAuthIdentity(ctx, authenticationMethod, identifier)
+		if err != nil {
+			return err
+		}
+
+		// Validate the ETag.
+		err = localUtil.EtagCheck(r, identity.Writable())
+		if err != nil {
+			return err
+		}
+
+		return tx.UpdateAuthIdentity(ctx, authenticationMethod, identifier, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	authGroupsChanged(d)
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthIdentityUpdated.Event(authenticationMethod, identifier, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/auth/identities/{authenticationMethod}/{identifier} auth auth_identity_delete
+//
+//	Delete the identity
+//
+//	Removes the identity from all its authorization groups.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authIdentityDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	authenticationMethod, identifier, err := authIdentityFromRequest(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteAuthIdentity(ctx, authenticationMethod, identifier)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	authGroupsChanged(d)
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthIdentityDeleted.Event(authenticationMethod, identifier, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// authIdentityFromRequest returns the authentication method and identifier from the request URL.
+func authIdentityFromRequest(r *http.Request) (string, string, error) {
+	authenticationMethod, err := url.PathUnescape(mux.Vars(r)["authenticationMethod"])
+	if err != nil {
+		return "", "", err
+	}
+
+	identifier, err := url.PathUnescape(mux.Vars(r)["identifier"])
+	if err != nil {
+		return "", "", err
+	}
+
+	return authenticationMethod, identifier, nil
+}
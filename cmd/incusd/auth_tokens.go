@@ -0,0 +1,562 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// authTokenIssuer is the issuer of the authentication tokens, it tells them apart from other bearer tokens.
+const authTokenIssuer = "incus-auth-token"
+
+// authTokenClaims are the claims of a signed authentication token.
+type authTokenClaims struct {
+	jwt.RegisteredClaims
+
+	Project      string   `json:"project"`
+	Entitlements []string `json:"entitlements"`
+}
+
+var authTokensCmd = APIEndpoint{
+	Path: "auth/tokens",
+
+	Get:  APIEndpointAction{Handler: authTokensGet, AccessHandler: allowAuthenticated},
+	Post: APIEndpointAction{Handler: authTokensPost, AccessHandler: allowAuthenticated},
+}
+
+var authTokenCmd = APIEndpoint{
+	Path: "auth/tokens/{id}",
+
+	Delete: APIEndpointAction{Handler: authTokenDelete, AccessHandler: allowAuthenticated},
+	Get:    APIEndpointAction{Handler: authTokenGet, AccessHandler: allowAuthenticated},
+}
+
+// swagger:operation GET /1.0/auth/tokens auth auth_tokens_get
+//
+//	Get the authentication tokens
+//
+//	Returns a list of authentication tokens (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve authentication tokens from all projects
+//	    type: boolean
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/tokens/3f2c9d1e-8a4b-4c6d-9e0f-1a2b3c4d5e6f"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/tokens?recursion=1 auth auth_tokens_get_recursion1
+//
+//	Get the authentication tokens
+//
+//	Returns a list of authentication tokens (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve authentication tokens from all projects
+//	    type: boolean
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of authentication tokens
+//	          items:
+//	            $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var projectName *string
+	if !util.IsTrue(request.QueryParam(r, "all-projects")) {
+		name := request.ProjectParam(r)
+		projectName = &name
+	}
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), r, auth.EntitlementCanEdit, auth.ObjectTypeProject)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var tokens []api.AuthToken
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		tokens, err = tx.GetAuthTokens(ctx, projectName)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	allowedTokens := make([]api.AuthToken, 0, len(tokens))
+	for _, token := range tokens {
+		if userHasPermission(auth.ObjectProject(token.Project)) {
+			allowedTokens = append(allowedTokens, token)
+		}
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, allowedTokens)
+	}
+
+	urls := make([]string, 0, len(allowedTokens))
+	for _, token := range allowedTokens {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "tokens", token.ID).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/tokens auth auth_tokens_post
+//
+//	Create an authentication token
+//
+//	Creates a new short-lived bearer token restricted to the project and entitlements provided.
+//	The token itself is only returned once.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: token
+//	    description: Authentication token to create
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthTokensPost"
+//	responses:
+//	  "200":
+//	    description: Authentication token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthTokenSecret"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	// Tokens can't be used to extend their own lifetime or scope.
+	if request.CreateRequestor(r).Protocol == api.AuthenticationMethodToken {
+		return response.Forbidden(fmt.Errorf("Authentication tokens can't be used to create other tokens"))
+	}
+
+	err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectProject(projectName), auth.EntitlementCanEdit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthTokensPost{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = auth.ValidateTokenEntitlements(req.Entitlements)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	now := time.Now().UTC()
+	if !req.ExpiresAt.After(now) {
+		return response.BadRequest(fmt.Errorf("Expiry date must be in the future"))
+	}
+
+	token := api.AuthToken{
+		ID:           uuid.New().String(),
+		Description:  req.Description,
+		Project:      projectName,
+		Entitlements: req.Entitlements,
+		CreatedAt:    now,
+		ExpiresAt:    req.ExpiresAt.UTC(),
+	}
+
+	signed, err := authTokenSign(s.Endpoints.NetworkCert(), token)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateAuthToken(ctx, token)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.AuthTokenCreated.Event(token.ID, projectName, request.CreateRequestor(r), map[string]any{"expires_at": token.ExpiresAt, "entitlements": token.Entitlements})
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, api.AuthTokenSecret{ID: token.ID, Token: signed}, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/tokens/{id} auth auth_token_get
+//
+//	Get the authentication token
+//
+//	Gets a specific authentication token.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Authentication token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenGet(d *Daemon, r *http.Request) response.Response {
+	token, resp := authTokenFromRequest(d.State(), r)
+	if resp != nil {
+		return resp
+	}
+
+	return response.SyncResponse(true, token)
+}
+
+// swagger:operation DELETE /1.0/auth/tokens/{id} auth auth_token_delete
+//
+//	Revoke the authentication token
+//
+//	Revokes the authentication token, it can't be used anymore.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	token, resp := authTokenFromRequest(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteAuthToken(ctx, token.ID)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(token.Project, lifecycle.AuthTokenDeleted.Event(token.ID, token.Project, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// authTokenFromRequest loads the authentication token referenced by the request URL, checking that the requestor
+// can manage the tokens of its project.
+func authTokenFromRequest(s *state.State, r *http.Request) (*api.AuthToken, response.Response) {
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	var token *api.AuthToken
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		token, err = tx.GetAuthToken(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectProject(token.Project), auth.EntitlementCanEdit)
+	if err != nil {
+		// Don't leak the existence of the token.
+		if api.StatusErrorCheck(err, http.StatusForbidden) {
+			return nil, response.NotFound(fmt.Errorf("Authentication token not found"))
+		}
+
+		return nil, response.SmartError(err)
+	}
+
+	return token, nil
+}
+
+// authTokenSigningMethod returns the JWT signing method matching the given key.
+func authTokenSigningMethod(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			return jwt.SigningMethodES256, nil
+		case 384:
+			return jwt.SigningMethodES384, nil
+		case 521:
+			return jwt.SigningMethodES512, nil
+		}
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported key type %T", key)
+}
+
+// authTokenSign returns the signed bearer token for the authentication token.
+// Tokens are signed with the server (or cluster) certificate so that any cluster member can verify them.
+func authTokenSign(cert *localtls.CertInfo, token api.AuthToken) (string, error) {
+	x509Cert, err := cert.PublicKeyX509()
+	if err != nil {
+		return "", fmt.Errorf("Failed parsing server certificate: %w", err)
+	}
+
+	method, err := authTokenSigningMethod(x509Cert.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	claims := authTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        token.ID,
+			Issuer:    authTokenIssuer,
+			IssuedAt:  jwt.NewNumericDate(token.CreatedAt),
+			ExpiresAt: jwt.NewNumericDate(token.ExpiresAt),
+		},
+		Project:      token.Project,
+		Entitlements: token.Entitlements,
+	}
+
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(cert.KeyPair().PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("Failed signing authentication token: %w", err)
+	}
+
+	return signed, nil
+}
+
+// authTokenFromHeader returns the bearer token of the request if it is an authentication token.
+func authTokenFromHeader(r *http.Request) (string, bool) {
+	fields := strings.Fields(r.Header.Get("Authorization"))
+	if len(fields) != 2 || strings.ToLower(fields[0]) != "bearer" {
+		return "", false
+	}
+
+	claims := authTokenClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(fields[1], &claims)
+	if err != nil || claims.Issuer != authTokenIssuer {
+		return "", false
+	}
+
+	return fields[1], true
+}
+
+// authTokenVerify checks the signature and expiry of a bearer token and that it wasn't revoked.
+// It returns the ID of the authentication token.
+func (d *Daemon) authTokenVerify(ctx context.Context, signed string) (string, error) {
+	x509Cert, err := d.endpoints.NetworkCert().PublicKeyX509()
+	if err != nil {
+		return "", fmt.Errorf("Failed parsing server certificate: %w", err)
+	}
+
+	method, err := authTokenSigningMethod(x509Cert.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	claims := authTokenClaims{}
+	_, err = jwt.ParseWithClaims(signed, &claims, func(*jwt.Token) (any, error) {
+		return x509Cert.PublicKey, nil
+	}, jwt.WithValidMethods([]string{method.Alg()}), jwt.WithIssuer(authTokenIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", fmt.Errorf("Invalid authentication token: %w", err)
+	}
+
+	// Check that the token wasn't revoked.
+	_, err = d.authToken(ctx, claims.ID)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return "", fmt.Errorf("Authentication token has been revoked")
+		}
+
+		return "", err
+	}
+
+	return claims.ID, nil
+}
+
+// autoRemoveExpiredAuthTokens removes the expired authentication tokens from the database.
+func autoRemoveExpiredAuthTokens(ctx context.Context, s *state.State) {
+	// If we are clustered, let the leader handle the cleanup.
+	if s.ServerClustered {
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil {
+			return
+		}
+
+		if s.LocalConfig.ClusterAddress() != leader {
+			return
+		}
+	}
+
+	var count int
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		count, err = tx.DeleteExpiredAuthTokens(ctx, time.Now())
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed removing expired authentication tokens", logger.Ctx{"err": err})
+		return
+	}
+
+	if count > 0 {
+		logger.Info("Removed expired authentication tokens", logger.Ctx{"count": count})
+	}
+}
@@ -25,12 +25,15 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/server/warnings"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -54,6 +57,12 @@ var certificateCmd = APIEndpoint{
 	Put:    APIEndpointAction{Handler: certificatePut, AccessHandler: allowAuthenticated},
 }
 
+var certificateRenewCmd = APIEndpoint{
+	Path: "certificates/{fingerprint}/renew",
+
+	Post: APIEndpointAction{Handler: certificateRenewPost, AccessHandler: allowAuthenticated},
+}
+
 // swagger:operation GET /1.0/certificates certificates certificates_get
 //
 //  Get the trusted certificates
@@ -563,7 +572,7 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 
 	// Access check.
 	// Check if the user is already trusted.
-	trusted, _, _, err := d.Authenticate(nil, r)
+	trusted, _, _, _, err := d.Authenticate(nil, r)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -1262,6 +1271,167 @@ func certificateDelete(d *Daemon, r *http.Request) response.Response {
 	return response.EmptySyncResponse
 }
 
+// certificateIsRequestor returns whether the request was authenticated with the certificate of the given fingerprint.
+// Only the authenticated requestor is considered as the other certificates of the client chain aren't verified.
+func certificateIsRequestor(r *http.Request, fingerprint string) bool {
+	requestor := request.CreateRequestor(r)
+
+	return requestor.Protocol == api.AuthenticationMethodTLS && requestor.Username == fingerprint
+}
+
+// swagger:operation POST /1.0/certificates/{fingerprint}/renew certificates certificate_renew_post
+//
+//	Renew the trusted certificate
+//
+//	Atomically replaces the certificate by a renewed one.
+//	The renewed certificate keeps the name, type, restrictions, projects and authorization groups of the replaced one.
+//
+//	Clients may renew their own certificate, other certificates require the permission to edit them.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: certificate
+//	    description: Renewed certificate
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/CertificateRenewPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateRenewPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	fingerprint, err := url.PathUnescape(mux.Vars(r)["fingerprint"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Parse the request.
+	req := api.CertificateRenewPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	block, _ := pem.Decode([]byte(req.Certificate))
+	if block == nil {
+		return response.BadRequest(fmt.Errorf("Invalid PEM certificate"))
+	}
+
+	newCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid certificate material: %w", err))
+	}
+
+	err = certificateValidate(newCert)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	newFingerprint := localtls.CertFingerprint(newCert)
+
+	// Get current database record.
+	var dbCert *dbCluster.Certificate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbCert, err = dbCluster.GetCertificateByFingerprintPrefix(ctx, tx.Tx(), fingerprint)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if dbCert.Type == certificate.TypeServer {
+		return response.BadRequest(fmt.Errorf("Server certificates can't be renewed"))
+	}
+
+	// Clients may renew their own certificate.
+	if !certificateIsRequestor(r, dbCert.Fingerprint) {
+		err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectCertificate(dbCert.Fingerprint), auth.EntitlementCanEdit)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	// The renewed certificate must outlive the current one.
+	block, _ = pem.Decode([]byte(dbCert.Certificate))
+	if block != nil {
+		oldCert, err := x509.ParseCertificate(block.Bytes)
+		if err == nil && !newCert.NotAfter.After(oldCert.NotAfter) {
+			return response.BadRequest(fmt.Errorf("The renewed certificate must expire after the current one"))
+		}
+	}
+
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: newCert.Raw}))
+
+	var apiCert *api.Certificate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		exists, err := dbCluster.CertificateExists(ctx, tx.Tx(), newFingerprint)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return api.StatusErrorf(http.StatusConflict, "Certificate already in trust store")
+		}
+
+		err = tx.RenewCertificate(ctx, dbCert.Fingerprint, newFingerprint, certPEM)
+		if err != nil {
+			return err
+		}
+
+		// Any expiry warning is now obsolete.
+		err = dbCluster.DeleteWarnings(ctx, tx.Tx(), dbCluster.TypeCertificate, dbCert.ID)
+		if err != nil {
+			return err
+		}
+
+		renewed, err := dbCluster.GetCertificate(ctx, tx.Tx(), newFingerprint)
+		if err != nil {
+			return err
+		}
+
+		apiCert, err = renewed.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Notify other members about the renewed certificate.
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = notifier(func(client incus.InstanceServer) error {
+		return client.UpdateCertificate(newFingerprint, apiCert.Writable(), "")
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	lc := lifecycle.CertificateRenewed.Event(newFingerprint, request.CreateRequestor(r), logger.Ctx{"old_fingerprint": dbCert.Fingerprint})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
 func certificateValidate(cert *x509.Certificate) error {
 	if time.Now().Before(cert.NotBefore) {
 		return fmt.Errorf("The provided certificate isn't valid yet")
@@ -1285,3 +1455,97 @@ func certificateValidate(cert *x509.Certificate) error {
 
 	return nil
 }
+
+// certificateExpiryCheck raises warnings and lifecycle events for the trusted client certificates expiring within
+// the configured number of days. It only runs on the leader.
+func certificateExpiryCheck(ctx context.Context, s *state.State) error {
+	if s.ServerClustered {
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil {
+			return err
+		}
+
+		// Resolve the warnings raised while this member was the leader.
+		if leader != s.LocalConfig.ClusterAddress() {
+			return warnings.ResolveWarningsByLocalNodeAndType(s.DB.Cluster, warningtype.TrustedCertificateExpiring)
+		}
+	}
+
+	days := s.GlobalConfig.TrustExpiryWarningDays()
+
+	var dbCerts []dbCluster.Certificate
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbCerts, err = dbCluster.GetCertificates(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading certificates: %w", err)
+	}
+
+	for _, dbCert := range dbCerts {
+		if dbCert.Type == certificate.TypeServer {
+			continue
+		}
+
+		block, _ := pem.Decode([]byte(dbCert.Certificate))
+		if block == nil {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+
+		if days <= 0 || time.Until(cert.NotAfter) > time.Duration(days)*24*time.Hour {
+			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningtype.TrustedCertificateExpiring, dbCluster.TypeCertificate, dbCert.ID)
+			if err != nil {
+				logger.Warn("Failed resolving certificate expiry warning", logger.Ctx{"fingerprint": dbCert.Fingerprint, "err": err})
+			}
+
+			continue
+		}
+
+		message := fmt.Sprintf("Certificate %q (%s) expires on %s", dbCert.Name, dbCert.Fingerprint[:12], cert.NotAfter.UTC().Format(time.RFC3339))
+		if time.Now().After(cert.NotAfter) {
+			message = fmt.Sprintf("Certificate %q (%s) expired on %s", dbCert.Name, dbCert.Fingerprint[:12], cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, "", dbCluster.TypeCertificate, dbCert.ID, warningtype.TrustedCertificateExpiring, message)
+		})
+		if err != nil {
+			logger.Warn("Failed raising certificate expiry warning", logger.Ctx{"fingerprint": dbCert.Fingerprint, "err": err})
+		}
+
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.CertificateExpiring.Event(dbCert.Fingerprint, nil, logger.Ctx{"expires_at": cert.NotAfter.UTC()}))
+	}
+
+	return nil
+}
+
+// certificateExpiryTask checks the expiry of the trusted client certificates.
+func certificateExpiryTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := certificateExpiryCheck(ctx, d.State())
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("Failed checking certificate expiry", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Daily()
+}
+
+func certificateRevocationTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := localUtil.RefreshRevocationList(ctx, d.endpoints.NetworkCert().CA())
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("Failed refreshing certificate revocation list", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Hourly()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+func TestCertificateIsRequestor(t *testing.T) {
+	client := localtls.TestingKeyPair()
+	admin := localtls.TestingAltKeyPair()
+
+	clientCert, err := x509.ParseCertificate(client.KeyPair().Certificate[0])
+	require.NoError(t, err)
+
+	adminCert, err := x509.ParseCertificate(admin.KeyPair().Certificate[0])
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		username    string
+		protocol    string
+		fingerprint string
+		expected    bool
+	}{
+		{"Own certificate", client.Fingerprint(), api.AuthenticationMethodTLS, client.Fingerprint(), true},
+		{"Extra certificate in the chain", client.Fingerprint(), api.AuthenticationMethodTLS, admin.Fingerprint(), false},
+		{"Other authentication method", client.Fingerprint(), api.AuthenticationMethodOIDC, client.Fingerprint(), false},
+		{"Unauthenticated", "", "", admin.Fingerprint(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/1.0/certificates/"+tt.fingerprint+"/renew", nil)
+
+			// The client appends the admin certificate to its chain.
+			r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{clientCert, adminCert}}
+
+			if tt.protocol != "" {
+				ctx := context.WithValue(r.Context(), request.CtxUsername, tt.username)
+				ctx = context.WithValue(ctx, request.CtxProtocol, tt.protocol)
+				r = r.WithContext(ctx)
+			}
+
+			assert.Equal(t, tt.expected, certificateIsRequestor(r, tt.fingerprint))
+		})
+	}
+}
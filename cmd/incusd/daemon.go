@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/x509"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -24,6 +25,8 @@ import (
 	"github.com/cowsql/go-cowsql/driver"
 	"github.com/gorilla/mux"
 	liblxc "github.com/lxc/go-lxc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/sys/unix"
 	yaml "gopkg.in/yaml.v2"
 
@@ -32,6 +35,7 @@ import (
 	"github.com/lxc/incus/v6/internal/rsync"
 	"github.com/lxc/incus/v6/internal/server/acme"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/audit"
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/auth/oidc"
 	"github.com/lxc/incus/v6/internal/server/bgp"
@@ -68,6 +72,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/sys"
 	"github.com/lxc/incus/v6/internal/server/syslog"
 	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/internal/server/ucred"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/server/warnings"
@@ -154,13 +159,19 @@ type Daemon struct {
 	serverName      string
 	serverClustered bool
 
-	lokiClient *loki.Client
+	lokiClient   *loki.Client
+	lokiClientMu sync.RWMutex
+
+	// Audit log.
+	auditLog    *audit.Log
+	auditSyslog *audit.SyslogForwarder
 
 	// HTTP-01 challenge provider for ACME
 	http01Provider acme.HTTP01Provider
 
 	// Authorization.
-	authorizer auth.Authorizer
+	authorizer      auth.Authorizer
+	authGroupsCache authGroupsCache
 
 	// Syslog listener cancel function.
 	syslogSocketCancel context.CancelFunc
@@ -416,7 +427,7 @@ func allowPermission(objectType auth.ObjectType, entitlement auth.Entitlement, m
 
 // Convenience function around Authenticate.
 func (d *Daemon) checkTrustedClient(r *http.Request) error {
-	trusted, _, _, err := d.Authenticate(nil, r)
+	trusted, _, _, _, err := d.Authenticate(nil, r)
 	if !trusted || err != nil {
 		if err != nil {
 			return err
@@ -474,12 +485,12 @@ func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.
 // will validate the TLS certificate.
 //
 // This does not perform authorization, only validates authentication.
-// Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, and the type of
-// client that has been authenticated (cluster, unix, or tls).
-func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, error) {
+// Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, the type of
+// client that has been authenticated (cluster, unix, or tls) and the identity provider groups of OIDC users.
+func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, []string, error) {
 	trustedCerts, err := d.getTrustedCertificates()
 	if err != nil {
-		return false, "", "", err
+		return false, "", "", nil, err
 	}
 
 	// Allow internal cluster traffic by checking against the trusted certfificates.
@@ -487,7 +498,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, fingerprint := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeServer], d.endpoints.NetworkCert(), false)
 			if trusted {
-				return true, fingerprint, "cluster", nil
+				return true, fingerprint, "cluster", nil, nil
 			}
 		}
 	}
@@ -497,38 +508,38 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		if w != nil {
 			cred, err := ucred.GetCredFromContext(r.Context())
 			if err != nil {
-				return false, "", "", err
+				return false, "", "", nil, err
 			}
 
 			u, err := user.LookupId(fmt.Sprintf("%d", cred.Uid))
 			if err != nil {
-				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil
+				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil, nil
 			}
 
-			return true, u.Username, "unix", nil
+			return true, u.Username, "unix", nil, nil
 		}
 
-		return true, "", "unix", nil
+		return true, "", "unix", nil, nil
 	}
 
 	// DevIncus unix socket credentials on main API.
 	if r.RemoteAddr == "@dev_incus" {
-		return false, "", "", fmt.Errorf("Main API query can't come from /dev/incus socket")
+		return false, "", "", nil, fmt.Errorf("Main API query can't come from /dev/incus socket")
 	}
 
 	// Cluster notification with wrong certificate.
 	if isClusterNotification(r) {
-		return false, "", "", fmt.Errorf("Cluster notification isn't using trusted server certificate")
+		return false, "", "", nil, fmt.Errorf("Cluster notification isn't using trusted server certificate")
 	}
 
 	// Cluster internal client with wrong certificate.
 	if isClusterInternal(r) {
-		return false, "", "", fmt.Errorf("Cluster internal client isn't using trusted server certificate")
+		return false, "", "", nil, fmt.Errorf("Cluster internal client isn't using trusted server certificate")
 	}
 
 	// Bad query, no TLS found.
 	if r.TLS == nil {
-		return false, "", "", fmt.Errorf("Bad/missing TLS on network query")
+		return false, "", "", nil, fmt.Errorf("Bad/missing TLS on network query")
 	}
 
 	// Load the certificates.
@@ -539,18 +550,29 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	if jwtOk {
 		trusted, username := localUtil.CheckTrustState(*cert, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
+		}
+	}
+
+	// Check for an authentication token signed by this server.
+	signed, ok := authTokenFromHeader(r)
+	if ok {
+		id, err := d.authTokenVerify(r.Context(), signed)
+		if err != nil {
+			return false, "", "", nil, err
 		}
+
+		return true, id, api.AuthenticationMethodToken, nil, nil
 	}
 
 	// Check for JWT token signed by an OpenID Connect provider.
 	if d.oidcVerifier != nil && d.oidcVerifier.IsRequest(r) {
-		userName, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
+		result, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
 		if err != nil {
-			return false, "", "", err
+			return false, "", "", nil, err
 		}
 
-		return true, userName, api.AuthenticationMethodOIDC, nil
+		return true, result.Username, api.AuthenticationMethodOIDC, result.IdentityProviderGroups, nil
 	}
 
 	// Validate metrics TLS certificates.
@@ -558,7 +580,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeMetrics], d.endpoints.NetworkCert(), trustCACertificates)
 			if trusted {
-				return true, username, api.AuthenticationMethodTLS, nil
+				return true, username, api.AuthenticationMethodTLS, nil, nil
 			}
 		}
 	}
@@ -567,12 +589,12 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	for _, i := range r.TLS.PeerCertificates {
 		trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
 		}
 	}
 
 	// Reject unauthorized.
-	return false, "", "", nil
+	return false, "", "", nil, nil
 }
 
 // State creates a new State instance linked to our internal db and os.
@@ -646,7 +668,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 		}
 
 		// Authentication
-		trusted, username, protocol, err := d.Authenticate(w, r)
+		trusted, username, protocol, identityProviderGroups, err := d.Authenticate(w, r)
 		if err != nil {
 			_, ok := err.(*oidc.AuthError)
 			if ok {
@@ -655,6 +677,10 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 					_ = d.oidcVerifier.WriteHeaders(w)
 				}
 
+				if d.auditLog != nil {
+					d.auditRecord(r, http.StatusUnauthorized, "")
+				}
+
 				_ = response.Unauthorized(err).Render(w)
 				return
 			}
@@ -665,6 +691,10 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			// Except for the initial cluster accept request (done over trusted TLS)
 			if !trusted || c.Path != "cluster/accept" || protocol != api.AuthenticationMethodTLS {
 				logger.Warn("Rejecting remote internal API request", logger.Ctx{"ip": r.RemoteAddr})
+				if d.auditLog != nil {
+					d.auditRecord(r, http.StatusForbidden, "")
+				}
+
 				_ = response.Forbidden(nil).Render(w)
 				return
 			}
@@ -685,12 +715,26 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			ctx := context.WithValue(r.Context(), request.CtxUsername, username)
 			ctx = context.WithValue(ctx, request.CtxProtocol, protocol)
 
+			if identityProviderGroups != nil {
+				ctx = context.WithValue(ctx, request.CtxIdentityProviderGroups, identityProviderGroups)
+			}
+
 			// Add forwarded requestor data.
 			if protocol == "cluster" {
 				// Add authentication/authorization context data.
 				ctx = context.WithValue(ctx, request.CtxForwardedAddress, r.Header.Get(request.HeaderForwardedAddress))
 				ctx = context.WithValue(ctx, request.CtxForwardedUsername, r.Header.Get(request.HeaderForwardedUsername))
 				ctx = context.WithValue(ctx, request.CtxForwardedProtocol, r.Header.Get(request.HeaderForwardedProtocol))
+
+				forwardedGroups := r.Header.Get(request.HeaderForwardedIdentityProviderGroups)
+				if forwardedGroups != "" {
+					var groups []string
+
+					err := json.Unmarshal([]byte(forwardedGroups), &groups)
+					if err == nil {
+						ctx = context.WithValue(ctx, request.CtxForwardedIdentityProviderGroups, groups)
+					}
+				}
 			}
 
 			r = r.WithContext(ctx)
@@ -702,6 +746,10 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			}
 
 			logger.Warn("Rejecting request from untrusted client", logger.Ctx{"ip": r.RemoteAddr})
+			if d.auditLog != nil {
+				d.auditRecord(r, http.StatusForbidden, "")
+			}
+
 			_ = response.Forbidden(nil).Render(w)
 			return
 		}
@@ -721,6 +769,17 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			localUtil.DebugJSON("API Request", captured, logger.AddContext(logCtx))
 		}
 
+		// Trace the request, continuing any trace started by the client or another cluster member.
+		ctx, span := tracing.Start(tracing.Extract(r.Context(), r.Header), fmt.Sprintf("%s %s", r.Method, uri),
+			attribute.String("http.request.method", r.Method),
+			attribute.String("url.path", r.URL.Path),
+			attribute.String("incus.protocol", protocol),
+		)
+
+		defer span.End()
+
+		r = r.WithContext(ctx)
+
 		// Actually process the request
 		var resp response.Response
 
@@ -795,6 +854,11 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			resp = response.NotFound(fmt.Errorf("Method %q not found", r.Method))
 		}
 
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.Code()))
+		if resp.Code() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(resp.Code()))
+		}
+
 		// If sending out Forbidden, make sure we have OIDC headers.
 		if resp.Code() == http.StatusForbidden && d.oidcVerifier != nil {
 			_ = d.oidcVerifier.WriteHeaders(w)
@@ -808,6 +872,12 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": uri, "err": err, "writeErr": writeErr})
 			}
 		}
+
+		// Record mutating requests in the audit log.
+		// Requests forwarded by other cluster members were already recorded by them.
+		if d.auditLog != nil && r.Method != "GET" && r.Method != "HEAD" && version != "internal" && protocol != "cluster" {
+			d.auditRecord(r, resp.Code(), w.Header().Get("Location"))
+		}
 	})
 
 	// If the endpoint has a canonical name then record it so it can be used to build URLS
@@ -873,11 +943,30 @@ func (d *Daemon) Init() error {
 	return nil
 }
 
+// getLokiClient returns the current loki client, if any.
+// The client can be replaced or stopped at any time following configuration changes.
+func (d *Daemon) getLokiClient() *loki.Client {
+	d.lokiClientMu.RLock()
+	defer d.lokiClientMu.RUnlock()
+
+	return d.lokiClient
+}
+
+// stopLoki stops and clears the current loki client, if any.
+func (d *Daemon) stopLoki() {
+	d.lokiClientMu.Lock()
+	client := d.lokiClient
+	d.lokiClient = nil
+	d.lokiClientMu.Unlock()
+
+	if client != nil {
+		client.Stop()
+	}
+}
+
 func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, instanceName string, logLevel string, labels []string, types []string) error {
 	// Stop any existing loki client.
-	if d.lokiClient != nil {
-		d.lokiClient.Stop()
-	}
+	d.stopLoki()
 
 	// Check basic requirements for starting a new client.
 	if URL == "" || logLevel == "" || len(types) == 0 {
@@ -907,10 +996,18 @@ func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, i
 	}
 
 	// Start a new client.
-	d.lokiClient = loki.NewClient(d.shutdownCtx, u, cert, key, caCert, instanceName, location, logLevel, labels, types)
+	client := loki.NewClient(d.shutdownCtx, u, cert, key, caCert, instanceName, location, logLevel, labels, types)
+
+	d.lokiClientMu.Lock()
+	d.lokiClient = client
+	d.lokiClientMu.Unlock()
 
 	// Attach the new client to the log handler.
-	d.internalListener.AddHandler("loki", d.lokiClient.HandleEvent)
+	d.internalListener.AddHandler("loki", client.HandleEvent)
+	d.auditLog.AddHandler("loki", client.HandleAuditEntry)
+
+	// Ship the logs of instances that requested it.
+	d.internalListener.AddHandler("instance-logs", instanceLogsHandleEvent(d))
 
 	return nil
 }
@@ -921,7 +1018,7 @@ func (d *Daemon) init() error {
 	var dbWarnings []dbCluster.Warning
 
 	// Set default authorizer.
-	d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+	d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithGroupsFunc(d.authGroups), auth.WithTokensFunc(d.authToken))
 	if err != nil {
 		return err
 	}
@@ -932,6 +1029,18 @@ func (d *Daemon) init() error {
 	// Setup internal event listener
 	d.internalListener = events.NewInternalListener(d.shutdownCtx, d.events)
 
+	// Check the health of instances following their lifecycle.
+	d.internalListener.AddHandler("instance-healthchecks", instanceHealthChecksHandleEvent(d))
+
+	// Start instances on connection and stop them when idle following their lifecycle.
+	d.internalListener.AddHandler("instance-activations", instanceActivationsHandleEvent(d))
+
+	// Release the resources held by the seccomp handler for instances which stop or go away.
+	d.internalListener.AddHandler("seccomp", seccompHandleEvent(d))
+
+	// Keep the resource usage history of instances in line with their name.
+	d.internalListener.AddHandler("instance-history", instanceHistoryHandleEvent)
+
 	// Lets check if there's an existing daemon running
 	err = endpoints.CheckAlreadyRunning(d.os.GetUnixSocket())
 	if err != nil {
@@ -1185,6 +1294,12 @@ func (d *Daemon) init() error {
 		return err
 	}
 
+	// Open the audit log, keyed with the server certificate.
+	d.auditLog, err = audit.Open(internalUtil.VarPath("audit.log"), auditKey(serverCert))
+	if err != nil {
+		return fmt.Errorf("Failed opening the audit log: %w", err)
+	}
+
 	// Load cached local trusted certificates before starting listener and cluster database.
 	err = updateCertificateCacheFromLocal(d)
 	if err != nil {
@@ -1473,13 +1588,19 @@ func (d *Daemon) init() error {
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := d.globalConfig.LokiServer()
-	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := d.globalConfig.OIDCServer()
+	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim := d.globalConfig.OIDCServer()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
+	clusterRebalanceScriptlet := d.globalConfig.ClusterRebalanceScriptlet()
+	admissionScriptlet := d.globalConfig.AdmissionScriptlet()
+	otlpEndpoint, otlpHeaders, otlpCACert := d.globalConfig.OTLPServer()
 	authorizationScriptlet := d.globalConfig.AuthorizationScriptlet()
+	authorizationRBAC := d.globalConfig.AuthorizationRBAC()
+	auditSyslog := d.globalConfig.AuditSyslog()
 
 	d.endpoints.NetworkUpdateTrustedProxy(d.globalConfig.HTTPSTrustedProxy())
+	daemonConfigSetRevocation(d, d.globalConfig)
 	d.globalConfigMu.Unlock()
 
 	// Setup Loki logger.
@@ -1490,6 +1611,22 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup audit log forwarding.
+	if auditSyslog != "" {
+		err = d.setupAuditSyslog(auditSyslog)
+		if err != nil {
+			logger.Warn("Failed forwarding the audit log to syslog", logger.Ctx{"err": err})
+		}
+	}
+
+	// Setup OpenTelemetry exporter.
+	if otlpEndpoint != "" {
+		err = tracing.Setup(otlpEndpoint, otlpHeaders, otlpCACert, d.serverName)
+		if err != nil {
+			logger.Warn("Failed setting up OpenTelemetry exporter", logger.Ctx{"err": err})
+		}
+	}
+
 	// Setup syslog listener.
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
@@ -1500,7 +1637,7 @@ func (d *Daemon) init() error {
 
 	// Setup OIDC authentication.
 	if oidcIssuer != "" && oidcClientID != "" {
-		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
+		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim)
 		if err != nil {
 			return err
 		}
@@ -1522,6 +1659,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup the built-in role based authorization.
+	if authorizationRBAC {
+		err = d.setupRBAC(true)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Setup BGP listener.
 	d.bgp = bgp.NewServer()
 	if bgpAddress != "" && bgpASN != 0 && bgpRouterID != "" {
@@ -1613,6 +1758,22 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Load cluster re-balancing scriptlet.
+	if clusterRebalanceScriptlet != "" {
+		err = scriptletLoad.ClusterRebalanceSet(clusterRebalanceScriptlet)
+		if err != nil {
+			logger.Warn("Failed loading cluster re-balancing scriptlet", logger.Ctx{"err": err})
+		}
+	}
+
+	// Load admission scriptlet.
+	if admissionScriptlet != "" {
+		err = scriptletLoad.AdmissionSet(admissionScriptlet)
+		if err != nil {
+			logger.Warn("Failed loading admission scriptlet", logger.Ctx{"err": err})
+		}
+	}
+
 	// Apply all patches that need to be run after networks are initialized.
 	err = patchesApply(d, patchPostNetworks)
 	if err != nil {
@@ -1650,6 +1811,15 @@ func (d *Daemon) init() error {
 		// This should come after the event handler go routines have been started.
 		devicesRegister(instances)
 
+		// Resume shipping the logs of running instances.
+		instanceLogsRegister(d, instances)
+
+		// Resume checking the health of running instances.
+		instanceHealthChecksRegister(d, instances)
+
+		// Listen on behalf of stopped instances started on connection and track the activity of running ones.
+		instanceActivationsRegister(d, instances)
+
 		// Setup seccomp handler
 		if d.os.SeccompListener {
 			seccompServer, err := seccomp.NewSeccompServer(d.State(), internalUtil.RunPath("seccomp.socket"), func(pid int32, state *state.State) (seccomp.Instance, error) {
@@ -1734,8 +1904,23 @@ func (d *Daemon) init() error {
 		// Auto-renew server certificate (daily)
 		d.tasks.Add(autoRenewCertificateTask(d))
 
+		// Warn about expiring client certificates (daily)
+		d.tasks.Add(certificateExpiryTask(d))
+
+		// Refresh the CA certificate revocation list (hourly)
+		d.tasks.Add(certificateRevocationTask(d))
+
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Record instance resource usage history (minutely)
+		d.tasks.Add(instanceHistoryTask(d))
+
+		// Account project resource usage (every 15 minutes)
+		d.tasks.Add(projectUsageTask(d))
+
+		// Sample the network load (every 5 seconds)
+		d.tasks.Add(networkLoadTask())
 	}
 
 	// Start all background tasks
@@ -1961,6 +2146,9 @@ func (d *Daemon) Stop(ctx context.Context, sig os.Signal) error {
 		trackError(d.seccomp.Stop(), "Stop seccomp")
 	}
 
+	// Flush any pending span.
+	trackError(tracing.Shutdown(ctx), "Stop tracing")
+
 	n = len(errs)
 	if n > 0 {
 		format := "%v"
@@ -1991,7 +2179,7 @@ func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) er
 
 	if apiURL == "" || apiToken == "" || storeID == "" {
 		// Reset to default authorizer.
-		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithGroupsFunc(d.authGroups), auth.WithTokensFunc(d.authToken))
 		if err != nil {
 			return err
 		}
@@ -2010,7 +2198,7 @@ func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) er
 
 	revert.Add(func() {
 		// Reset to default authorizer.
-		d.authorizer, _ = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+		d.authorizer, _ = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithGroupsFunc(d.authGroups), auth.WithTokensFunc(d.authToken))
 	})
 
 	// Build the list of resources to update the model.
@@ -2242,7 +2430,7 @@ func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) er
 		return &resources, nil
 	}
 
-	openfgaAuthorizer, err := auth.LoadAuthorizer(d.shutdownCtx, auth.DriverOpenFGA, logger.Log, d.clientCerts, auth.WithConfig(config), auth.WithResourcesFunc(refreshResources))
+	openfgaAuthorizer, err := auth.LoadAuthorizer(d.shutdownCtx, auth.DriverOpenFGA, logger.Log, d.clientCerts, auth.WithConfig(config), auth.WithResourcesFunc(refreshResources), auth.WithTokensFunc(d.authToken))
 	if err != nil {
 		return err
 	}
@@ -2261,8 +2449,13 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 	}
 
 	if scriptlet == "" {
+		// Leave any other authorizer alone.
+		if d.authorizer.Driver() != auth.DriverScriptlet {
+			return nil
+		}
+
 		// Reset to default authorizer.
-		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithGroupsFunc(d.authGroups), auth.WithTokensFunc(d.authToken))
 		if err != nil {
 			return err
 		}
@@ -2271,9 +2464,9 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 	}
 
 	// Fail if not using the default tls or scriptlet authorizer.
-	switch d.authorizer.(type) {
-	case *auth.TLS, *auth.Scriptlet:
-		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverScriptlet, logger.Log, d.clientCerts)
+	switch d.authorizer.Driver() {
+	case auth.DriverTLS, auth.DriverScriptlet:
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverScriptlet, logger.Log, d.clientCerts, auth.WithGroupsFunc(d.authGroups), auth.WithTokensFunc(d.authToken))
 		if err != nil {
 			return err
 		}
@@ -2285,6 +2478,82 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 	return nil
 }
 
+// authGroups returns the authorization groups, it is called whenever a permission is checked.
+// The groups are cached until they change, see authGroupsChanged.
+func (d *Daemon) authGroups(ctx context.Context) ([]api.AuthGroup, error) {
+	groups, generation := d.authGroupsCache.get()
+	if groups != nil {
+		return groups, nil
+	}
+
+	err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		groups, err = tx.GetAuthGroups(ctx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.authGroupsCache.set(groups, generation)
+
+	return groups, nil
+}
+
+// authToken loads an authentication token from the database, it is called whenever a token user's permission is checked.
+func (d *Daemon) authToken(ctx context.Context, id string) (*api.AuthToken, error) {
+	var token *api.AuthToken
+
+	err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		token, err = tx.GetAuthToken(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Setup built-in role based authorization.
+func (d *Daemon) setupRBAC(enabled bool) error {
+	var err error
+
+	if !enabled {
+		// Leave any other authorizer alone.
+		if d.authorizer.Driver() != auth.DriverRBAC {
+			return nil
+		}
+
+		// Reset to default authorizer.
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts, auth.WithGroupsFunc(d.authGroups), auth.WithTokensFunc(d.authToken))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Fail if not using the default tls or rbac authorizer.
+	switch d.authorizer.Driver() {
+	case auth.DriverTLS, auth.DriverRBAC:
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverRBAC, logger.Log, d.clientCerts, auth.WithGroupsFunc(d.authGroups), auth.WithTokensFunc(d.authToken))
+		if err != nil {
+			return err
+		}
+
+	default:
+		return errors.New("Attempting to setup role based authorization while another authorizer is already set")
+	}
+
+	return nil
+}
+
 // Syslog listener.
 func (d *Daemon) setupSyslogSocket(enable bool) error {
 	// Always cancel the context to ensure that no goroutines leak.
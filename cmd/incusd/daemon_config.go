@@ -7,6 +7,8 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/node"
 	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/proxy"
 )
 
@@ -46,3 +48,14 @@ func daemonConfigSetProxy(d *Daemon, config *clusterConfig.Config) {
 		config.ProxyIgnoreHosts(),
 	)
 }
+
+func daemonConfigSetRevocation(d *Daemon, config *clusterConfig.Config) {
+	// Revocation services are reached through the configured proxy.
+	client, err := localUtil.HTTPClient("", d.proxy)
+	if err != nil {
+		logger.Warn("Failed setting up revocation checks", logger.Ctx{"err": err})
+		return
+	}
+
+	localUtil.SetRevocationConfig(config.TrustCACRLURL(), config.TrustCAOCSP(), client)
+}
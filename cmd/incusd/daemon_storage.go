@@ -21,6 +21,7 @@ import (
 func daemonStorageVolumesUnmount(s *state.State) error {
 	var storageBackups string
 	var storageImages string
+	var storageRecordings string
 
 	err := s.DB.Node.Transaction(context.Background(), func(ctx context.Context, tx *db.NodeTx) error {
 		nodeConfig, err := node.ConfigLoad(ctx, tx)
@@ -30,6 +31,7 @@ func daemonStorageVolumesUnmount(s *state.State) error {
 
 		storageBackups = nodeConfig.StorageBackupsVolume()
 		storageImages = nodeConfig.StorageImagesVolume()
+		storageRecordings = nodeConfig.StorageRecordingsVolume()
 
 		return nil
 	})
@@ -72,12 +74,20 @@ func daemonStorageVolumesUnmount(s *state.State) error {
 		}
 	}
 
+	if storageRecordings != "" {
+		err := unmount("recordings", storageRecordings)
+		if err != nil {
+			return fmt.Errorf("Failed to unmount recordings storage: %w", err)
+		}
+	}
+
 	return nil
 }
 
 func daemonStorageMount(s *state.State) error {
 	var storageBackups string
 	var storageImages string
+	var storageRecordings string
 	err := s.DB.Node.Transaction(context.Background(), func(ctx context.Context, tx *db.NodeTx) error {
 		nodeConfig, err := node.ConfigLoad(ctx, tx)
 		if err != nil {
@@ -86,6 +96,7 @@ func daemonStorageMount(s *state.State) error {
 
 		storageBackups = nodeConfig.StorageBackupsVolume()
 		storageImages = nodeConfig.StorageImagesVolume()
+		storageRecordings = nodeConfig.StorageRecordingsVolume()
 
 		return nil
 	})
@@ -137,6 +148,13 @@ func daemonStorageMount(s *state.State) error {
 		}
 	}
 
+	if storageRecordings != "" {
+		err := mount("recordings", storageRecordings)
+		if err != nil {
+			return fmt.Errorf("Failed to mount recordings storage: %w", err)
+		}
+	}
+
 	return nil
 }
 
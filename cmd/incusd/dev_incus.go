@@ -225,7 +225,14 @@ var devIncusAPIHandler = devIncusHandler{"/1.0", func(d *Daemon, c instance.Inst
 			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusBadRequest, "Invalid state %q", req.State), c.Type() == instancetype.VM)
 		}
 
-		err = c.VolatileSet(map[string]string{"volatile.last_state.ready": strconv.FormatBool(state == api.Ready)})
+		volatile := map[string]string{"volatile.last_state.ready": strconv.FormatBool(state == api.Ready)}
+
+		// Remember that the instance signals its readiness, as waited for by boot.depends_on.
+		if state == api.Ready && !util.IsTrue(c.LocalConfig()["volatile.ready_reported"]) {
+			volatile["volatile.ready_reported"] = "true"
+		}
+
+		err = c.VolatileSet(volatile)
 		if err != nil {
 			return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, err.Error()), c.Type() == instancetype.VM)
 		}
@@ -677,7 +677,7 @@ func pruneExpiredAndAutoCreateInstanceSnapshotsTask(d *Daemon) (task.Func, task.
 
 		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
-				err = project.AllowSnapshotCreation(&p)
+				err = project.AllowSnapshotCreation(ctx, tx, &p)
 				if err != nil {
 					return nil
 				}
@@ -0,0 +1,553 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/device"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// instanceActivationConnectTimeout is how long the connections which started an instance are held for while
+// waiting for the service behind the proxy to be reachable.
+const instanceActivationConnectTimeout = 2 * time.Minute
+
+// instanceActivationStart tracks the start of an instance following a connection.
+type instanceActivationStart struct {
+	done chan struct{}
+	err  error
+}
+
+// instanceActivation tracks the socket activation and idle stop of an instance.
+type instanceActivation struct {
+	// Proxy devices listening on behalf of the stopped instance.
+	devices map[string]deviceConfig.Device
+
+	// Set while the instance is being started following a connection.
+	start *instanceActivationStart
+
+	// Number of connections currently relayed to the instance after starting it.
+	relays int
+
+	// Idle timer of the running instance along with the boot.idle_stop value it was started with.
+	idleCancel context.CancelFunc
+	idleStop   string
+}
+
+// instanceActivations keeps track of the instances using socket activation or idle stop.
+var instanceActivations = map[string]*instanceActivation{}
+var instanceActivationsMu sync.Mutex
+
+// instanceActivationsRegister starts listening for the stopped instances and tracking the activity of the running ones.
+func instanceActivationsRegister(d *Daemon, instances []instance.Instance) {
+	for _, inst := range instances {
+		instanceActivationSync(d, inst)
+	}
+}
+
+// instanceActivationsHandleEvent updates the socket activation and idle stop following the instance lifecycle events.
+func instanceActivationsHandleEvent(d *Daemon) func(event api.Event) {
+	return func(event api.Event) {
+		if event.Type != api.EventTypeLifecycle {
+			return
+		}
+
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return
+		}
+
+		if !slices.Contains([]string{api.EventLifecycleInstanceCreated, api.EventLifecycleInstanceStarted, api.EventLifecycleInstanceRestarted, api.EventLifecycleInstanceUpdated, api.EventLifecycleInstanceStopped, api.EventLifecycleInstanceShutdown, api.EventLifecycleInstanceDeleted, api.EventLifecycleInstanceRenamed, api.EventLifecycleInstanceMigrated}, lifecycleEvent.Action) {
+			return
+		}
+
+		projectName := lifecycleEvent.Project
+		if projectName == "" {
+			projectName = api.ProjectDefaultName
+		}
+
+		if lifecycleEvent.Action == api.EventLifecycleInstanceRenamed {
+			oldName, ok := lifecycleEvent.Context["old_name"].(string)
+			if ok {
+				instanceActivationRemove(projectName, oldName)
+			}
+		}
+
+		if lifecycleEvent.Action == api.EventLifecycleInstanceDeleted {
+			instanceActivationRemove(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		inst, err := instance.LoadByProjectAndName(d.State(), projectName, lifecycleEvent.Name)
+		if err != nil {
+			instanceActivationRemove(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		// Only listen for and track local instances.
+		if d.serverClustered && inst.Location() != d.serverName {
+			instanceActivationRemove(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		instanceActivationSync(d, inst)
+	}
+}
+
+// instanceActivationDevices returns the proxy devices which start their instance on connection.
+func instanceActivationDevices(expandedDevices deviceConfig.Devices) map[string]deviceConfig.Device {
+	devices := map[string]deviceConfig.Device{}
+	for devName, devConfig := range expandedDevices {
+		if devConfig["type"] == "proxy" && util.IsTrue(devConfig["start_on_connect"]) {
+			devices[devName] = devConfig
+		}
+	}
+
+	return devices
+}
+
+// instanceActivationSync listens on behalf of a stopped instance or tracks the activity of a running one
+// based on its state and configuration.
+func instanceActivationSync(d *Daemon, inst instance.Instance) {
+	key := inst.Project().Name + "/" + inst.Name()
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+	instanceActivationsMu.Lock()
+	defer instanceActivationsMu.Unlock()
+
+	activation, ok := instanceActivations[key]
+	if !ok {
+		activation = &instanceActivation{}
+		instanceActivations[key] = activation
+	}
+
+	defer instanceActivationCleanup(key, activation)
+
+	if inst.IsRunning() {
+		// The proxy devices release their listeners when starting, make sure none are left behind.
+		for devName := range activation.devices {
+			device.ProxyActivationClose(inst.Project().Name, inst.Name(), devName)
+		}
+
+		activation.devices = nil
+
+		// Restart the idle timer if its configuration changed.
+		idleStop := inst.ExpandedConfig()["boot.idle_stop"]
+		if activation.idleCancel != nil && activation.idleStop == idleStop {
+			return
+		}
+
+		if activation.idleCancel != nil {
+			activation.idleCancel()
+			activation.idleCancel = nil
+		}
+
+		minutes, err := strconv.Atoi(idleStop)
+		if err != nil || minutes <= 0 {
+			return
+		}
+
+		ctx, cancel := context.WithCancel(d.shutdownCtx)
+		activation.idleCancel = cancel
+		activation.idleStop = idleStop
+
+		go instanceIdleStopRun(ctx, d, inst.Project().Name, inst.Name(), time.Duration(minutes)*time.Minute)
+
+		return
+	}
+
+	if activation.idleCancel != nil {
+		activation.idleCancel()
+		activation.idleCancel = nil
+	}
+
+	// Leave the listeners alone while the instance is being started.
+	if activation.start != nil || d.shutdownCtx.Err() != nil {
+		return
+	}
+
+	devices := instanceActivationDevices(inst.ExpandedDevices())
+	if maps.EqualFunc(activation.devices, devices, func(a deviceConfig.Device, b deviceConfig.Device) bool { return maps.Equal(a, b) }) {
+		return
+	}
+
+	for devName := range activation.devices {
+		device.ProxyActivationClose(inst.Project().Name, inst.Name(), devName)
+	}
+
+	activation.devices = nil
+
+	for devName, devConfig := range devices {
+		err := device.ProxyActivationListen(inst, devName, devConfig, func(conn net.Conn, portIndex int) {
+			instanceActivationHandle(d, inst.Project().Name, inst.Name(), devName, portIndex, conn)
+		})
+		if err != nil {
+			l.Warn("Failed listening for connections to start the instance", logger.Ctx{"device": devName, "err": err})
+			continue
+		}
+
+		if activation.devices == nil {
+			activation.devices = map[string]deviceConfig.Device{}
+		}
+
+		activation.devices[devName] = devConfig
+	}
+}
+
+// instanceActivationCleanup forgets about an instance once there's nothing left to track for it.
+// The caller must hold instanceActivationsMu.
+func instanceActivationCleanup(key string, activation *instanceActivation) {
+	if activation.devices != nil || activation.start != nil || activation.relays > 0 || activation.idleCancel != nil {
+		return
+	}
+
+	if instanceActivations[key] == activation {
+		delete(instanceActivations, key)
+	}
+}
+
+// instanceActivationRemove stops listening for and tracking the activity of an instance.
+func instanceActivationRemove(projectName string, instanceName string) {
+	key := projectName + "/" + instanceName
+
+	instanceActivationsMu.Lock()
+	defer instanceActivationsMu.Unlock()
+
+	activation, ok := instanceActivations[key]
+	if !ok {
+		return
+	}
+
+	for devName := range activation.devices {
+		device.ProxyActivationClose(projectName, instanceName, devName)
+	}
+
+	if activation.idleCancel != nil {
+		activation.idleCancel()
+	}
+
+	delete(instanceActivations, key)
+}
+
+// instanceActivationHandle starts the instance following a connection to one of its proxy devices, then relays the
+// connection to the service behind the proxy once it's reachable.
+func instanceActivationHandle(d *Daemon, projectName string, instanceName string, devName string, portIndex int, conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	s := d.State()
+	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": instanceName, "device": devName, "remote": conn.RemoteAddr().String()})
+
+	err := instanceActivationWaitStart(d, projectName, instanceName)
+	if err != nil {
+		l.Warn("Failed starting instance on connection", logger.Ctx{"err": err})
+		return
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, instanceName)
+	if err != nil {
+		l.Warn("Failed loading instance", logger.Ctx{"err": err})
+		return
+	}
+
+	devConfig, ok := inst.ExpandedDevices()[devName]
+	if !ok {
+		return
+	}
+
+	// Hold the connection until the service behind the proxy is reachable.
+	ctx, cancel := context.WithTimeout(d.shutdownCtx, instanceActivationConnectTimeout)
+	defer cancel()
+
+	var target net.Conn
+	for {
+		target, err = device.ProxyActivationDial(ctx, s, inst, devConfig, portIndex)
+		if err == nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			l.Warn("Target of proxy isn't reachable, dropping connection", logger.Ctx{"err": err})
+			return
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	defer func() { _ = target.Close() }()
+
+	instanceActivationRelay(projectName+"/"+instanceName, conn, target)
+}
+
+// instanceActivationWaitStart starts the instance if this isn't already in progress and waits for it to be running.
+func instanceActivationWaitStart(d *Daemon, projectName string, instanceName string) error {
+	key := projectName + "/" + instanceName
+
+	instanceActivationsMu.Lock()
+	activation, ok := instanceActivations[key]
+	if !ok {
+		activation = &instanceActivation{}
+		instanceActivations[key] = activation
+	}
+
+	start := activation.start
+	if start == nil {
+		start = &instanceActivationStart{done: make(chan struct{})}
+		activation.start = start
+
+		go func() {
+			start.err = instanceActivationStartInstance(d, projectName, instanceName)
+
+			instanceActivationsMu.Lock()
+			activation.start = nil
+
+			// The proxy devices release the listeners when starting, so forget about them if the start failed
+			// part way in order to listen again below.
+			if start.err != nil {
+				for devName := range activation.devices {
+					device.ProxyActivationClose(projectName, instanceName, devName)
+				}
+
+				activation.devices = nil
+			}
+
+			instanceActivationCleanup(key, activation)
+			instanceActivationsMu.Unlock()
+
+			close(start.done)
+
+			// Listen again if the instance couldn't be started.
+			if start.err != nil {
+				inst, err := instance.LoadByProjectAndName(d.State(), projectName, instanceName)
+				if err == nil {
+					instanceActivationSync(d, inst)
+				}
+			}
+		}()
+	}
+
+	instanceActivationsMu.Unlock()
+
+	<-start.done
+
+	return start.err
+}
+
+// instanceActivationStartInstance starts a stopped instance, waiting for its dependencies first.
+func instanceActivationStartInstance(d *Daemon, projectName string, instanceName string) error {
+	s := d.State()
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, instanceName)
+	if err != nil {
+		return err
+	}
+
+	if inst.IsRunning() {
+		return nil
+	}
+
+	logger.Info("Starting instance following connection", logger.Ctx{"project": projectName, "instance": instanceName})
+
+	err = instanceDependenciesWait(s, inst)
+	if err != nil {
+		return err
+	}
+
+	err = inst.Start(false)
+	if err != nil && !inst.IsRunning() {
+		return err
+	}
+
+	return nil
+}
+
+// instanceActivationRelay copies the data between a connection and the service behind the proxy until both sides
+// are done, accounting it as instance activity.
+func instanceActivationRelay(key string, conn net.Conn, target net.Conn) {
+	instanceActivationsMu.Lock()
+	activation, ok := instanceActivations[key]
+	if !ok {
+		activation = &instanceActivation{}
+		instanceActivations[key] = activation
+	}
+
+	activation.relays++
+	instanceActivationsMu.Unlock()
+
+	defer func() {
+		instanceActivationsMu.Lock()
+		activation.relays--
+		instanceActivationCleanup(key, activation)
+		instanceActivationsMu.Unlock()
+	}()
+
+	done := make(chan struct{}, 2)
+	relay := func(dst net.Conn, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+
+		// Propagate the end of the stream while still allowing data in the other direction.
+		tcpConn, ok := dst.(*net.TCPConn)
+		if ok {
+			_ = tcpConn.CloseWrite()
+		} else {
+			_ = dst.Close()
+		}
+
+		done <- struct{}{}
+	}
+
+	go relay(target, conn)
+	go relay(conn, target)
+
+	<-done
+	<-done
+}
+
+// instanceActivationRelays returns the number of connections currently relayed to the instance.
+func instanceActivationRelays(projectName string, instanceName string) int {
+	instanceActivationsMu.Lock()
+	defer instanceActivationsMu.Unlock()
+
+	activation, ok := instanceActivations[projectName+"/"+instanceName]
+	if !ok {
+		return 0
+	}
+
+	return activation.relays
+}
+
+// instanceIdleStopActive returns whether connections are going through the proxy devices of the instance, along with
+// whether it has any host-bound tcp proxy device to check.
+func instanceIdleStopActive(d *Daemon, inst instance.Instance) (bool, bool, error) {
+	if instanceActivationRelays(inst.Project().Name, inst.Name()) > 0 {
+		return true, true, nil
+	}
+
+	found := false
+	for _, devConfig := range inst.ExpandedDevices() {
+		if devConfig["type"] != "proxy" || (devConfig["bind"] != "" && devConfig["bind"] != "host") {
+			continue
+		}
+
+		listenAddr, err := network.ProxyParseAddr(devConfig["listen"])
+		if err != nil || listenAddr.ConnType != "tcp" {
+			continue
+		}
+
+		found = true
+
+		count, err := device.ProxyConnections(d.State(), inst, devConfig)
+		if err != nil {
+			return false, true, err
+		}
+
+		if count > 0 {
+			return true, true, nil
+		}
+	}
+
+	return false, found, nil
+}
+
+// instanceIdleStopDue returns whether an instance has been idle for long enough to be stopped.
+// Instances whose activity can't be checked, as they don't have any host-bound tcp proxy device, are never idle.
+func instanceIdleStopDue(lastActivity time.Time, now time.Time, timeout time.Duration, active bool, found bool) bool {
+	if active || !found {
+		return false
+	}
+
+	return now.Sub(lastActivity) >= timeout
+}
+
+// instanceIdleStopShutdown cleanly shuts down an idle instance.
+// Unlike for unhealthy instances, an instance which doesn't shut down in time is left running rather than forced to stop.
+func instanceIdleStopShutdown(inst instance.Instance) error {
+	timeout := evacuateHostShutdownDefaultTimeout
+
+	value, err := strconv.Atoi(inst.ExpandedConfig()["boot.host_shutdown_timeout"])
+	if err == nil {
+		timeout = value
+	}
+
+	err = inst.Shutdown(time.Duration(timeout) * time.Second)
+	if err != nil && !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
+		return err
+	}
+
+	return nil
+}
+
+// instanceIdleStopRun shuts down the instance once no connections went through its proxy devices for the given time.
+func instanceIdleStopRun(ctx context.Context, d *Daemon, projectName string, instanceName string, timeout time.Duration) {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": instanceName})
+	l.Debug("Starting instance idle timer", logger.Ctx{"timeout": timeout})
+	defer l.Debug("Stopped instance idle timer")
+
+	lastActivity := time.Now()
+	warned := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Minute):
+		}
+
+		// Reload the instance to account for device changes.
+		inst, err := instance.LoadByProjectAndName(d.State(), projectName, instanceName)
+		if err != nil {
+			continue
+		}
+
+		active, found, err := instanceIdleStopActive(d, inst)
+		if err != nil {
+			// Never stop an instance whose activity can't be determined.
+			if !warned {
+				l.Warn("Failed checking instance activity", logger.Ctx{"err": err})
+				warned = true
+			}
+
+			lastActivity = time.Now()
+			continue
+		}
+
+		now := time.Now()
+		if active || !found {
+			lastActivity = now
+		}
+
+		if !instanceIdleStopDue(lastActivity, now, timeout, active, found) {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.Info("Stopping idle instance", logger.Ctx{"idle": time.Since(lastActivity).Round(time.Minute)})
+
+		err = instanceIdleStopShutdown(inst)
+		if err != nil {
+			l.Error("Failed stopping idle instance", logger.Ctx{"err": err})
+			lastActivity = time.Now()
+			continue
+		}
+
+		return
+	}
+}
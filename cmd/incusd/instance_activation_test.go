@@ -0,0 +1,116 @@
+package main
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+)
+
+func TestInstanceActivationDevices(t *testing.T) {
+	devices := deviceConfig.Devices{
+		"root": {"type": "disk", "path": "/", "pool": "default"},
+		"web":  {"type": "proxy", "listen": "tcp:0.0.0.0:80", "connect": "tcp:127.0.0.1:80", "start_on_connect": "true"},
+		"ssh":  {"type": "proxy", "listen": "tcp:0.0.0.0:22", "connect": "tcp:127.0.0.1:22"},
+		"dns":  {"type": "proxy", "listen": "udp:0.0.0.0:53", "connect": "udp:127.0.0.1:53", "start_on_connect": "false"},
+	}
+
+	activation := instanceActivationDevices(devices)
+	assert.Len(t, activation, 1)
+	assert.Equal(t, devices["web"], activation["web"])
+}
+
+func TestInstanceIdleStopDue(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		lastActivity time.Time
+		active       bool
+		found        bool
+		expected     bool
+	}{
+		{"Idle for longer than the timeout", now.Add(-20 * time.Minute), false, true, true},
+		{"Idle for exactly the timeout", now.Add(-10 * time.Minute), false, true, true},
+		{"Idle for less than the timeout", now.Add(-5 * time.Minute), false, true, false},
+		{"Active connections", now.Add(-20 * time.Minute), true, true, false},
+		{"No proxy device to check", now.Add(-20 * time.Minute), false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, instanceIdleStopDue(tt.lastActivity, now, 10*time.Minute, tt.active, tt.found))
+		})
+	}
+}
+
+func TestInstanceActivationCleanup(t *testing.T) {
+	tests := []struct {
+		name       string
+		activation *instanceActivation
+		kept       bool
+	}{
+		{"Nothing left to track", &instanceActivation{}, false},
+		{"Listening", &instanceActivation{devices: map[string]deviceConfig.Device{"web": {}}}, true},
+		{"Starting", &instanceActivation{start: &instanceActivationStart{}}, true},
+		{"Relaying", &instanceActivation{relays: 1}, true},
+		{"Idle timer", &instanceActivation{idleCancel: func() {}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "test/" + tt.name
+
+			instanceActivationsMu.Lock()
+			instanceActivations[key] = tt.activation
+			instanceActivationCleanup(key, tt.activation)
+			_, ok := instanceActivations[key]
+			delete(instanceActivations, key)
+			instanceActivationsMu.Unlock()
+
+			assert.Equal(t, tt.kept, ok)
+		})
+	}
+}
+
+func TestInstanceActivationRelay(t *testing.T) {
+	client, conn := net.Pipe()
+	target, service := net.Pipe()
+
+	done := make(chan struct{})
+	go func() {
+		instanceActivationRelay("test/relay", conn, target)
+		close(done)
+	}()
+
+	// The relayed connection counts as instance activity.
+	require.Eventually(t, func() bool { return instanceActivationRelays("test", "relay") == 1 }, time.Second, 10*time.Millisecond)
+
+	go func() {
+		_, _ = client.Write([]byte("ping"))
+	}()
+
+	buf := make([]byte, 4)
+	_, err := io.ReadFull(service, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	go func() {
+		_, _ = service.Write([]byte("pong"))
+	}()
+
+	_, err = io.ReadFull(client, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(buf))
+
+	_ = client.Close()
+	_ = service.Close()
+	<-done
+
+	assert.Equal(t, 0, instanceActivationRelays("test", "relay"))
+}
@@ -315,10 +315,24 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Backup names may not contain slashes"))
 	}
 
+	if req.Stateful && !inst.IsRunning() {
+		return response.BadRequest(fmt.Errorf("Stateful backups require the instance to be running"))
+	}
+
 	fullName := name + internalInstance.SnapshotDelimiter + req.Name
 	instanceOnly := req.InstanceOnly
 
 	backup := func(op *operations.Operation) error {
+		// Checkpoint the instance so that its runtime state gets included in the backup.
+		if req.Stateful {
+			inst.SetOperation(op)
+
+			err := inst.Stop(true)
+			if err != nil {
+				return fmt.Errorf("Failed stateful stop of instance: %w", err)
+			}
+		}
+
 		args := db.InstanceBackup{
 			Name:                 fullName,
 			InstanceID:           inst.ID(),
@@ -331,6 +345,11 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 
 		err := backupCreate(s, args, inst, op)
 		if err != nil {
+			// Resume the instance from its checkpoint.
+			if req.Stateful {
+				_ = inst.Start(true)
+			}
+
 			return fmt.Errorf("Create backup: %w", err)
 		}
 
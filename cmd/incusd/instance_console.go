@@ -30,6 +30,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -68,6 +69,9 @@ type consoleWs struct {
 
 	// channel type (either console or vga)
 	protocol string
+
+	// daemon state
+	s *state.State
 }
 
 func (s *consoleWs) Metadata() any {
@@ -224,6 +228,19 @@ func (s *consoleWs) doConsole(op *operations.Operation) error {
 		_ = console.Close()
 	}()
 
+	// Record the session if requested.
+	recorder, err := sessionRecorderStart(s.s, s.instance, op, "console", nil, s.width, s.height)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err := recorder.Close()
+		if err != nil {
+			logger.Warn("Failed finishing session recording", logger.Ctx{"err": err})
+		}
+	}()
+
 	// Detect size of window and set it into console.
 	if s.width > 0 && s.height > 0 {
 		_ = linux.SetPtySize(int(console.Fd()), s.width, s.height)
@@ -285,6 +302,7 @@ func (s *consoleWs) doConsole(op *operations.Operation) error {
 				}
 
 				logger.Debugf("Set window size to: %dx%d", winchWidth, winchHeight)
+				recorder.Resize(winchWidth, winchHeight)
 			}
 		}
 	}()
@@ -300,7 +318,7 @@ func (s *consoleWs) doConsole(op *operations.Operation) error {
 		defer l.Debug("Finished mirroring websocket to console")
 
 		l.Debug("Started mirroring websocket")
-		readDone, writeDone := ws.Mirror(conn, console)
+		readDone, writeDone := ws.Mirror(conn, recorder.ReadWriteCloser(console))
 
 		<-readDone
 		l.Debug("Finished mirroring console to websocket")
@@ -571,6 +589,7 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 	ws.width = post.Width
 	ws.height = post.Height
 	ws.protocol = post.Type
+	ws.s = s
 
 	resources := map[string][]api.URL{}
 	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", ws.instance.Name())}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// instanceDependenciesDefaultTimeout is how long to wait for dependencies when boot.depends_on.timeout isn't set.
+const instanceDependenciesDefaultTimeout = 60 * time.Second
+
+// instanceDependencyLevels returns the dependency level of each instance, keyed by project and name.
+// Instances must be started in increasing level order, or stopped in increasing level order when reverse is set.
+func instanceDependencyLevels(instances []instance.Instance, reverse bool) map[string]int {
+	dependencies := map[string][]string{}
+
+	for _, inst := range instances {
+		key := inst.Project().Name + "/" + inst.Name()
+
+		_, ok := dependencies[key]
+		if !ok {
+			dependencies[key] = []string{}
+		}
+
+		for _, name := range internalInstance.ParseDependencies(inst.ExpandedConfig()["boot.depends_on"]) {
+			depKey := inst.Project().Name + "/" + name
+
+			if reverse {
+				// Dependencies must wait for the instances depending on them.
+				dependencies[depKey] = append(dependencies[depKey], key)
+			} else {
+				dependencies[key] = append(dependencies[key], depKey)
+			}
+		}
+	}
+
+	return internalInstance.DependencyLevels(dependencies)
+}
+
+// instanceDependencyRunning returns whether a dependency is running, possibly on another cluster member.
+func instanceDependencyRunning(s *state.State, inst instance.Instance) bool {
+	if !s.ServerClustered || inst.Location() == s.ServerName {
+		return inst.IsRunning()
+	}
+
+	return inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning
+}
+
+// instanceDependencySignalsReadiness returns whether a dependency is expected to report being ready, either
+// through a health check or because its guest reported being ready before.
+func instanceDependencySignalsReadiness(inst instance.Instance) bool {
+	return inst.ExpandedConfig()["healthcheck.type"] != "" || util.IsTrue(inst.LocalConfig()["volatile.ready_reported"])
+}
+
+// instanceDependenciesWait waits for the instances listed in boot.depends_on to be running and ready.
+// Dependencies which never signal their readiness are only waited for until running.
+// Once boot.depends_on.timeout is exceeded, an error is returned if a dependency still isn't running while
+// dependencies which are running but haven't reported being ready are ignored.
+func instanceDependenciesWait(s *state.State, inst instance.Instance) error {
+	dependencies := internalInstance.ParseDependencies(inst.ExpandedConfig()["boot.depends_on"])
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	timeout := instanceDependenciesDefaultTimeout
+	value := inst.ExpandedConfig()["boot.depends_on.timeout"]
+	if value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("Invalid boot.depends_on.timeout: %w", err)
+		}
+
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+	deadline := time.Now().Add(timeout)
+	logged := false
+
+	for {
+		notRunning := []string{}
+		notReady := []string{}
+
+		for _, name := range dependencies {
+			dependency, err := instance.LoadByProjectAndName(s, inst.Project().Name, name)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					return fmt.Errorf("Dependency %q doesn't exist", name)
+				}
+
+				return fmt.Errorf("Failed loading dependency %q: %w", name, err)
+			}
+
+			if !instanceDependencyRunning(s, dependency) {
+				notRunning = append(notRunning, name)
+			} else if instanceDependencySignalsReadiness(dependency) && !util.IsTrue(dependency.LocalConfig()["volatile.last_state.ready"]) {
+				notReady = append(notReady, name)
+			}
+		}
+
+		if len(notRunning) == 0 && len(notReady) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if len(notRunning) > 0 {
+				return fmt.Errorf("Dependencies aren't running: %s", strings.Join(notRunning, ", "))
+			}
+
+			l.Warn("Starting instance with dependencies which aren't ready", logger.Ctx{"dependencies": notReady})
+
+			return nil
+		}
+
+		if !logged {
+			l.Info("Waiting for instance dependencies", logger.Ctx{"notRunning": notRunning, "notReady": notReady})
+			logged = true
+		}
+
+		select {
+		case <-s.ShutdownCtx.Done():
+			return s.ShutdownCtx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
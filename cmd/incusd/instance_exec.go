@@ -261,6 +261,20 @@ func (s *execWs) Do(op *operations.Operation) error {
 	waitAttachedChildIsDead, markAttachedChildIsDead := context.WithCancel(context.Background())
 	var wgEOF sync.WaitGroup
 
+	// Record interactive sessions if requested.
+	var recorder *sessionRecorder
+	if s.req.Interactive {
+		recorder, err = sessionRecorderStart(s.s, s.instance, op, "exec", s.req.Command, s.req.Width, s.req.Height)
+		if err != nil {
+			for i := range ttys {
+				_ = ttys[i].Close()
+				_ = ptys[i].Close()
+			}
+
+			return err
+		}
+	}
+
 	// Define a function to clean up TTYs and sockets when done.
 	finisher := func(cmdResult int, cmdErr error) error {
 		// Cancel this before closing the control connection so control handler can detect command ending.
@@ -289,6 +303,11 @@ func (s *execWs) Do(op *operations.Operation) error {
 			_ = pty.Close()
 		}
 
+		err = recorder.Close()
+		if err != nil {
+			logger.Warn("Failed finishing session recording", logger.Ctx{"err": err})
+		}
+
 		// Make VM disconnections (shutdown/reboot) match containers.
 		if cmdErr == drivers.ErrExecDisconnected {
 			cmdResult = 129
@@ -401,6 +420,8 @@ func (s *execWs) Do(op *operations.Operation) error {
 					l.Debug("Failed to set window size", logger.Ctx{"err": err, "width": winchWidth, "height": winchHeight})
 					continue
 				}
+
+				recorder.Resize(winchWidth, winchHeight)
 			} else if command.Command == "signal" {
 				err := cmd.Signal(unix.Signal(command.Signal))
 				if err != nil {
@@ -431,9 +452,9 @@ func (s *execWs) Do(op *operations.Operation) error {
 			if s.instance.Type() == instancetype.Container {
 				// For containers, we are running the command via the locally managed PTY and so
 				// need to use the same PTY handle for both read and write.
-				readDone, writeDone = ws.Mirror(conn, linux.NewExecWrapper(waitAttachedChildIsDead, ptys[0]))
+				readDone, writeDone = ws.Mirror(conn, recorder.ReadWriteCloser(linux.NewExecWrapper(waitAttachedChildIsDead, ptys[0])))
 			} else {
-				readDone = ws.MirrorRead(conn, ptys[execWSStdout])
+				readDone = ws.MirrorRead(conn, recorder.Reader(ptys[execWSStdout]))
 				writeDone = ws.MirrorWrite(conn, ttys[execWSStdin])
 			}
 
@@ -0,0 +1,576 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Health statuses reported in the instance state.
+const (
+	instanceHealthStarting  = "starting"
+	instanceHealthHealthy   = "healthy"
+	instanceHealthUnhealthy = "unhealthy"
+)
+
+// instanceHealthCheck tracks the health check of a running instance.
+type instanceHealthCheck struct {
+	cancel context.CancelFunc
+	config map[string]string
+
+	mu     sync.Mutex
+	health api.InstanceStateHealth
+}
+
+// instanceHealthCheckClient sends the HTTP health checks.
+// Instances are reached directly rather than through the configured proxy and redirects count as a successful check.
+var instanceHealthCheckClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:             nil,
+		DisableKeepAlives: true,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// instanceHealthChecks keeps track of the instances whose health is currently being checked.
+var instanceHealthChecks = map[string]*instanceHealthCheck{}
+var instanceHealthChecksMu sync.Mutex
+
+// instanceHealthChecksRegister starts checking the health of the running instances which requested it.
+func instanceHealthChecksRegister(d *Daemon, instances []instance.Instance) {
+	for _, inst := range instances {
+		instanceHealthCheckSync(d, inst)
+	}
+}
+
+// instanceHealthChecksHandleEvent starts, restarts or stops health checks following the instance lifecycle events.
+func instanceHealthChecksHandleEvent(d *Daemon) func(event api.Event) {
+	return func(event api.Event) {
+		if event.Type != api.EventTypeLifecycle {
+			return
+		}
+
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return
+		}
+
+		if !slices.Contains([]string{api.EventLifecycleInstanceStarted, api.EventLifecycleInstanceRestarted, api.EventLifecycleInstanceUpdated, api.EventLifecycleInstanceStopped, api.EventLifecycleInstanceShutdown, api.EventLifecycleInstanceDeleted, api.EventLifecycleInstanceRenamed, api.EventLifecycleInstanceMigrated}, lifecycleEvent.Action) {
+			return
+		}
+
+		projectName := lifecycleEvent.Project
+		if projectName == "" {
+			projectName = api.ProjectDefaultName
+		}
+
+		if lifecycleEvent.Action == api.EventLifecycleInstanceRenamed {
+			oldName, ok := lifecycleEvent.Context["old_name"].(string)
+			if ok {
+				instanceHealthCheckStop(projectName, oldName)
+			}
+		}
+
+		if lifecycleEvent.Action == api.EventLifecycleInstanceDeleted {
+			instanceHealthCheckStop(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		inst, err := instance.LoadByProjectAndName(d.State(), projectName, lifecycleEvent.Name)
+		if err != nil {
+			instanceHealthCheckStop(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		// Only check the health of local instances.
+		if d.serverClustered && inst.Location() != d.serverName {
+			instanceHealthCheckStop(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		instanceHealthCheckSync(d, inst)
+	}
+}
+
+// instanceHealthCheckConfig returns the health check configuration of an instance.
+func instanceHealthCheckConfig(inst instance.Instance) map[string]string {
+	config := map[string]string{}
+	for k, v := range inst.ExpandedConfig() {
+		if strings.HasPrefix(k, "healthcheck.") {
+			config[k] = v
+		}
+	}
+
+	return config
+}
+
+// instanceHealthCheckSync starts, restarts or stops the health check of an instance based on its state and configuration.
+func instanceHealthCheckSync(d *Daemon, inst instance.Instance) {
+	if !inst.IsRunning() || inst.ExpandedConfig()["healthcheck.type"] == "" {
+		instanceHealthCheckStop(inst.Project().Name, inst.Name())
+		return
+	}
+
+	config := instanceHealthCheckConfig(inst)
+	key := inst.Project().Name + "/" + inst.Name()
+
+	instanceHealthChecksMu.Lock()
+	check, ok := instanceHealthChecks[key]
+	instanceHealthChecksMu.Unlock()
+
+	// Keep the current check (and its state) if its configuration didn't change.
+	if ok && maps.Equal(check.config, config) {
+		return
+	}
+
+	instanceHealthCheckStop(inst.Project().Name, inst.Name())
+	instanceHealthCheckStart(d, inst, config)
+}
+
+// instanceHealthCheckStart starts checking the health of an instance.
+func instanceHealthCheckStart(d *Daemon, inst instance.Instance, config map[string]string) {
+	key := inst.Project().Name + "/" + inst.Name()
+
+	instanceHealthChecksMu.Lock()
+	defer instanceHealthChecksMu.Unlock()
+
+	_, ok := instanceHealthChecks[key]
+	if ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(d.shutdownCtx)
+	check := &instanceHealthCheck{
+		cancel: cancel,
+		config: config,
+		health: api.InstanceStateHealth{Status: instanceHealthStarting},
+	}
+
+	instanceHealthChecks[key] = check
+
+	go instanceHealthCheckRun(ctx, d, inst, check)
+}
+
+// instanceHealthCheckStop stops checking the health of an instance.
+func instanceHealthCheckStop(projectName string, instanceName string) {
+	key := projectName + "/" + instanceName
+
+	instanceHealthChecksMu.Lock()
+	defer instanceHealthChecksMu.Unlock()
+
+	check, ok := instanceHealthChecks[key]
+	if !ok {
+		return
+	}
+
+	check.cancel()
+	delete(instanceHealthChecks, key)
+}
+
+// instanceHealthCheckState returns the current health of an instance, or nil if it isn't being checked.
+func instanceHealthCheckState(projectName string, instanceName string) *api.InstanceStateHealth {
+	instanceHealthChecksMu.Lock()
+	check, ok := instanceHealthChecks[projectName+"/"+instanceName]
+	instanceHealthChecksMu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	check.mu.Lock()
+	defer check.mu.Unlock()
+
+	health := check.health
+
+	return &health
+}
+
+// instanceHealthCheckInt returns the positive integer set in a health check key or its default.
+func instanceHealthCheckInt(config map[string]string, key string, defaultValue int) int {
+	value, err := strconv.Atoi(config[key])
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+
+	return value
+}
+
+// instanceHealthCheckRun periodically checks the health of an instance until the context is cancelled.
+func instanceHealthCheckRun(ctx context.Context, d *Daemon, inst instance.Instance, check *instanceHealthCheck) {
+	s := d.State()
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+	interval := time.Duration(instanceHealthCheckInt(check.config, "healthcheck.interval", 30)) * time.Second
+	timeout := time.Duration(instanceHealthCheckInt(check.config, "healthcheck.timeout", 5)) * time.Second
+	maxFailures := instanceHealthCheckInt(check.config, "healthcheck.failures", 3)
+	action := check.config["healthcheck.action"]
+
+	l.Debug("Starting instance health check", logger.Ctx{"type": check.config["healthcheck.type"], "interval": interval})
+	defer l.Debug("Stopped instance health check")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := instanceHealthCheckProbe(checkCtx, inst, check.config)
+		cancel()
+
+		// Don't report checks interrupted by the instance stopping or the configuration changing.
+		if ctx.Err() != nil {
+			return
+		}
+
+		check.mu.Lock()
+		previousStatus := check.health.Status
+		check.health.LastCheck = time.Now()
+
+		if err == nil {
+			check.health.Status = instanceHealthHealthy
+			check.health.Failures = 0
+			check.health.LastError = ""
+		} else {
+			check.health.Failures++
+			check.health.LastError = err.Error()
+
+			if check.health.Failures >= maxFailures {
+				check.health.Status = instanceHealthUnhealthy
+			}
+		}
+
+		health := check.health
+		check.mu.Unlock()
+
+		if err != nil {
+			l.Debug("Instance health check failed", logger.Ctx{"failures": health.Failures, "err": err})
+		}
+
+		if health.Status == previousStatus {
+			continue
+		}
+
+		// Reflect the health in the readiness of the instance, as used by boot.depends_on.
+		if health.Status != instanceHealthStarting {
+			err := inst.VolatileSet(map[string]string{"volatile.last_state.ready": strconv.FormatBool(health.Status == instanceHealthHealthy)})
+			if err != nil {
+				l.Warn("Failed updating instance readiness", logger.Ctx{"err": err})
+			}
+		}
+
+		if health.Status == instanceHealthHealthy {
+			if previousStatus == instanceHealthUnhealthy {
+				l.Info("Instance is healthy again")
+				s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceHealthy.Event(inst, nil))
+			}
+
+			continue
+		}
+
+		if health.Status != instanceHealthUnhealthy {
+			continue
+		}
+
+		l.Warn("Instance is unhealthy", logger.Ctx{"failures": health.Failures, "err": health.LastError, "action": action})
+		s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceUnhealthy.Event(inst, map[string]any{"failures": health.Failures, "error": health.LastError, "action": action}))
+
+		if action == "" || action == "none" {
+			continue
+		}
+
+		err = instanceHealthCheckRemediate(ctx, d, inst, action)
+		if err != nil {
+			l.Error("Failed handling unhealthy instance", logger.Ctx{"action": action, "err": err})
+		}
+
+		// Give the instance time to recover before acting again.
+		check.mu.Lock()
+		check.health.Status = instanceHealthStarting
+		check.health.Failures = 0
+		check.mu.Unlock()
+	}
+}
+
+// instanceHealthCheckProbe runs a single health check against the instance.
+func instanceHealthCheckProbe(ctx context.Context, inst instance.Instance, config map[string]string) error {
+	switch config["healthcheck.type"] {
+	case "exec":
+		return instanceHealthCheckExec(ctx, inst, config["healthcheck.command"])
+	case "tcp":
+		address, err := instanceHealthCheckAddress(inst, config["healthcheck.address"])
+		if err != nil {
+			return err
+		}
+
+		dialer := net.Dialer{}
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	case "http":
+		address, err := instanceHealthCheckAddress(inst, config["healthcheck.address"])
+		if err != nil {
+			return err
+		}
+
+		path := config["healthcheck.path"]
+		if path == "" {
+			path = "/"
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", "http://"+address+path, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := instanceHealthCheckClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("Unexpected HTTP status: %s", resp.Status)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("Unsupported health check type %q", config["healthcheck.type"])
+}
+
+// instanceHealthCheckAddress returns the address to connect to, filling in the instance address if no host was set.
+func instanceHealthCheckAddress(inst instance.Instance, address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("Invalid health check address %q: %w", address, err)
+	}
+
+	if host != "" {
+		ip := net.ParseIP(strings.Trim(host, "[]"))
+		if ip != nil && ip.IsUnspecified() {
+			return "", fmt.Errorf("Wildcard health check address %q isn't allowed", address)
+		}
+
+		return address, nil
+	}
+
+	hostInterfaces, _ := net.Interfaces()
+	state, err := inst.RenderState(hostInterfaces)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(state.Network))
+	for name := range state.Network {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	// Prefer IPv4 addresses, falling back to IPv6.
+	for _, family := range []string{"inet", "inet6"} {
+		for _, name := range names {
+			if name == "lo" {
+				continue
+			}
+
+			for _, addr := range state.Network[name].Addresses {
+				if addr.Family == family && addr.Scope == "global" {
+					return net.JoinHostPort(addr.Address, port), nil
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("No global address found for the instance")
+}
+
+// instanceHealthCheckExec runs the command inside the instance, failing if it doesn't exit with a zero status.
+func instanceHealthCheckExec(ctx context.Context, inst instance.Instance, command string) error {
+	args, err := shellquote.Split(command)
+	if err != nil {
+		return fmt.Errorf("Invalid health check command: %w", err)
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("No health check command set")
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = devNull.Close() }()
+
+	req := api.InstanceExecPost{
+		Command:     args,
+		Environment: map[string]string{"PATH": "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
+	}
+
+	cmd, err := inst.Exec(req, devNull, devNull, devNull)
+	if err != nil {
+		return err
+	}
+
+	// Kill the command if it takes too long.
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = cmd.Signal(unix.SIGKILL)
+		case <-done:
+		}
+	}()
+
+	exitCode, err := cmd.Wait()
+	if ctx.Err() != nil {
+		return fmt.Errorf("Health check command timed out")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("Health check command exited with status %d", exitCode)
+	}
+
+	return nil
+}
+
+// instanceHealthCheckRemediate applies the configured action to an unhealthy instance.
+func instanceHealthCheckRemediate(ctx context.Context, d *Daemon, inst instance.Instance, action string) error {
+	switch action {
+	case "restart":
+		return inst.Restart(instanceHealthCheckShutdownTimeout(inst))
+	case "stop":
+		return instanceHealthCheckShutdown(inst)
+	case "evacuate":
+		if !d.serverClustered {
+			return fmt.Errorf("Evacuating unhealthy instances requires clustering")
+		}
+
+		return instanceHealthCheckEvacuate(ctx, d, inst)
+	}
+
+	return fmt.Errorf("Unsupported health check action %q", action)
+}
+
+// instanceHealthCheckShutdownTimeout returns how long to wait for the instance to shut down cleanly.
+func instanceHealthCheckShutdownTimeout(inst instance.Instance) time.Duration {
+	val, err := strconv.Atoi(inst.ExpandedConfig()["boot.host_shutdown_timeout"])
+	if err != nil {
+		val = evacuateHostShutdownDefaultTimeout
+	}
+
+	return time.Duration(val) * time.Second
+}
+
+// instanceHealthCheckShutdown cleanly shuts down the instance, forcing it to stop if that fails.
+func instanceHealthCheckShutdown(inst instance.Instance) error {
+	err := inst.Shutdown(instanceHealthCheckShutdownTimeout(inst))
+	if err == nil || errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
+		return nil
+	}
+
+	err = inst.Stop(false)
+	if err != nil && !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
+		return err
+	}
+
+	return nil
+}
+
+// instanceHealthCheckEvacuate moves the instance to another cluster member and starts it there.
+func instanceHealthCheckEvacuate(ctx context.Context, d *Daemon, inst instance.Instance) error {
+	s := d.State()
+
+	// Don't tie the migration to the health check as stopping the instance cancels it.
+	ctx = context.WithoutCancel(ctx)
+
+	run := func(op *operations.Operation) error {
+		sourceMemberInfo, targetMemberInfo, err := evacuateClusterSelectTarget(ctx, s, inst)
+		if err != nil {
+			return err
+		}
+
+		err = instanceHealthCheckShutdown(inst)
+		if err != nil {
+			return err
+		}
+
+		err = migrateInstance(ctx, s, inst, api.InstancePost{Migration: true}, sourceMemberInfo, targetMemberInfo, "", op)
+		if err != nil {
+			return fmt.Errorf("Failed to migrate instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		// Start it back up on target.
+		dest, err := cluster.Connect(targetMemberInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err != nil {
+			return fmt.Errorf("Failed to connect to destination %q for instance %q in project %q: %w", targetMemberInfo.Address, inst.Name(), inst.Project().Name, err)
+		}
+
+		dest = dest.UseProject(inst.Project().Name)
+
+		startOp, err := dest.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "start"}, "")
+		if err != nil {
+			return err
+		}
+
+		return startOp.Wait()
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+
+	op, err := operations.OperationCreate(s, inst.Project().Name, operations.OperationClassTask, operationtype.InstanceMigrate, resources, nil, run, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	err = op.Start()
+	if err != nil {
+		return err
+	}
+
+	return op.Wait(ctx)
+}
@@ -0,0 +1,588 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/resources"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/task"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Resource usage history is kept for a day at one minute resolution.
+const (
+	instanceHistoryInterval = time.Minute
+	instanceHistoryLength   = 24 * time.Hour
+	instanceHistorySize     = int(instanceHistoryLength / instanceHistoryInterval)
+)
+
+// instanceHistoryDiskInterval is how often the disk usage is refreshed, as querying the storage driver isn't cheap.
+const instanceHistoryDiskInterval = 15 * time.Minute
+
+// instanceHistoryWorkers is how many instances are sampled concurrently.
+const instanceHistoryWorkers = 4
+
+// instanceHistoryRecordSize is the size of a sample in the history files.
+const instanceHistoryRecordSize = 6 * 8
+
+// instanceHistoryClockTicks is the number of clock ticks per second used by /proc/PID/stat.
+const instanceHistoryClockTicks = 100
+
+// instanceHistorySample is the compact form of api.InstanceStateSample.
+type instanceHistorySample struct {
+	timestamp   int64
+	cpu         int64
+	memory      int64
+	disk        int64
+	networkRecv int64
+	networkSent int64
+}
+
+// instanceHistory is a ring buffer of resource usage samples of an instance, backed by a file of the same layout.
+// Samples are stored in the slot matching their minute so that the file can be updated in place.
+type instanceHistory struct {
+	mu      sync.Mutex
+	path    string
+	samples []instanceHistorySample
+	latest  int64
+}
+
+// newInstanceHistory returns a history backed by the given file, loading any samples it already holds.
+// An empty path keeps the history in memory only.
+func newInstanceHistory(path string) (*instanceHistory, error) {
+	h := &instanceHistory{
+		path:    path,
+		samples: make([]instanceHistorySample, instanceHistorySize),
+	}
+
+	if path == "" {
+		return h, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return h, nil
+		}
+
+		return nil, err
+	}
+
+	for i := range min(len(data)/instanceHistoryRecordSize, instanceHistorySize) {
+		sample := instanceHistoryDecode(data[i*instanceHistoryRecordSize : (i+1)*instanceHistoryRecordSize])
+		if sample.timestamp == 0 || instanceHistorySlot(sample.timestamp) != i {
+			continue
+		}
+
+		h.samples[i] = sample
+		h.latest = max(h.latest, sample.timestamp)
+	}
+
+	return h, nil
+}
+
+// instanceHistorySlot returns the ring buffer slot of a sample taken at the given time.
+func instanceHistorySlot(timestamp int64) int {
+	return int((timestamp / int64(instanceHistoryInterval/time.Second)) % int64(instanceHistorySize))
+}
+
+// instanceHistoryEncode returns the file representation of a sample.
+func instanceHistoryEncode(sample instanceHistorySample) []byte {
+	data := make([]byte, 0, instanceHistoryRecordSize)
+	for _, value := range []int64{sample.timestamp, sample.cpu, sample.memory, sample.disk, sample.networkRecv, sample.networkSent} {
+		data = binary.LittleEndian.AppendUint64(data, uint64(value))
+	}
+
+	return data
+}
+
+// instanceHistoryDecode returns the sample stored in its file representation.
+func instanceHistoryDecode(data []byte) instanceHistorySample {
+	value := func(i int) int64 {
+		return int64(binary.LittleEndian.Uint64(data[i*8:]))
+	}
+
+	return instanceHistorySample{
+		timestamp:   value(0),
+		cpu:         value(1),
+		memory:      value(2),
+		disk:        value(3),
+		networkRecv: value(4),
+		networkSent: value(5),
+	}
+}
+
+// add records a new sample, overwriting the one taken a full history length earlier.
+func (h *instanceHistory) add(sample instanceHistorySample) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	slot := instanceHistorySlot(sample.timestamp)
+	h.samples[slot] = sample
+	h.latest = max(h.latest, sample.timestamp)
+
+	if h.path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.WriteAt(instanceHistoryEncode(sample), int64(slot*instanceHistoryRecordSize))
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// valid returns whether a sample belongs to the current history rather than to an older cycle of the buffer.
+// The caller must hold the lock.
+func (h *instanceHistory) valid(sample instanceHistorySample) bool {
+	return sample.timestamp != 0 && sample.timestamp > h.latest-int64(instanceHistoryLength/time.Second)
+}
+
+// last returns the most recent sample.
+func (h *instanceHistory) last() (instanceHistorySample, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.latest == 0 {
+		return instanceHistorySample{}, false
+	}
+
+	return h.samples[instanceHistorySlot(h.latest)], true
+}
+
+// latestTime returns the time of the most recent sample.
+func (h *instanceHistory) latestTime() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.latest == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(h.latest, 0)
+}
+
+// since returns the samples taken after the given time, oldest first.
+func (h *instanceHistory) since(t time.Time) []api.InstanceStateSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := []instanceHistorySample{}
+	for _, sample := range h.samples {
+		if !h.valid(sample) || sample.timestamp < t.Unix() {
+			continue
+		}
+
+		samples = append(samples, sample)
+	}
+
+	slices.SortFunc(samples, func(a instanceHistorySample, b instanceHistorySample) int {
+		return int(a.timestamp - b.timestamp)
+	})
+
+	result := make([]api.InstanceStateSample, 0, len(samples))
+	for _, sample := range samples {
+		result = append(result, api.InstanceStateSample{
+			Timestamp:            time.Unix(sample.timestamp, 0).UTC(),
+			CPUUsage:             sample.cpu,
+			MemoryUsage:          sample.memory,
+			DiskUsage:            sample.disk,
+			NetworkBytesReceived: sample.networkRecv,
+			NetworkBytesSent:     sample.networkSent,
+		})
+	}
+
+	return result
+}
+
+// instanceHistories keeps the resource usage history of the local instances.
+var instanceHistories = map[string]*instanceHistory{}
+var instanceHistoriesMu sync.Mutex
+
+// instanceHistoryPath returns the path of the file holding the resource usage history of an instance.
+func instanceHistoryPath(projectName string, instanceName string) string {
+	return internalUtil.VarPath("history", project.Instance(projectName, instanceName))
+}
+
+// instanceHistoryLoad returns the resource usage history of an instance, loading it from disk if needed.
+// Unless create is set, nil is returned for instances without any history.
+func instanceHistoryLoad(projectName string, instanceName string, create bool) (*instanceHistory, error) {
+	key := project.Instance(projectName, instanceName)
+
+	instanceHistoriesMu.Lock()
+	defer instanceHistoriesMu.Unlock()
+
+	history := instanceHistories[key]
+	if history != nil {
+		return history, nil
+	}
+
+	path := instanceHistoryPath(projectName, instanceName)
+	if !create {
+		_, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+	}
+
+	history, err := newInstanceHistory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceHistories[key] = history
+
+	return history, nil
+}
+
+// instanceHistoryGet returns the resource usage history of an instance since the given time.
+func instanceHistoryGet(projectName string, instanceName string, since time.Time) []api.InstanceStateSample {
+	history, err := instanceHistoryLoad(projectName, instanceName, false)
+	if err != nil {
+		logger.Warn("Failed loading instance resource usage history", logger.Ctx{"project": projectName, "instance": instanceName, "err": err})
+	}
+
+	if history == nil {
+		return []api.InstanceStateSample{}
+	}
+
+	return history.since(since)
+}
+
+// instanceHistoryLast returns the most recent resource usage sample of an instance.
+func instanceHistoryLast(projectName string, instanceName string) (instanceHistorySample, bool) {
+	history, err := instanceHistoryLoad(projectName, instanceName, false)
+	if err != nil || history == nil {
+		return instanceHistorySample{}, false
+	}
+
+	return history.last()
+}
+
+// instanceHistoryForget drops the resource usage history of an instance.
+func instanceHistoryForget(projectName string, instanceName string) {
+	instanceHistoriesMu.Lock()
+	defer instanceHistoriesMu.Unlock()
+
+	delete(instanceHistories, project.Instance(projectName, instanceName))
+
+	err := os.Remove(instanceHistoryPath(projectName, instanceName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Warn("Failed removing instance resource usage history", logger.Ctx{"project": projectName, "instance": instanceName, "err": err})
+	}
+}
+
+// instanceHistoryRename moves the resource usage history of an instance to its new name.
+func instanceHistoryRename(projectName string, oldName string, newName string) {
+	instanceHistoriesMu.Lock()
+	defer instanceHistoriesMu.Unlock()
+
+	delete(instanceHistories, project.Instance(projectName, oldName))
+	delete(instanceHistories, project.Instance(projectName, newName))
+
+	err := os.Rename(instanceHistoryPath(projectName, oldName), instanceHistoryPath(projectName, newName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Warn("Failed renaming instance resource usage history", logger.Ctx{"project": projectName, "instance": oldName, "err": err})
+	}
+}
+
+// instanceHistoryHandleEvent moves or removes the resource usage history of instances which get renamed or deleted.
+func instanceHistoryHandleEvent(event api.Event) {
+	if event.Type != api.EventTypeLifecycle {
+		return
+	}
+
+	lifecycleEvent := api.EventLifecycle{}
+
+	err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+	if err != nil {
+		return
+	}
+
+	if !slices.Contains([]string{api.EventLifecycleInstanceDeleted, api.EventLifecycleInstanceRenamed}, lifecycleEvent.Action) {
+		return
+	}
+
+	projectName := lifecycleEvent.Project
+	if projectName == "" {
+		projectName = api.ProjectDefaultName
+	}
+
+	if lifecycleEvent.Action == api.EventLifecycleInstanceRenamed {
+		oldName, ok := lifecycleEvent.Context["old_name"].(string)
+		if ok {
+			instanceHistoryRename(projectName, oldName, lifecycleEvent.Name)
+		}
+
+		return
+	}
+
+	instanceHistoryForget(projectName, lifecycleEvent.Name)
+}
+
+// instanceHistoryProcessUsage returns the CPU time, in nanoseconds, and resident memory, in bytes, of a process.
+func instanceHistoryProcessUsage(pid int) (int64, int64, error) {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Skip over the command name as it may contain spaces.
+	idx := strings.LastIndex(string(content), ")")
+	if idx < 0 {
+		return 0, 0, fmt.Errorf("Invalid stat file for PID %d", pid)
+	}
+
+	// The fields after the command name start with the state (field 3), utime and stime are fields 14 and 15.
+	fields := strings.Fields(string(content[idx+1:]))
+	if len(fields) < 13 {
+		return 0, 0, fmt.Errorf("Invalid stat file for PID %d", pid)
+	}
+
+	var ticks int64
+	for _, field := range fields[11:13] {
+		value, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		ticks += value
+	}
+
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	var rss int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "VmRSS:" {
+			continue
+		}
+
+		rss, err = strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		rss *= 1024
+		break
+	}
+
+	return ticks * int64(time.Second/instanceHistoryClockTicks), rss, nil
+}
+
+// instanceHistorySampleInstance takes a resource usage sample of a running instance from the host's point of view.
+// This avoids going through the VM agent and only refreshes the disk usage every instanceHistoryDiskInterval.
+func instanceHistorySampleInstance(s *state.State, inst instance.Instance, now time.Time, previous *instanceHistorySample) (instanceHistorySample, error) {
+	sample := instanceHistorySample{timestamp: now.Unix()}
+
+	if inst.Type() == instancetype.Container {
+		cg, err := inst.CGroup()
+		if err != nil {
+			return sample, err
+		}
+
+		sample.cpu, err = cg.GetCPUAcctUsage()
+		if err != nil {
+			return sample, err
+		}
+
+		sample.memory, err = cg.GetMemoryUsage()
+		if err != nil {
+			return sample, err
+		}
+	} else {
+		var err error
+
+		sample.cpu, sample.memory, err = instanceHistoryProcessUsage(inst.InitPID())
+		if err != nil {
+			return sample, err
+		}
+	}
+
+	// The host side of the NICs sees the instance's traffic the other way around.
+	for devName, dev := range inst.ExpandedDevices() {
+		if dev["type"] != "nic" {
+			continue
+		}
+
+		hostName := inst.LocalConfig()[fmt.Sprintf("volatile.%s.host_name", devName)]
+		if hostName == "" {
+			continue
+		}
+
+		counters, err := resources.GetNetworkCounters(hostName)
+		if err != nil {
+			continue
+		}
+
+		sample.networkRecv += counters.BytesSent
+		sample.networkSent += counters.BytesReceived
+	}
+
+	// Carry the disk usage over from the previous sample unless it's due for a refresh.
+	if previous != nil {
+		sample.disk = previous.disk
+
+		if now.Sub(time.Unix(previous.timestamp, 0)) < instanceHistoryDiskInterval && instanceHistorySlot(sample.timestamp)%int(instanceHistoryDiskInterval/instanceHistoryInterval) != 0 {
+			return sample, nil
+		}
+	}
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return sample, nil
+	}
+
+	usage, err := pool.GetInstanceUsage(inst)
+	if err == nil && usage != nil {
+		sample.disk = usage.Used
+	}
+
+	return sample, nil
+}
+
+// instanceHistoryCleanup drops the history of instances which are gone or haven't run for longer than it covers.
+func instanceHistoryCleanup(current map[string]bool, now time.Time) {
+	entries, err := os.ReadDir(internalUtil.VarPath("history"))
+	if err != nil {
+		logger.Warn("Failed listing instance resource usage history", logger.Ctx{"err": err})
+		return
+	}
+
+	instanceHistoriesMu.Lock()
+	defer instanceHistoriesMu.Unlock()
+
+	for _, entry := range entries {
+		key := entry.Name()
+		if current[key] {
+			info, err := entry.Info()
+			if err != nil || now.Sub(info.ModTime()) <= instanceHistoryLength {
+				continue
+			}
+		}
+
+		delete(instanceHistories, key)
+
+		err := os.Remove(internalUtil.VarPath("history", key))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("Failed removing instance resource usage history", logger.Ctx{"file": key, "err": err})
+		}
+	}
+
+	for key, history := range instanceHistories {
+		if !current[key] || now.Sub(history.latestTime()) > instanceHistoryLength {
+			delete(instanceHistories, key)
+		}
+	}
+}
+
+// instanceHistoryTask records the resource usage of the running local instances.
+func instanceHistoryTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Warn("Failed loading instances for resource usage history", logger.Ctx{"err": err})
+			return
+		}
+
+		now := time.Now()
+		current := map[string]bool{}
+
+		// Sample the instances concurrently, but without hogging the system with large numbers of instances.
+		wg := sync.WaitGroup{}
+		workers := make(chan struct{}, instanceHistoryWorkers)
+
+		for _, inst := range instances {
+			if inst.IsSnapshot() {
+				continue
+			}
+
+			current[project.Instance(inst.Project().Name, inst.Name())] = true
+
+			if !inst.IsRunning() {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			case workers <- struct{}{}:
+			}
+
+			wg.Add(1)
+			go func(inst instance.Instance) {
+				defer wg.Done()
+				defer func() { <-workers }()
+
+				l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+				history, err := instanceHistoryLoad(inst.Project().Name, inst.Name(), true)
+				if err != nil {
+					l.Warn("Failed loading instance resource usage history", logger.Ctx{"err": err})
+					return
+				}
+
+				var previous *instanceHistorySample
+				last, ok := history.last()
+				if ok {
+					previous = &last
+				}
+
+				sample, err := instanceHistorySampleInstance(s, inst, now, previous)
+				if err != nil {
+					l.Debug("Failed sampling instance resource usage", logger.Ctx{"err": err})
+					return
+				}
+
+				err = history.add(sample)
+				if err != nil {
+					l.Warn("Failed recording instance resource usage history", logger.Ctx{"err": err})
+				}
+			}(inst)
+		}
+
+		wg.Wait()
+
+		instanceHistoryCleanup(current, now)
+	}
+
+	return f, task.Every(instanceHistoryInterval)
+}
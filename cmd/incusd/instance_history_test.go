@@ -0,0 +1,71 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceHistory(t *testing.T) {
+	h, err := newInstanceHistory("")
+	require.NoError(t, err)
+	assert.True(t, h.latestTime().IsZero())
+
+	start := time.Unix(1700000000, 0)
+
+	// Overfill the buffer so that it wraps around.
+	for i := range instanceHistorySize + 10 {
+		err := h.add(instanceHistorySample{timestamp: start.Add(time.Duration(i) * time.Minute).Unix(), cpu: int64(i)})
+		require.NoError(t, err)
+	}
+
+	last := start.Add(time.Duration(instanceHistorySize+9) * time.Minute)
+	assert.Equal(t, last, h.latestTime())
+
+	samples := h.since(time.Time{})
+	assert.Len(t, samples, instanceHistorySize)
+	assert.Equal(t, int64(10), samples[0].CPUUsage)
+	assert.Equal(t, int64(instanceHistorySize+9), samples[len(samples)-1].CPUUsage)
+
+	samples = h.since(last.Add(-2 * time.Minute))
+	assert.Len(t, samples, 3)
+	assert.Equal(t, last.UTC(), samples[2].Timestamp)
+}
+
+func TestInstanceHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c1")
+
+	h, err := newInstanceHistory(path)
+	require.NoError(t, err)
+
+	start := time.Unix(1700000000, 0)
+	for i := range 5 {
+		err := h.add(instanceHistorySample{timestamp: start.Add(time.Duration(i) * time.Minute).Unix(), cpu: int64(i), disk: 42})
+		require.NoError(t, err)
+	}
+
+	// A restart picks the samples back up.
+	h, err = newInstanceHistory(path)
+	require.NoError(t, err)
+
+	samples := h.since(time.Time{})
+	assert.Len(t, samples, 5)
+	assert.Equal(t, int64(4), samples[4].CPUUsage)
+	assert.Equal(t, int64(42), samples[4].DiskUsage)
+
+	last, ok := h.last()
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(4*time.Minute).Unix(), last.timestamp)
+
+	// Samples from an earlier cycle of the buffer are ignored after a gap.
+	later := start.Add(instanceHistoryLength + 10*time.Minute)
+	err = h.add(instanceHistorySample{timestamp: later.Unix(), cpu: 100})
+	require.NoError(t, err)
+
+	samples = h.since(time.Time{})
+	assert.Len(t, samples, 1)
+	assert.Equal(t, int64(100), samples[0].CPUUsage)
+}
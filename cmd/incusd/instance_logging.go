@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	liblxc "github.com/lxc/go-lxc"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// instanceLogsShippers keeps track of the instances whose logs are currently being shipped.
+var instanceLogsShippers = map[string]context.CancelFunc{}
+var instanceLogsShippersMu sync.Mutex
+
+// instanceLogsPollInterval is how often the instance log files are checked for new lines.
+const instanceLogsPollInterval = time.Second
+
+// instanceLogsRegister starts shipping the logs of the running instances which requested it.
+func instanceLogsRegister(d *Daemon, instances []instance.Instance) {
+	for _, inst := range instances {
+		instanceLogsSync(d, inst)
+	}
+}
+
+// instanceLogsHandleEvent starts or stops shipping the logs of an instance following its lifecycle events.
+func instanceLogsHandleEvent(d *Daemon) func(event api.Event) {
+	return func(event api.Event) {
+		if event.Type != api.EventTypeLifecycle {
+			return
+		}
+
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return
+		}
+
+		if !slices.Contains([]string{api.EventLifecycleInstanceStarted, api.EventLifecycleInstanceRestarted, api.EventLifecycleInstanceUpdated, api.EventLifecycleInstanceStopped, api.EventLifecycleInstanceShutdown, api.EventLifecycleInstanceDeleted, api.EventLifecycleInstanceRenamed}, lifecycleEvent.Action) {
+			return
+		}
+
+		projectName := lifecycleEvent.Project
+		if projectName == "" {
+			projectName = api.ProjectDefaultName
+		}
+
+		// Stop any existing shipper, renamed instances get a new one under their new name.
+		if lifecycleEvent.Action == api.EventLifecycleInstanceRenamed {
+			oldName, ok := lifecycleEvent.Context["old_name"].(string)
+			if ok {
+				instanceLogsStop(projectName, oldName)
+			}
+		}
+
+		if lifecycleEvent.Action == api.EventLifecycleInstanceDeleted {
+			instanceLogsStop(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		inst, err := instance.LoadByProjectAndName(d.State(), projectName, lifecycleEvent.Name)
+		if err != nil {
+			instanceLogsStop(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		// Only ship the logs of local instances.
+		if d.serverClustered && inst.Location() != d.serverName {
+			instanceLogsStop(projectName, lifecycleEvent.Name)
+			return
+		}
+
+		instanceLogsSync(d, inst)
+	}
+}
+
+// instanceLogsSync starts or stops shipping the logs of an instance based on its state and configuration.
+func instanceLogsSync(d *Daemon, inst instance.Instance) {
+	if d.getLokiClient() == nil || !inst.IsRunning() || inst.ExpandedConfig()["logging.target"] != "loki" {
+		instanceLogsStop(inst.Project().Name, inst.Name())
+		return
+	}
+
+	instanceLogsStart(d, inst)
+}
+
+// instanceLogsStart starts shipping the logs of an instance if not already done.
+func instanceLogsStart(d *Daemon, inst instance.Instance) {
+	key := inst.Project().Name + "/" + inst.Name()
+
+	instanceLogsShippersMu.Lock()
+	defer instanceLogsShippersMu.Unlock()
+
+	_, ok := instanceLogsShippers[key]
+	if ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(d.shutdownCtx)
+	instanceLogsShippers[key] = cancel
+
+	location := d.serverName
+	projectName := inst.Project().Name
+	name := inst.Name()
+
+	handle := func(source string, line string) {
+		// Look the client up on every line as it's replaced when the configuration changes.
+		client := d.getLokiClient()
+		if client == nil {
+			return
+		}
+
+		client.HandleInstanceLog(location, projectName, name, source, time.Now(), line)
+	}
+
+	send := func(source string) func(line string) {
+		return instanceLogsSender(handle, source)
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": name})
+	l.Debug("Starting to ship instance logs")
+
+	if inst.Type() == instancetype.Container {
+		go instanceLogsFollowContainerConsole(ctx, l, inst.(instance.Container), send("console"))
+		go instanceLogsTailFile(ctx, l, inst.LogFilePath(), nil, send("lxc"))
+	} else if inst.Type() == instancetype.VM {
+		// The console ring buffer is only written to the log file when drained, so do it on every poll.
+		v := inst.(instance.VM)
+		flush := func() {
+			err := v.FlushConsoleLog()
+			if err != nil {
+				l.Debug("Failed flushing the console log", logger.Ctx{"err": err})
+			}
+		}
+
+		go instanceLogsTailFile(ctx, l, inst.ConsoleBufferLogPath(), flush, send("console"))
+		go instanceLogsTailFile(ctx, l, inst.LogFilePath(), nil, send("qemu"))
+		go instanceLogsFollowJournal(ctx, l, inst, send("journal"))
+	}
+}
+
+// instanceLogsSender returns a function sending the lines of a log source to the handler, labelled with the source.
+func instanceLogsSender(handle func(source string, line string), source string) func(line string) {
+	return func(line string) {
+		handle(source, line)
+	}
+}
+
+// instanceLogsSplitter splits log output into lines, keeping incomplete lines around until they're terminated.
+type instanceLogsSplitter struct {
+	partial string
+	send    func(line string)
+}
+
+// write sends the complete non-empty lines of the output.
+func (s *instanceLogsSplitter) write(data string) {
+	data = s.partial + data
+
+	for {
+		end := strings.IndexByte(data, '\n')
+		if end < 0 {
+			break
+		}
+
+		line := strings.TrimRight(data[:end], "\r")
+		data = data[end+1:]
+
+		if line != "" {
+			s.send(line)
+		}
+	}
+
+	s.partial = data
+}
+
+// reset drops any incomplete line.
+func (s *instanceLogsSplitter) reset() {
+	s.partial = ""
+}
+
+// instanceLogsStop stops shipping the logs of an instance.
+func instanceLogsStop(projectName string, instanceName string) {
+	key := projectName + "/" + instanceName
+
+	instanceLogsShippersMu.Lock()
+	defer instanceLogsShippersMu.Unlock()
+
+	cancel, ok := instanceLogsShippers[key]
+	if !ok {
+		return
+	}
+
+	cancel()
+	delete(instanceLogsShippers, key)
+}
+
+// instanceLogsTailFile sends the lines appended to a log file until the context is cancelled.
+// Only new lines are sent and the file being truncated or replaced is handled.
+// The optional refresh function is called before every check of the file.
+func instanceLogsTailFile(ctx context.Context, l logger.Logger, path string, refresh func(), send func(line string)) {
+	var file *os.File
+	var inode uint64
+	var offset int64
+	splitter := &instanceLogsSplitter{send: send}
+
+	defer func() {
+		if file != nil {
+			_ = file.Close()
+		}
+	}()
+
+	open := func(fromStart bool) {
+		if file != nil {
+			_ = file.Close()
+			file = nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return
+		}
+
+		var stat unix.Stat_t
+		err = unix.Fstat(int(f.Fd()), &stat)
+		if err != nil {
+			_ = f.Close()
+			return
+		}
+
+		offset = 0
+		if !fromStart {
+			offset, err = f.Seek(0, io.SeekEnd)
+			if err != nil {
+				_ = f.Close()
+				return
+			}
+		}
+
+		file = f
+		inode = stat.Ino
+		splitter.reset()
+	}
+
+	// Skip whatever was logged before we started.
+	open(false)
+
+	ticker := time.NewTicker(instanceLogsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if refresh != nil {
+			refresh()
+		}
+
+		// Handle the file being created, replaced or truncated.
+		var stat unix.Stat_t
+		err := unix.Stat(path, &stat)
+		if err != nil {
+			continue
+		}
+
+		if file == nil || stat.Ino != inode || stat.Size < offset {
+			open(true)
+			if file == nil {
+				continue
+			}
+		}
+
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := file.Read(buf)
+			offset += int64(n)
+			splitter.write(string(buf[:n]))
+
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					l.Warn("Failed reading instance log", logger.Ctx{"path": filepath.Base(path), "err": err})
+				}
+
+				break
+			}
+		}
+	}
+}
+
+// instanceLogsFollowContainerConsole sends the output added to the console ring buffer of a container until
+// the context is cancelled. The ring buffer is read without being cleared so the console log stays available.
+func instanceLogsFollowContainerConsole(ctx context.Context, l logger.Logger, c instance.Container, send func(line string)) {
+	read := func() (string, error) {
+		log, err := c.ConsoleLog(liblxc.ConsoleLogOptions{ReadLog: true})
+		if err != nil {
+			// The ring buffer is empty.
+			errno, isErrno := linux.GetErrno(err)
+			if isErrno && errno == unix.ENODATA {
+				return "", nil
+			}
+
+			return "", err
+		}
+
+		return log, nil
+	}
+
+	// Skip whatever was logged before we started.
+	previous, _ := read()
+	splitter := &instanceLogsSplitter{send: send}
+
+	ticker := time.NewTicker(instanceLogsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := read()
+		if err != nil {
+			l.Debug("Failed reading the console ring buffer", logger.Ctx{"err": err})
+			continue
+		}
+
+		splitter.write(instanceLogsConsoleDelta(previous, current))
+		previous = current
+	}
+}
+
+// instanceLogsConsoleDelta returns the output added to a console ring buffer between two reads of it.
+// Once the buffer is full, the oldest output gets dropped so the current read starts with the end of the
+// previous one. Everything is returned if it doesn't, like after the buffer was cleared.
+func instanceLogsConsoleDelta(previous string, current string) string {
+	if current == "" {
+		return ""
+	}
+
+	for dropped := 0; dropped < len(previous); dropped++ {
+		// Only consider the positions starting with the first character of the current read.
+		next := strings.IndexByte(previous[dropped:], current[0])
+		if next < 0 {
+			break
+		}
+
+		dropped += next
+
+		rest := previous[dropped:]
+		if strings.HasPrefix(current, rest) {
+			return current[len(rest):]
+		}
+	}
+
+	return current
+}
+
+// instanceLogsFollowJournal sends the guest journal, as read through the agent, until the context is cancelled.
+func instanceLogsFollowJournal(ctx context.Context, l logger.Logger, inst instance.Instance, send func(line string)) {
+	for {
+		err := instanceLogsReadJournal(ctx, inst, send)
+		if err != nil {
+			l.Debug("Failed reading the guest journal", logger.Ctx{"err": err})
+		}
+
+		// Retry as the agent may not be running yet or may have been restarted.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// instanceLogsReadJournal runs journalctl in the guest and sends its output until it exits or the context is cancelled.
+func instanceLogsReadJournal(ctx context.Context, inst instance.Instance, send func(line string)) error {
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stdin.Close() }()
+
+	stderr, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stderr.Close() }()
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stdoutReader.Close() }()
+
+	req := api.InstanceExecPost{
+		Command:     []string{"journalctl", "--follow", "--lines=0", "--output=short-iso"},
+		Environment: map[string]string{"PATH": "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
+	}
+
+	cmd, err := inst.Exec(req, stdin, stdoutWriter, stderr)
+	_ = stdoutWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	// Stop journalctl when we're done.
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = cmd.Signal(unix.SIGTERM)
+		case <-done:
+		}
+	}()
+
+	scanner := bufio.NewScanner(stdoutReader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			send(line)
+		}
+	}
+
+	_, err = cmd.Wait()
+
+	return err
+}
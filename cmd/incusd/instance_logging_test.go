@@ -0,0 +1,146 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceLogsSplitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []string
+	}{
+		{
+			name:   "Complete lines",
+			writes: []string{"first\nsecond\n"},
+			want:   []string{"first", "second"},
+		},
+		{
+			name:   "Line split across writes",
+			writes: []string{"fir", "st\nsec", "ond\n"},
+			want:   []string{"first", "second"},
+		},
+		{
+			name:   "Incomplete line is kept",
+			writes: []string{"first\nsecond"},
+			want:   []string{"first"},
+		},
+		{
+			name:   "Carriage returns and empty lines",
+			writes: []string{"first\r\n\r\n\nsecond\r\n"},
+			want:   []string{"first", "second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := []string{}
+			splitter := &instanceLogsSplitter{send: func(line string) { lines = append(lines, line) }}
+
+			for _, data := range tt.writes {
+				splitter.write(data)
+			}
+
+			assert.Equal(t, tt.want, lines)
+		})
+	}
+}
+
+func TestInstanceLogsSplitterReset(t *testing.T) {
+	lines := []string{}
+	splitter := &instanceLogsSplitter{send: func(line string) { lines = append(lines, line) }}
+
+	splitter.write("truncated")
+	splitter.reset()
+	splitter.write("new\n")
+
+	assert.Equal(t, []string{"new"}, lines)
+}
+
+func TestInstanceLogsSender(t *testing.T) {
+	type entry struct {
+		source string
+		line   string
+	}
+
+	entries := []entry{}
+	handle := func(source string, line string) {
+		entries = append(entries, entry{source: source, line: line})
+	}
+
+	console := &instanceLogsSplitter{send: instanceLogsSender(handle, "console")}
+	qemu := &instanceLogsSplitter{send: instanceLogsSender(handle, "qemu")}
+
+	console.write("login: ")
+	qemu.write("warning\n")
+	console.write("\nbooted\n")
+
+	assert.Equal(t, []entry{
+		{source: "qemu", line: "warning"},
+		{source: "console", line: "login: "},
+		{source: "console", line: "booted"},
+	}, entries)
+}
+
+func TestInstanceLogsConsoleDelta(t *testing.T) {
+	long := strings.Repeat("x", 1024)
+
+	tests := []struct {
+		name     string
+		previous string
+		current  string
+		want     string
+	}{
+		{
+			name:     "First read",
+			previous: "",
+			current:  "boot\n",
+			want:     "boot\n",
+		},
+		{
+			name:     "Appended output",
+			previous: "boot\n",
+			current:  "boot\nlogin\n",
+			want:     "login\n",
+		},
+		{
+			name:     "No new output",
+			previous: "boot\n",
+			current:  "boot\n",
+			want:     "",
+		},
+		{
+			name:     "Oldest output dropped",
+			previous: "boot\nlogin\n",
+			current:  "ot\nlogin\nshell\n",
+			want:     "shell\n",
+		},
+		{
+			name:     "Oldest output dropped with long previous read",
+			previous: "boot\n" + long + "end\n",
+			current:  long[10:] + "end\nshell\n",
+			want:     "shell\n",
+		},
+		{
+			name:     "Repeated output",
+			previous: "tick\ntick\n",
+			current:  "tick\ntick\ntick\n",
+			want:     "tick\n",
+		},
+		{
+			name:     "Buffer cleared",
+			previous: "boot\nlogin\n",
+			current:  "shell\n",
+			want:     "shell\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, instanceLogsConsoleDelta(tt.previous, tt.current))
+		})
+	}
+}
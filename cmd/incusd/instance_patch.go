@@ -22,6 +22,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/response"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/osarch"
 )
 
@@ -183,6 +184,17 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionUpdate,
+		Type:    apiScriptlet.AdmissionTypeInstance,
+		Project: projectName,
+		Name:    name,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Check project limits.
 	apiProfiles := make([]api.Profile, 0, len(req.Profiles))
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// instancePlacementApplyRules applies the instance's `placement.affinity` and `placement.anti_affinity` rules
+// to the list of candidate cluster members.
+//
+// Candidates violating a hard rule are removed from the list. The remaining candidates are ordered so that
+// those satisfying the soft rules come first, otherwise preserving the order they were provided in.
+func instancePlacementApplyRules(ctx context.Context, tx *db.ClusterTx, projectName string, instName string, config map[string]string, candidates []db.NodeInfo) ([]db.NodeInfo, error) {
+	affinity, antiAffinity, err := internalInstance.GetPlacementRules(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing to do if no rules are configured.
+	if affinity == nil && antiAffinity == nil {
+		return candidates, nil
+	}
+
+	// Count matching instances on each cluster member.
+	affinityPeers := 0
+	affinityCount := map[string]int{}
+	antiAffinityCount := map[string]int{}
+
+	err = tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+		if dbInst.Name == instName {
+			return nil
+		}
+
+		expandedConfig := db.ExpandInstanceConfig(dbInst.Config, dbInst.Profiles)
+
+		if affinity != nil && affinity.Matches(expandedConfig) {
+			affinityPeers++
+			affinityCount[dbInst.Node]++
+		}
+
+		if antiAffinity != nil && antiAffinity.Matches(expandedConfig) {
+			antiAffinityCount[dbInst.Node]++
+		}
+
+		return nil
+	}, dbCluster.InstanceFilter{Project: &projectName})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instances for placement rules: %w", err)
+	}
+
+	// Filter out members violating hard rules.
+	// An affinity rule can only be satisfied if matching instances exist somewhere in the cluster.
+	filtered := make([]db.NodeInfo, 0, len(candidates))
+	for _, candidate := range candidates {
+		if antiAffinity != nil && antiAffinity.Hard && antiAffinityCount[candidate.Name] > 0 {
+			continue
+		}
+
+		if affinity != nil && affinity.Hard && affinityPeers > 0 && affinityCount[candidate.Name] == 0 {
+			continue
+		}
+
+		filtered = append(filtered, candidate)
+	}
+
+	if len(candidates) > 0 && len(filtered) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "No cluster member satisfies the placement rules of instance %q", instName)
+	}
+
+	// Prefer members with the fewest anti-affinity matches and then the most affinity matches.
+	sort.SliceStable(filtered, func(i, j int) bool {
+		nameI := filtered[i].Name
+		nameJ := filtered[j].Name
+
+		if antiAffinityCount[nameI] != antiAffinityCount[nameJ] {
+			return antiAffinityCount[nameI] < antiAffinityCount[nameJ]
+		}
+
+		return affinityCount[nameI] > affinityCount[nameJ]
+	})
+
+	return filtered, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -316,6 +317,12 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				if err != nil {
 					return err
 				}
+
+				// Apply the instance affinity and anti-affinity rules.
+				targetCandidates, err = instancePlacementApplyRules(ctx, tx, instProject, name, inst.ExpandedConfig(), targetCandidates)
+				if err != nil {
+					return err
+				}
 			}
 
 			return nil
@@ -1018,6 +1025,12 @@ func migrateInstance(ctx context.Context, s *state.State, inst instance.Instance
 				return fmt.Errorf("Failed deleting instance on source member: %w", err)
 			}
 		}
+
+		// Session recordings are local to the cluster member and don't follow the instance.
+		err = os.RemoveAll(sessionRecordingsPath(inst.Project().Name, inst.Name()))
+		if err != nil {
+			return fmt.Errorf("Failed removing session recordings on source member: %w", err)
+		}
 	}
 
 	return nil
@@ -24,6 +24,7 @@ import (
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/osarch"
 	"github.com/lxc/incus/v6/shared/revert"
 )
@@ -129,6 +130,17 @@ func instancePut(d *Daemon, r *http.Request) response.Response {
 	var do func(*operations.Operation) error
 	var opType operationtype.Type
 	if configRaw.Restore == "" {
+		err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+			Action:  apiScriptlet.AdmissionActionUpdate,
+			Type:    apiScriptlet.AdmissionTypeInstance,
+			Project: projectName,
+			Name:    name,
+			Object:  &configRaw,
+		}, &configRaw.Config)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
 		// Check project limits.
 		apiProfiles := make([]api.Profile, 0, len(configRaw.Profiles))
 		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -187,6 +199,44 @@ func instancePut(d *Daemon, r *http.Request) response.Response {
 
 		opType = operationtype.InstanceUpdate
 	} else {
+		// The configuration is restored from the snapshot as-is so the scriptlet can only accept or reject it.
+		snapName := configRaw.Restore
+		if !internalInstance.IsSnapshot(snapName) {
+			snapName = name + internalInstance.SnapshotDelimiter + snapName
+		}
+
+		snap, err := instance.LoadByProjectAndName(s, projectName, snapName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		snapArchitecture, _ := osarch.ArchitectureName(snap.Architecture())
+
+		snapProfiles := make([]string, 0, len(snap.Profiles()))
+		for _, profile := range snap.Profiles() {
+			snapProfiles = append(snapProfiles, profile.Name)
+		}
+
+		snapPut := api.InstancePut{
+			Architecture: snapArchitecture,
+			Config:       snap.LocalConfig(),
+			Description:  snap.Description(),
+			Devices:      snap.LocalDevices().CloneNative(),
+			Ephemeral:    snap.IsEphemeral(),
+			Profiles:     snapProfiles,
+		}
+
+		err = admissionCheckFixed(s, r, &apiScriptlet.AdmissionRequest{
+			Action:  apiScriptlet.AdmissionActionUpdate,
+			Type:    apiScriptlet.AdmissionTypeInstance,
+			Project: projectName,
+			Name:    name,
+			Object:  &snapPut,
+		}, snapPut.Config)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
 		// Snapshot Restore
 		do = func(op *operations.Operation) error {
 			defer unlock()
@@ -0,0 +1,679 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var instanceSessionsCmd = APIEndpoint{
+	Name: "instanceSessions",
+	Path: "instances/{name}/sessions",
+
+	Get: APIEndpointAction{Handler: instanceSessionsGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanExec, "name")},
+}
+
+var instanceSessionCmd = APIEndpoint{
+	Name: "instanceSession",
+	Path: "instances/{name}/sessions/{session}",
+
+	Get: APIEndpointAction{Handler: instanceSessionGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanExec, "name")},
+}
+
+// sessionRecordingHeader is the asciinema v2 header line of a session recording.
+type sessionRecordingHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Command   string `json:"command,omitempty"`
+	Title     string `json:"title,omitempty"`
+
+	// Incus specific metadata, ignored by asciinema players.
+	Session sessionRecordingMetadata `json:"x-incus"`
+}
+
+// sessionRecordingMetadata records who started the session and how.
+type sessionRecordingMetadata struct {
+	ID       string   `json:"id"`
+	Type     string   `json:"type"`
+	Command  []string `json:"command,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Protocol string   `json:"protocol,omitempty"`
+	Address  string   `json:"address,omitempty"`
+}
+
+// sessionRecorder writes an interactive session to disk in the asciinema v2 format.
+// Only the output stream and terminal resizes are recorded, keystrokes (including passwords typed at
+// prompts that don't echo) are not.
+type sessionRecorder struct {
+	s         *state.State
+	inst      instance.Instance
+	id        string
+	kind      string
+	requestor *api.EventLifecycleRequestor
+
+	file    *os.File
+	start   time.Time
+	pending []byte
+	mu      sync.Mutex
+}
+
+// sessionRecordingsPath returns the path to the session recordings of an instance.
+func sessionRecordingsPath(projectName string, instanceName string) string {
+	return internalUtil.VarPath("recordings", project.Instance(projectName, instanceName))
+}
+
+// sessionRecordingPath returns the path to a session recording of an instance.
+func sessionRecordingPath(projectName string, instanceName string, id string) string {
+	return filepath.Join(sessionRecordingsPath(projectName, instanceName), id+".cast")
+}
+
+// sessionRecorderStart starts recording a session if the instance has session recording enabled.
+// Returns a nil recorder when recording is disabled, all sessionRecorder functions accept a nil receiver.
+func sessionRecorderStart(s *state.State, inst instance.Instance, op *operations.Operation, kind string, command []string, width int, height int) (*sessionRecorder, error) {
+	if util.IsFalseOrEmpty(inst.ExpandedConfig()["security.session_recording"]) {
+		return nil, nil
+	}
+
+	id := uuid.New().String()
+	if op != nil {
+		id = op.ID()
+	}
+
+	metadata := sessionRecordingMetadata{
+		ID:      id,
+		Type:    kind,
+		Command: command,
+	}
+
+	var requestor *api.EventLifecycleRequestor
+	if op != nil {
+		requestor = op.Requestor()
+	}
+
+	if requestor != nil {
+		metadata.Username = requestor.Username
+		metadata.Protocol = requestor.Protocol
+		metadata.Address = requestor.Address
+	}
+
+	if width <= 0 || height <= 0 {
+		width = 80
+		height = 24
+	}
+
+	start := time.Now()
+	header := sessionRecordingHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: start.Unix(),
+		Command:   strings.Join(command, " "),
+		Title:     fmt.Sprintf("%s %s", inst.Name(), kind),
+		Session:   metadata,
+	}
+
+	path := sessionRecordingPath(inst.Project().Name, inst.Name(), id)
+
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating session recording directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating session recording: %w", err)
+	}
+
+	err = json.NewEncoder(f).Encode(header)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("Failed writing session recording header: %w", err)
+	}
+
+	rec := &sessionRecorder{
+		s:         s,
+		inst:      inst,
+		id:        id,
+		kind:      kind,
+		requestor: requestor,
+		file:      f,
+		start:     start,
+	}
+
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceSessionStarted.Event(id, inst, requestor, map[string]any{"id": id, "type": kind}))
+
+	return rec, nil
+}
+
+// event appends an event line to the recording.
+func (r *sessionRecorder) event(code string, data string) {
+	line, err := json.Marshal([]any{time.Since(r.start).Seconds(), code, data})
+	if err != nil {
+		return
+	}
+
+	_, err = r.file.Write(append(line, '\n'))
+	if err != nil {
+		logger.Warn("Failed writing session recording", logger.Ctx{"project": r.inst.Project().Name, "instance": r.inst.Name(), "session": r.id, "err": err})
+	}
+}
+
+// output records data written to the terminal.
+func (r *sessionRecorder) output(data []byte) {
+	if r == nil || len(data) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+
+	// Hold back incomplete UTF-8 sequences until the rest of the character comes in.
+	var complete []byte
+	complete, r.pending = sessionSplitUTF8(append(r.pending, data...))
+	if len(complete) > 0 {
+		r.event("o", string(complete))
+	}
+}
+
+// Resize records a change of the terminal size.
+func (r *sessionRecorder) Resize(width int, height int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Close finishes the recording.
+func (r *sessionRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	if len(r.pending) > 0 {
+		r.event("o", string(r.pending))
+		r.pending = nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	r.s.Events.SendLifecycle(r.inst.Project().Name, lifecycle.InstanceSessionFinished.Event(r.id, r.inst, r.requestor, map[string]any{"id": r.id, "type": r.kind}))
+
+	return err
+}
+
+// Reader returns a reader recording everything read from rd as terminal output.
+func (r *sessionRecorder) Reader(rd io.Reader) io.Reader {
+	if r == nil {
+		return rd
+	}
+
+	return &sessionRecorderReader{Reader: rd, rec: r}
+}
+
+// ReadWriteCloser returns a ReadWriteCloser recording everything read from rwc as terminal output.
+func (r *sessionRecorder) ReadWriteCloser(rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	if r == nil {
+		return rwc
+	}
+
+	return &sessionRecorderReadWriteCloser{ReadWriteCloser: rwc, rec: r}
+}
+
+type sessionRecorderReader struct {
+	io.Reader
+	rec *sessionRecorder
+}
+
+func (r *sessionRecorderReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.rec.output(p[:n])
+
+	return n, err
+}
+
+type sessionRecorderReadWriteCloser struct {
+	io.ReadWriteCloser
+	rec *sessionRecorder
+}
+
+func (r *sessionRecorderReadWriteCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadWriteCloser.Read(p)
+	r.rec.output(p[:n])
+
+	return n, err
+}
+
+// sessionSplitUTF8 splits buf into a valid UTF-8 prefix and a trailing incomplete character (if any).
+func sessionSplitUTF8(buf []byte) ([]byte, []byte) {
+	for i := len(buf) - 1; i >= 0 && i >= len(buf)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(buf[i]) {
+			continue
+		}
+
+		if !utf8.FullRune(buf[i:]) {
+			return buf[:i], append([]byte(nil), buf[i:]...)
+		}
+
+		break
+	}
+
+	return buf, nil
+}
+
+// sessionRecordingLoad reads the details of a session recording.
+func sessionRecordingLoad(path string) (*api.InstanceSession, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	header := sessionRecordingHeader{}
+	err = json.Unmarshal(line, &header)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing session recording header: %w", err)
+	}
+
+	return &api.InstanceSession{
+		ID:        header.Session.ID,
+		Type:      header.Session.Type,
+		Command:   header.Session.Command,
+		Username:  header.Session.Username,
+		Protocol:  header.Session.Protocol,
+		Address:   header.Session.Address,
+		StartedAt: time.Unix(header.Timestamp, 0).UTC(),
+		UpdatedAt: fi.ModTime().UTC(),
+		Size:      fi.Size(),
+	}, nil
+}
+
+// sessionRecordingsCanReadAll checks whether the requestor can read the session recordings of other identities.
+// This requires being able to edit the instance or to view sensitive server data.
+func sessionRecordingsCanReadAll(s *state.State, r *http.Request, projectName string, instanceName string) (bool, error) {
+	err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectInstance(projectName, instanceName), auth.EntitlementCanEdit)
+	if err == nil {
+		return true, nil
+	} else if !api.StatusErrorCheck(err, http.StatusForbidden) {
+		return false, err
+	}
+
+	err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectServer(), auth.EntitlementCanViewSensitive)
+	if err == nil {
+		return true, nil
+	} else if !api.StatusErrorCheck(err, http.StatusForbidden) {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// sessionRecordingVisible checks whether a session recording can be read by the requestor.
+// Those who can't read all the recordings only get to see the sessions they started themselves.
+func sessionRecordingVisible(session *api.InstanceSession, requestor *api.EventLifecycleRequestor, readAll bool) bool {
+	if readAll {
+		return true
+	}
+
+	if requestor == nil || session.Username == "" {
+		return false
+	}
+
+	return session.Username == requestor.Username && session.Protocol == requestor.Protocol
+}
+
+// validSessionID checks that the session identifier can't be used to escape the recordings directory.
+func validSessionID(id string) bool {
+	return uuid.Validate(id) == nil
+}
+
+// swagger:operation GET /1.0/instances/{name}/sessions instances instance_sessions_get
+//
+//	Get the recorded sessions
+//
+//	Returns a list of recorded sessions (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/instances/foo/sessions/1c0d7a0e-43f2-4b6e-a6ea-5b4a6d3b9b8c"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/instances/{name}/sessions?recursion=1 instances instance_sessions_get_recursion1
+//
+//	Get the recorded sessions
+//
+//	Returns a list of recorded sessions (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of recorded sessions
+//	          items:
+//	            $ref: "#/definitions/InstanceSession"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSessionsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(fmt.Errorf("Invalid instance name"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	// Ensure instance exists.
+	_, err = instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	readAll, err := sessionRecordingsCanReadAll(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	recursion := localUtil.IsRecursionRequest(r)
+
+	urls := []string{}
+	sessions := []api.InstanceSession{}
+
+	dents, err := os.ReadDir(sessionRecordingsPath(projectName, name))
+	if err != nil && !os.IsNotExist(err) {
+		return response.SmartError(err)
+	}
+
+	for _, f := range dents {
+		id, found := strings.CutSuffix(f.Name(), ".cast")
+		if !found || !validSessionID(id) {
+			continue
+		}
+
+		if !recursion && readAll {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "instances", name, "sessions", id).String())
+			continue
+		}
+
+		session, err := sessionRecordingLoad(sessionRecordingPath(projectName, name, id))
+		if err != nil {
+			logger.Warn("Failed loading session recording", logger.Ctx{"project": projectName, "instance": name, "session": id, "err": err})
+			continue
+		}
+
+		if !sessionRecordingVisible(session, requestor, readAll) {
+			continue
+		}
+
+		if !recursion {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "instances", name, "sessions", id).String())
+			continue
+		}
+
+		sessions = append(sessions, *session)
+	}
+
+	if !recursion {
+		return response.SyncResponse(true, urls)
+	}
+
+	return response.SyncResponse(true, sessions)
+}
+
+// swagger:operation GET /1.0/instances/{name}/sessions/{session} instances instance_session_get
+//
+//	Get the session recording
+//
+//	Downloads the asciinema recording of the session.
+//
+//	---
+//	produces:
+//	  - application/json
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	     description: Raw file
+//	     content:
+//	       application/octet-stream:
+//	         schema:
+//	           type: string
+//	           example: some-text
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSessionGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(fmt.Errorf("Invalid instance name"))
+	}
+
+	id, err := url.PathUnescape(mux.Vars(r)["session"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !validSessionID(id) {
+		return response.BadRequest(fmt.Errorf("Invalid session %q", id))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	// Ensure instance exists.
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	path := sessionRecordingPath(projectName, name, id)
+	if !util.PathExists(path) {
+		return response.NotFound(fmt.Errorf("Session %q not found", id))
+	}
+
+	readAll, err := sessionRecordingsCanReadAll(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	// Don't reveal the sessions of other identities.
+	if !readAll {
+		session, err := sessionRecordingLoad(path)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if !sessionRecordingVisible(session, requestor, readAll) {
+			return response.NotFound(fmt.Errorf("Session %q not found", id))
+		}
+	}
+
+	ent := response.FileResponseEntry{
+		Path:     path,
+		Filename: id + ".cast",
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceSessionRetrieved.Event(id, inst, requestor, map[string]any{"id": id}))
+
+	return response.FileResponse(r, []response.FileResponseEntry{ent}, nil)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestSessionSplitUTF8(t *testing.T) {
+	complete, rest := sessionSplitUTF8([]byte("hello"))
+	assert.Equal(t, []byte("hello"), complete)
+	assert.Nil(t, rest)
+
+	// "é" is 0xc3 0xa9, split after the first byte.
+	complete, rest = sessionSplitUTF8([]byte{'a', 0xc3})
+	assert.Equal(t, []byte("a"), complete)
+	assert.Equal(t, []byte{0xc3}, rest)
+
+	complete, rest = sessionSplitUTF8(append(rest, 0xa9))
+	assert.Equal(t, []byte("é"), complete)
+	assert.Nil(t, rest)
+
+	// "€" is 0xe2 0x82 0xac, split after the second byte.
+	complete, rest = sessionSplitUTF8([]byte{0xe2, 0x82})
+	assert.Empty(t, complete)
+	assert.Equal(t, []byte{0xe2, 0x82}, rest)
+}
+
+func TestSessionRecordingVisible(t *testing.T) {
+	session := &api.InstanceSession{Username: "alice", Protocol: "oidc"}
+
+	tests := []struct {
+		name      string
+		session   *api.InstanceSession
+		requestor *api.EventLifecycleRequestor
+		readAll   bool
+		want      bool
+	}{
+		{
+			name:      "Own session",
+			session:   session,
+			requestor: &api.EventLifecycleRequestor{Username: "alice", Protocol: "oidc"},
+			want:      true,
+		},
+		{
+			name:      "Other identity",
+			session:   session,
+			requestor: &api.EventLifecycleRequestor{Username: "bob", Protocol: "oidc"},
+			want:      false,
+		},
+		{
+			name:      "Same name with another protocol",
+			session:   session,
+			requestor: &api.EventLifecycleRequestor{Username: "alice", Protocol: "tls"},
+			want:      false,
+		},
+		{
+			name:      "Session without identity",
+			session:   &api.InstanceSession{},
+			requestor: &api.EventLifecycleRequestor{},
+			want:      false,
+		},
+		{
+			name:      "Other identity with access to all sessions",
+			session:   session,
+			requestor: &api.EventLifecycleRequestor{Username: "bob", Protocol: "oidc"},
+			readAll:   true,
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sessionRecordingVisible(tt.session, tt.requestor, tt.readAll))
+		})
+	}
+}
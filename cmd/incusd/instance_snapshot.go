@@ -267,7 +267,7 @@ func instanceSnapshotsPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
-		err = project.AllowSnapshotCreation(p)
+		err = project.AllowSnapshotCreation(ctx, tx, p)
 		if err != nil {
 			return err
 		}
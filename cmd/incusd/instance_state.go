@@ -16,6 +16,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -38,6 +39,11 @@ import (
 //	    name: project
 //	    description: Project name
 //	    type: string
+//	  - in: query
+//	    name: history
+//	    description: Include the resource usage history over this duration (up to 24h)
+//	    type: string
+//	    example: 1h
 //	responses:
 //	  "200":
 //	    description: State
@@ -93,6 +99,16 @@ func instanceState(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	var history time.Duration
+	if r.FormValue("history") != "" {
+		history, err = time.ParseDuration(r.FormValue("history"))
+		if err != nil || history <= 0 {
+			return response.BadRequest(fmt.Errorf("Invalid history duration %q", r.FormValue("history")))
+		}
+
+		history = min(history, instanceHistoryLength)
+	}
+
 	c, err := instance.LoadByProjectAndName(s, projectName, name)
 	if err != nil {
 		return response.SmartError(err)
@@ -104,6 +120,12 @@ func instanceState(d *Daemon, r *http.Request) response.Response {
 		return response.InternalError(err)
 	}
 
+	state.Health = instanceHealthCheckState(c.Project().Name, c.Name())
+
+	if history > 0 {
+		state.History = instanceHistoryGet(c.Project().Name, c.Name(), time.Now().Add(-history))
+	}
+
 	return response.SyncResponse(true, state)
 }
 
@@ -198,7 +220,7 @@ func instanceStatePut(d *Daemon, r *http.Request) response.Response {
 	do := func(op *operations.Operation) error {
 		inst.SetOperation(op)
 
-		return doInstanceStatePut(inst, req)
+		return doInstanceStatePut(s, inst, req)
 	}
 
 	resources := map[string][]api.URL{}
@@ -228,7 +250,7 @@ func instanceActionToOptype(action string) (operationtype.Type, error) {
 	return operationtype.Unknown, fmt.Errorf("Unknown action: '%s'", action)
 }
 
-func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error {
+func doInstanceStatePut(s *state.State, inst instance.Instance, req api.InstanceStatePut) error {
 	if req.Force {
 		// A zero timeout indicates to do a forced stop/restart.
 		req.Timeout = 0
@@ -242,6 +264,11 @@ func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error
 
 	switch internalInstance.InstanceAction(req.Action) {
 	case internalInstance.Start:
+		err := instanceDependenciesWait(s, inst)
+		if err != nil {
+			return err
+		}
+
 		return inst.Start(req.Stateful)
 	case internalInstance.Stop:
 		if req.Stateful {
@@ -220,6 +220,12 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 	// Sort based on instance boot priority.
 	sort.Sort(instanceAutostartList(instances))
 
+	// Start the dependencies (boot.depends_on) before the instances depending on them.
+	levels := instanceDependencyLevels(instances, false)
+	sort.SliceStable(instances, func(i, j int) bool {
+		return levels[instances[i].Project().Name+"/"+instances[i].Name()] < levels[instances[j].Project().Name+"/"+instances[j].Name()]
+	})
+
 	// Let's make up to 3 attempts to start instances.
 	maxAttempts := 3
 
@@ -246,13 +252,16 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 		for {
 			attempt++
 
-			var err error
-			if shutdownAction == "stateful-stop" {
-				// Attempt to restore state.
-				err = inst.Start(true)
-			} else {
-				// Normal startup.
-				err = inst.Start(false)
+			// Wait for the instances it depends on.
+			err := instanceDependenciesWait(s, inst)
+			if err == nil {
+				if shutdownAction == "stateful-stop" {
+					// Attempt to restore state.
+					err = inst.Start(true)
+				} else {
+					// Normal startup.
+					err = inst.Start(false)
+				}
 			}
 
 			if err != nil {
@@ -394,6 +403,12 @@ func instancesOnDisk(s *state.State) ([]instance.Instance, error) {
 func instancesShutdown(s *state.State, instances []instance.Instance) {
 	sort.Sort(instanceStopList(instances))
 
+	// Stop the instances depending on others (boot.depends_on) before their dependencies.
+	levels := instanceDependencyLevels(instances, true)
+	sort.SliceStable(instances, func(i, j int) bool {
+		return levels[instances[i].Project().Name+"/"+instances[i].Name()] < levels[instances[j].Project().Name+"/"+instances[j].Name()]
+	})
+
 	// Limit shutdown concurrency to number of instances or number of CPU cores (which ever is less).
 	var wg sync.WaitGroup
 	instShutdownCh := make(chan instance.Instance)
@@ -448,6 +463,7 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 	}
 
 	var currentBatchPriority int
+	var currentBatchLevel int
 	for i, inst := range instances {
 		// Skip stopped instances.
 		if !inst.IsRunning() {
@@ -455,10 +471,12 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 		}
 
 		priority, _ := strconv.Atoi(inst.ExpandedConfig()["boot.stop.priority"])
+		level := levels[inst.Project().Name+"/"+inst.Name()]
 
-		// Shutdown instances in priority batches, logging at the start of each batch.
-		if i == 0 || priority != currentBatchPriority {
+		// Shutdown instances in dependency level and priority batches, logging at the start of each batch.
+		if i == 0 || priority != currentBatchPriority || level != currentBatchLevel {
 			currentBatchPriority = priority
+			currentBatchLevel = level
 
 			// Wait for instances with higher priority to finish before starting next batch.
 			wg.Wait()
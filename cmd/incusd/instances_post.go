@@ -732,6 +732,19 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		bInfo.Name = instanceName
 	}
 
+	// The configuration is restored from the backup itself so the scriptlet can only accept or reject it.
+	req.Name = bInfo.Name
+	err = admissionCheckFixed(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionCreate,
+		Type:    apiScriptlet.AdmissionTypeInstance,
+		Project: projectName,
+		Name:    bInfo.Name,
+		Object:  &req,
+	}, req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	logger.Debug("Backup file info loaded", logger.Ctx{
 		"type":      bInfo.Type,
 		"name":      bInfo.Name,
@@ -832,7 +845,22 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 
 		runRevert.Success()
 
-		return instanceCreateFinish(s, &req, db.InstanceArgs{Name: bInfo.Name, Project: bInfo.Project}, op)
+		err = instanceCreateFinish(s, &req, db.InstanceArgs{Name: bInfo.Name, Project: bInfo.Project}, op)
+		if err != nil {
+			return err
+		}
+
+		// Resume instances which were exported along with their runtime state.
+		if inst.IsStateful() {
+			inst.SetOperation(op)
+
+			err = inst.Start(true)
+			if err != nil {
+				return fmt.Errorf("Failed restoring instance runtime state: %w", err)
+			}
+		}
+
+		return nil
 	}
 
 	resources := map[string][]api.URL{}
@@ -947,6 +975,18 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Run the admission scriptlet before the configuration gets checked against the project limits.
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionCreate,
+		Type:    apiScriptlet.AdmissionTypeInstance,
+		Project: targetProjectName,
+		Name:    req.Name,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	var targetProject *api.Project
 	var profiles []api.Profile
 	var sourceInst *dbCluster.Instance
@@ -1145,6 +1185,12 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			if err != nil {
 				return err
 			}
+
+			// Apply the instance affinity and anti-affinity rules.
+			candidateMembers, err = instancePlacementApplyRules(ctx, tx, targetProjectName, req.Name, db.ExpandInstanceConfig(req.Config, profiles), candidateMembers)
+			if err != nil {
+				return err
+			}
 		}
 
 		if !clusterNotification {
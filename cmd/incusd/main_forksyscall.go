@@ -13,6 +13,7 @@ package main
 #include <string.h>
 #include <sys/capability.h>
 #include <sys/fsuid.h>
+#include <sys/ioctl.h>
 #include <sys/prctl.h>
 #include <sys/stat.h>
 #include <sys/types.h>
@@ -22,6 +23,7 @@ package main
 #include <unistd.h>
 
 #include "../../shared/cgo/macro.h"
+#include "../../shared/cgo/incus_seccomp.h"
 #include "../../shared/cgo/memory_utils.h"
 #include "../../shared/cgo/mount_utils.h"
 #include "../../shared/cgo/syscall_numbers.h"
@@ -461,6 +463,171 @@ static void mount_emulate(void)
 	}
 }
 
+static int seccomp_install_fd(int notify_fd, __u64 id, int fd, bool cloexec)
+{
+	struct seccomp_notif_addfd addfd = {
+		.id		= id,
+		.srcfd		= fd,
+		.newfd_flags	= cloexec ? O_CLOEXEC : 0,
+	};
+
+	return ioctl(notify_fd, SECCOMP_IOCTL_NOTIF_ADDFD, &addfd);
+}
+
+// Expects command line to be in the form:
+// <PID> <PidFd> <notify-fd> <notify-id> <fstype> <flags> <uid> <gid> <fsuid> <fsgid>
+static void fsopen_emulate(void)
+{
+	__do_close int pidfd = -EBADF, ns_fd = -EBADF, notify_fd = -EBADF, fs_fd = -EBADF;
+	char *fstype = NULL;
+	unsigned int flags;
+	int ret;
+	__u64 id;
+	pid_t pid;
+	uid_t fsuid, uid;
+	gid_t fsgid, gid;
+
+	pid = atoi(advance_arg(true));
+	pidfd = atoi(advance_arg(true));
+	ns_fd = pidfd_nsfd(pidfd, pid);
+	if (ns_fd < 0)
+		_exit(EXIT_FAILURE);
+	notify_fd = atoi(advance_arg(true));
+	id = strtoull(advance_arg(true), NULL, 10);
+	fstype = advance_arg(true);
+	flags = atoi(advance_arg(true));
+	uid = atoi(advance_arg(true));
+	gid = atoi(advance_arg(true));
+	fsuid = atoi(advance_arg(true));
+	fsgid = atoi(advance_arg(true));
+
+	if (!acquire_basic_creds(pid, pidfd, ns_fd, NULL, NULL)) {
+		fprintf(stderr, "%d", ENOANO);
+		_exit(EXIT_FAILURE);
+	}
+
+	if (!acquire_final_creds(pid, uid, gid, fsuid, fsgid))
+		_exit(EXIT_FAILURE);
+
+	fs_fd = incus_fsopen(fstype, FSOPEN_CLOEXEC);
+	if (fs_fd < 0) {
+		fprintf(stderr, "%d", errno);
+		_exit(EXIT_FAILURE);
+	}
+
+	ret = seccomp_install_fd(notify_fd, id, fs_fd, flags & FSOPEN_CLOEXEC);
+	if (ret < 0) {
+		fprintf(stderr, "%d", errno);
+		_exit(EXIT_FAILURE);
+	}
+
+	printf("%d", ret);
+	fflush(stdout);
+}
+
+// Expects command line to be in the form:
+// <PID> <PidFd> <fs-fd> <uid> <gid> <fsuid> <fsgid>
+static void fsconfig_emulate(void)
+{
+	__do_close int pidfd = -EBADF, ns_fd = -EBADF, fs_fd = -EBADF;
+	pid_t pid;
+	uid_t fsuid, uid;
+	gid_t fsgid, gid;
+
+	pid = atoi(advance_arg(true));
+	pidfd = atoi(advance_arg(true));
+	ns_fd = pidfd_nsfd(pidfd, pid);
+	if (ns_fd < 0)
+		_exit(EXIT_FAILURE);
+	fs_fd = atoi(advance_arg(true));
+	uid = atoi(advance_arg(true));
+	gid = atoi(advance_arg(true));
+	fsuid = atoi(advance_arg(true));
+	fsgid = atoi(advance_arg(true));
+
+	// The source is looked up when creating the superblock so do it from within the instance.
+	if (!acquire_basic_creds(pid, pidfd, ns_fd, NULL, NULL)) {
+		fprintf(stderr, "%d", ENOANO);
+		_exit(EXIT_FAILURE);
+	}
+
+	if (!acquire_final_creds(pid, uid, gid, fsuid, fsgid))
+		_exit(EXIT_FAILURE);
+
+	if (incus_fsconfig(fs_fd, FSCONFIG_CMD_CREATE, NULL, NULL, 0) < 0) {
+		fprintf(stderr, "%d", errno);
+		_exit(EXIT_FAILURE);
+	}
+}
+
+// Expects command line to be in the form:
+// <PID> <PidFd> <notify-fd> <notify-id> <fs-fd> <flags> <attr-flags> <uid> <gid> <fsuid> <fsgid>
+static void fsmount_emulate(void)
+{
+	__do_close int pidfd = -EBADF, ns_fd = -EBADF, notify_fd = -EBADF, fs_fd = -EBADF,
+		       fd_userns = -EBADF, fd_tree = -EBADF;
+	unsigned int flags, attr_flags;
+	int ret;
+	__u64 id;
+	pid_t pid;
+	uid_t fsuid, uid;
+	gid_t fsgid, gid;
+	struct lxc_mount_attr attr = {
+		.attr_set	= MOUNT_ATTR_IDMAP,
+	};
+
+	pid = atoi(advance_arg(true));
+	pidfd = atoi(advance_arg(true));
+	ns_fd = pidfd_nsfd(pidfd, pid);
+	if (ns_fd < 0)
+		_exit(EXIT_FAILURE);
+	notify_fd = atoi(advance_arg(true));
+	id = strtoull(advance_arg(true), NULL, 10);
+	fs_fd = atoi(advance_arg(true));
+	flags = atoi(advance_arg(true));
+	attr_flags = atoi(advance_arg(true));
+	uid = atoi(advance_arg(true));
+	gid = atoi(advance_arg(true));
+	fsuid = atoi(advance_arg(true));
+	fsgid = atoi(advance_arg(true));
+
+	fd_userns = preserve_ns(-ESRCH, ns_fd, "user");
+	if (fd_userns < 0) {
+		fprintf(stderr, "%d", ENOANO);
+		_exit(EXIT_FAILURE);
+	}
+
+	if (!acquire_basic_creds(pid, pidfd, ns_fd, NULL, NULL)) {
+		fprintf(stderr, "%d", ENOANO);
+		_exit(EXIT_FAILURE);
+	}
+
+	if (!acquire_final_creds(pid, uid, gid, fsuid, fsgid))
+		_exit(EXIT_FAILURE);
+
+	fd_tree = incus_fsmount(fs_fd, FSMOUNT_CLOEXEC, attr_flags);
+	if (fd_tree < 0) {
+		fprintf(stderr, "%d", errno);
+		_exit(EXIT_FAILURE);
+	}
+
+	attr.userns_fd = fd_userns;
+	ret = incus_mount_setattr(fd_tree, "", AT_EMPTY_PATH, &attr, sizeof(attr));
+	if (ret < 0) {
+		fprintf(stderr, "%d", errno);
+		_exit(EXIT_FAILURE);
+	}
+
+	ret = seccomp_install_fd(notify_fd, id, fd_tree, flags & FSMOUNT_CLOEXEC);
+	if (ret < 0) {
+		fprintf(stderr, "%d", errno);
+		_exit(EXIT_FAILURE);
+	}
+
+	printf("%d", ret);
+	fflush(stdout);
+}
+
 static bool incus_cap_is_set(cap_t caps, cap_value_t cap, cap_flag_t flag)
 {
 	int ret;
@@ -542,6 +709,12 @@ void forksyscall(void)
 		setxattr_emulate();
 	else if (strcmp(syscall, "mount") == 0)
 		mount_emulate();
+	else if (strcmp(syscall, "fsopen") == 0)
+		fsopen_emulate();
+	else if (strcmp(syscall, "fsconfig") == 0)
+		fsconfig_emulate();
+	else if (strcmp(syscall, "fsmount") == 0)
+		fsmount_emulate();
 	else
 		_exit(EXIT_FAILURE);
 
@@ -314,6 +314,16 @@ func networkForwardsPost(d *Daemon, r *http.Request) response.Response {
 
 	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
 
+	// Check the project limits, unless this is a notification from another cluster member.
+	if clientType != clusterRequest.ClientTypeNotifier {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return project.AllowNetworkForwardCreation(ctx, tx, reqProject)
+		})
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	err = n.ForwardCreate(req, clientType)
 	if err != nil {
 		return response.SmartError(fmt.Errorf("Failed creating forward: %w", err))
@@ -37,6 +37,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/warnings"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/util"
@@ -387,6 +388,17 @@ func networksPost(d *Daemon, r *http.Request) response.Response {
 		req.Config = map[string]string{}
 	}
 
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionCreate,
+		Type:    apiScriptlet.AdmissionTypeNetwork,
+		Project: projectName,
+		Name:    req.Name,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	netType, err := network.LoadByType(req.Type)
 	if err != nil {
 		return response.BadRequest(err)
@@ -1381,6 +1393,17 @@ func networkPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionUpdate,
+		Type:    apiScriptlet.AdmissionTypeNetwork,
+		Project: projectName,
+		Name:    networkName,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// In clustered mode, we differentiate between node specific and non-node specific config keys based on
 	// whether the user has specified a target to apply the config to.
 	if s.ServerClustered {
@@ -31,6 +31,7 @@ import (
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/util"
 )
@@ -353,6 +354,17 @@ func profilesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Invalid profile name %q", req.Name))
 	}
 
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionCreate,
+		Type:    apiScriptlet.AdmissionTypeProfile,
+		Project: p.Name,
+		Name:    req.Name,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	err = instance.ValidConfig(d.os, req.Config, false, instancetype.Any)
 	if err != nil {
 		return response.BadRequest(err)
@@ -603,6 +615,17 @@ func profilePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionUpdate,
+		Type:    apiScriptlet.AdmissionTypeProfile,
+		Project: p.Name,
+		Name:    name,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	err = doProfileUpdate(r.Context(), s, *p, name, id, profile, req)
 
 	if err == nil && !isClusterNotification(r) {
@@ -752,6 +775,17 @@ func profilePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionUpdate,
+		Type:    apiScriptlet.AdmissionTypeProfile,
+		Project: p.Name,
+		Name:    name,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(p.Name, lifecycle.ProfileUpdated.Event(name, p.Name, requestor, nil))
 
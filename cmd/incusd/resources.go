@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -10,7 +11,9 @@ import (
 	"github.com/lxc/incus/v6/internal/server/resources"
 	"github.com/lxc/incus/v6/internal/server/response"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
 )
 
 var api10ResourcesCmd = APIEndpoint{
@@ -152,3 +155,15 @@ func storagePoolResourcesGet(d *Daemon, r *http.Request) response.Response {
 
 	return response.SyncResponse(true, res)
 }
+
+// networkLoadTask samples the network traffic so the throughput is readily available in the system load.
+func networkLoadTask() (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := resources.SampleNetworkLoad()
+		if err != nil {
+			logger.Warn("Failed sampling network load", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(resources.NetworkLoadSampleInterval)
+}
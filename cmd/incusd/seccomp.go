@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// seccompHandleEvent releases the resources held by the seccomp handler on behalf of instances which stop,
+// get renamed or get deleted.
+func seccompHandleEvent(d *Daemon) func(event api.Event) {
+	return func(event api.Event) {
+		if event.Type != api.EventTypeLifecycle || d.seccomp == nil {
+			return
+		}
+
+		lifecycleEvent := api.EventLifecycle{}
+
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return
+		}
+
+		if !slices.Contains([]string{api.EventLifecycleInstanceRestarted, api.EventLifecycleInstanceStopped, api.EventLifecycleInstanceShutdown, api.EventLifecycleInstanceDeleted, api.EventLifecycleInstanceRenamed, api.EventLifecycleInstanceMigrated}, lifecycleEvent.Action) {
+			return
+		}
+
+		projectName := lifecycleEvent.Project
+		if projectName == "" {
+			projectName = api.ProjectDefaultName
+		}
+
+		if lifecycleEvent.Action == api.EventLifecycleInstanceRenamed {
+			oldName, ok := lifecycleEvent.Context["old_name"].(string)
+			if ok {
+				d.seccomp.ReleaseInstance(projectName, oldName)
+			}
+
+			return
+		}
+
+		d.seccomp.ReleaseInstance(projectName, lifecycleEvent.Name)
+	}
+}
@@ -40,6 +40,7 @@ import (
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/archive"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
@@ -692,6 +693,48 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Currently not allowed to create storage volumes of type %q", req.Type))
 	}
 
+	// A copy without configuration inherits the source volume's one, so show that to the scriptlet instead.
+	if s.GlobalConfig.AdmissionScriptlet() != "" && req.Source.Type == "copy" && req.Config == nil && (req.Source.Location == "" || req.Source.Location == s.ServerName) {
+		srcProjectName := projectName
+		if req.Source.Project != "" {
+			srcProjectName, err = project.StorageVolumeProject(s.DB.Cluster, req.Source.Project, db.StoragePoolVolumeTypeCustom)
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			srcPoolID, err := tx.GetStoragePoolID(ctx, req.Source.Pool)
+			if err != nil {
+				return err
+			}
+
+			srcVolume, err := tx.GetStoragePoolVolume(ctx, srcPoolID, srcProjectName, db.StoragePoolVolumeTypeCustom, req.Source.Name, true)
+			if err != nil {
+				return err
+			}
+
+			req.Config = srcVolume.Config
+
+			return nil
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionCreate,
+		Type:    apiScriptlet.AdmissionTypeStorageVolume,
+		Project: projectName,
+		Pool:    poolName,
+		Name:    req.Name,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	var poolID int64
 	var dbVolume *db.StorageVolume
 
@@ -1892,6 +1935,18 @@ func storagePoolVolumePut(d *Daemon, r *http.Request) response.Response {
 		// Only apply changes during a snapshot restore if a non-nil config is supplied to avoid clearing
 		// the volume's config if only restoring snapshot.
 		if req.Config != nil || req.Restore == "" {
+			err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+				Action:  apiScriptlet.AdmissionActionUpdate,
+				Type:    apiScriptlet.AdmissionTypeStorageVolume,
+				Project: projectName,
+				Pool:    poolName,
+				Name:    volumeName,
+				Object:  &req,
+			}, &req.Config)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
 			// Possibly check if project limits are honored.
 			err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 				return project.AllowVolumeUpdate(tx, projectName, volumeName, req, dbVolume.Config)
@@ -2059,6 +2114,18 @@ func storagePoolVolumePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = admissionCheck(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionUpdate,
+		Type:    apiScriptlet.AdmissionTypeStorageVolume,
+		Project: projectName,
+		Pool:    poolName,
+		Name:    volumeName,
+		Object:  &req,
+	}, &req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Use an empty operation for this sync response to pass the requestor
 	op := &operations.Operation{}
 	op.SetRequestor(r)
@@ -2221,6 +2288,25 @@ func createStoragePoolVolumeFromISO(s *state.State, r *http.Request, requestProj
 		return response.BadRequest(fmt.Errorf("Missing volume name"))
 	}
 
+	// ISO volumes are created without any configuration so the scriptlet can only accept or reject them.
+	req := api.StorageVolumesPost{
+		Name:        volName,
+		Type:        db.StoragePoolVolumeTypeNameCustom,
+		ContentType: db.StoragePoolVolumeContentTypeNameISO,
+	}
+
+	err := admissionCheckFixed(s, r, &apiScriptlet.AdmissionRequest{
+		Action:  apiScriptlet.AdmissionActionCreate,
+		Type:    apiScriptlet.AdmissionTypeStorageVolume,
+		Project: projectName,
+		Pool:    pool,
+		Name:    volName,
+		Object:  &req,
+	}, req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Create isos directory if needed.
 	if !util.PathExists(internalUtil.VarPath("isos")) {
 		err := os.MkdirAll(internalUtil.VarPath("isos"), 0644)
@@ -2405,6 +2491,28 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		return response.InternalError(err)
 	}
 
+	// The configuration is restored from the backup itself so the scriptlet can only accept or reject it.
+	if bInfo.Config != nil && bInfo.Config.Volume != nil {
+		req := api.StorageVolumesPost{
+			StorageVolumePut: bInfo.Config.Volume.StorageVolumePut,
+			Name:             bInfo.Name,
+			Type:             db.StoragePoolVolumeTypeNameCustom,
+			ContentType:      bInfo.Config.Volume.ContentType,
+		}
+
+		err = admissionCheckFixed(s, r, &apiScriptlet.AdmissionRequest{
+			Action:  apiScriptlet.AdmissionActionCreate,
+			Type:    apiScriptlet.AdmissionTypeStorageVolume,
+			Project: projectName,
+			Pool:    bInfo.Pool,
+			Name:    bInfo.Name,
+			Object:  &req,
+		}, req.Config)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Copy reverter so far so we can use it inside run after this function has finished.
 	runRevert := revert.Clone()
 
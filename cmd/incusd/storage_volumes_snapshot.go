@@ -138,7 +138,7 @@ func storagePoolVolumeSnapshotsTypePost(d *Daemon, r *http.Request) response.Res
 			return err
 		}
 
-		err = project.AllowSnapshotCreation(p)
+		err = project.AllowSnapshotCreation(ctx, tx, p)
 		if err != nil {
 			return err
 		}
@@ -1222,7 +1222,7 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 			}
 
 			for _, v := range allVolumes {
-				err = project.AllowSnapshotCreation(projects[v.ProjectName])
+				err = project.AllowSnapshotCreation(ctx, tx, projects[v.ProjectName])
 				if err != nil {
 					continue
 				}
@@ -68,7 +68,10 @@ func autoRemoveExpiredTokens(ctx context.Context, s *state.State) {
 
 func autoRemoveExpiredTokensTask(d *Daemon) (task.Func, task.Schedule) {
 	f := func(ctx context.Context) {
-		autoRemoveExpiredTokens(ctx, d.State())
+		s := d.State()
+
+		autoRemoveExpiredTokens(ctx, s)
+		autoRemoveExpiredAuthTokens(ctx, s)
 	}
 
 	return f, task.Every(time.Minute)
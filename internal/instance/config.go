@@ -63,6 +63,38 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What order to start the instances in
 	"boot.autostart.priority": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on)
+	// Comma-separated list of instances (in the same project) that must be running and ready before this instance starts.
+	// Those instances are also shut down after this one.
+	//
+	// See {ref}`instance-options-boot-dependencies` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances to start before this one
+	"boot.depends_on": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on.timeout)
+	// Once exceeded, the instance fails to start if a dependency isn't running, or starts anyway if a dependency is running but not ready.
+	// ---
+	//  type: integer
+	//  defaultdesc: `60`
+	//  liveupdate: yes
+	//  shortdesc: Number of seconds to wait for the dependencies to be running and ready
+	"boot.depends_on.timeout": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.idle_stop)
+	// The instance is shut down once no connection went through its host-bound TCP proxy devices for that long.
+	// Combine with the `start_on_connect` option of those devices to start it again on the next connection.
+	//
+	// See {ref}`instance-options-boot-socket-activation` for more information.
+	// ---
+	//  type: integer
+	//  defaultdesc: `0` (disabled)
+	//  liveupdate: yes
+	//  shortdesc: Number of minutes without proxied connections after which to stop the instance
+	"boot.idle_stop": validate.Optional(validate.IsUint32),
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.stop.priority)
 	// The instance with the highest value is shut down first.
 	// ---
@@ -177,6 +209,117 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "stop", "stateful-stop", "force-stop")),
 
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.type)
+	// Possible values are `exec` (run `healthcheck.command` inside the instance), `tcp` (connect to `healthcheck.address`)
+	// and `http` (send a `GET` request to `healthcheck.path` on `healthcheck.address`).
+	//
+	// See {ref}`instance-options-healthcheck` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Type of health check
+	"healthcheck.type": validate.Optional(validate.IsOneOf("exec", "tcp", "http")),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.command)
+	// The check passes if the command exits with a zero status.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  condition: `healthcheck.type` is `exec`
+	//  shortdesc: Command to run inside the instance
+	"healthcheck.command": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.address)
+	// Use the `<host>:<port>` format. If the host is omitted (`:<port>`), the first global address of the instance is used.
+	// Wildcard addresses aren't allowed.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  condition: `healthcheck.type` is `tcp` or `http`
+	//  shortdesc: Address to connect to
+	"healthcheck.address": validate.Optional(func(value string) error {
+		// An empty host stands for the instance's own address.
+		if strings.HasPrefix(value, ":") {
+			_, err := strconv.ParseUint(strings.TrimPrefix(value, ":"), 10, 16)
+			if err != nil {
+				return fmt.Errorf("Invalid port in %q", value)
+			}
+
+			return nil
+		}
+
+		return validate.IsListenAddress(true, false, true)(value)
+	}),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.path)
+	// The check passes if the server replies with a `2xx` or `3xx` status code.
+	// ---
+	//  type: string
+	//  defaultdesc: `/`
+	//  liveupdate: yes
+	//  condition: `healthcheck.type` is `http`
+	//  shortdesc: HTTP path to request
+	"healthcheck.path": validate.Optional(validate.IsRequestURL),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.interval)
+	//
+	// ---
+	//  type: integer
+	//  defaultdesc: `30`
+	//  liveupdate: yes
+	//  shortdesc: Number of seconds between checks
+	"healthcheck.interval": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.timeout)
+	//
+	// ---
+	//  type: integer
+	//  defaultdesc: `5`
+	//  liveupdate: yes
+	//  shortdesc: Number of seconds after which a check is considered failed
+	"healthcheck.timeout": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.failures)
+	//
+	// ---
+	//  type: integer
+	//  defaultdesc: `3`
+	//  liveupdate: yes
+	//  shortdesc: Number of consecutive failed checks after which the instance is unhealthy
+	"healthcheck.failures": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.action)
+	// Possible values are `none`, `restart`, `stop` and `evacuate` (move the instance to another cluster member and start it there).
+	// ---
+	//  type: string
+	//  defaultdesc: `none`
+	//  liveupdate: yes
+	//  shortdesc: What to do when the instance becomes unhealthy
+	"healthcheck.action": validate.Optional(validate.IsOneOf("none", "restart", "stop", "evacuate")),
+
+	// gendoc:generate(entity=instance, group=ignition, key=ignition.config)
+	// The content is an Ignition (version 3) JSON configuration, as used by Fedora CoreOS and Flatcar.
+	// The configurations set on the profiles and on the instance are merged, in that order.
+	//
+	// See {ref}`instance-options-ignition` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: If supported by image
+	//  shortdesc: Ignition configuration
+	"ignition.config": validate.Optional(IsIgnitionConfig),
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=logging.target)
+	// When set to `loki`, the console log, the LXC or QEMU log and (for virtual machines with a running agent)
+	// the guest journal are shipped to the Loki server configured on the server.
+	//
+	// See {ref}`instance-logging-loki` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Where to ship the instance logs
+	"logging.target": validate.Optional(validate.IsOneOf("loki")),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	//
@@ -259,6 +402,48 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Whether to allow for stateful stop/start and snapshots
 	"migration.stateful": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=placement, key=placement.affinity)
+	// A comma-separated list of `key=value` pairs (for example `user.app=web`).
+	// The instance is placed on cluster members that already run instances from the same project
+	// whose expanded configuration matches all of the pairs.
+	//
+	// See {ref}`clustering-instance-placement-affinity` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances to place the instance together with
+	"placement.affinity": validate.Optional(validatePlacementSelector),
+
+	// gendoc:generate(entity=instance, group=placement, key=placement.affinity.mode)
+	// Possible values are `soft` (the rule is a preference) and `hard` (the rule must be satisfied).
+	// ---
+	//  type: string
+	//  defaultdesc: `soft`
+	//  liveupdate: yes
+	//  shortdesc: Whether the affinity rule is mandatory
+	"placement.affinity.mode": validate.Optional(validate.IsOneOf(PlacementModeSoft, PlacementModeHard)),
+
+	// gendoc:generate(entity=instance, group=placement, key=placement.anti_affinity)
+	// A comma-separated list of `key=value` pairs (for example `user.app=db`).
+	// The instance is kept away from cluster members that already run instances from the same project
+	// whose expanded configuration matches all of the pairs.
+	//
+	// See {ref}`clustering-instance-placement-affinity` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances to keep the instance away from
+	"placement.anti_affinity": validate.Optional(validatePlacementSelector),
+
+	// gendoc:generate(entity=instance, group=placement, key=placement.anti_affinity.mode)
+	// Possible values are `soft` (the rule is a preference) and `hard` (the rule must be satisfied).
+	// ---
+	//  type: string
+	//  defaultdesc: `soft`
+	//  liveupdate: yes
+	//  shortdesc: Whether the anti-affinity rule is mandatory
+	"placement.anti_affinity.mode": validate.Optional(validate.IsOneOf(PlacementModeSoft, PlacementModeHard)),
+
 	// Caller is responsible for full validation of any raw.* value.
 
 	// gendoc:generate(entity=instance, group=raw, key=raw.apparmor)
@@ -296,6 +481,16 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Prevents the instance from being deleted
 	"security.protection.delete": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=security, key=security.session_recording)
+	// When enabled, interactive `incus exec` and console sessions are recorded in the asciinema v2 format.
+	// Recordings are stored in the server's `recordings` directory (see `storage.recordings_volume`) and can be retrieved through `/1.0/instances/NAME/sessions`.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether to record interactive sessions
+	"security.session_recording": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.schedule)
 	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-and-space-separated list of schedule aliases (`@startup`, `@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic snapshots.
 	//
@@ -400,6 +595,13 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Instance marked itself as ready
 	"volatile.last_state.ready": validate.IsBool,
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.ready_reported)
+	//
+	// ---
+	//  type: bool
+	//  shortdesc: Instance reported being ready at least once
+	"volatile.ready_reported": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.rebalance.last_move)
 	//
 	// ---
@@ -806,6 +1008,17 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	//  shortdesc: Whether to allow BPF programs
 	"security.syscalls.intercept.bpf.devices": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=security, key=security.syscalls.intercept.keyctl)
+	// Joining a new session keyring fails as unsupported once the key quota of the instance's user is exhausted,
+	// so that container runtimes fall back to not using one.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: container
+	//  shortdesc: Whether to handle the `keyctl` system call
+	"security.syscalls.intercept.keyctl": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=security, key=security.syscalls.intercept.mknod)
 	// These system calls allow creation of a limited subset of char/block devices.
 	// ---
@@ -817,7 +1030,7 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	"security.syscalls.intercept.mknod": validate.Optional(validate.IsBool),
 
 	// gendoc:generate(entity=instance, group=security, key=security.syscalls.intercept.mount)
-	//
+	// This also covers the creation of file systems through the new mount API (`fsopen`, `fsconfig` and `fsmount`).
 	// ---
 	//  type: bool
 	//  defaultdesc: `false`
@@ -935,6 +1148,18 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to back the instance using huge pages
 	"limits.memory.hugepages": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=resource-limits, key=limits.memory.max)
+	// Memory between `limits.memory` (at boot time) and this size can be added to or removed from the running VM
+	// by changing `limits.memory`, using a `virtio-mem` device. This requires guest support.
+	//
+	// See {ref}`instance-options-limits-memory-hotplug` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Maximum memory the VM can grow to while running
+	"limits.memory.max": validate.Optional(validate.IsSize),
+
 	// Caller is responsible for full validation of any raw.* value.
 
 	// gendoc:generate(entity=instance, group=raw, key=raw.qemu)
@@ -1071,6 +1296,28 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: The guest owner's `base64`-encoded session blob
 	"security.sev.session.data": validate.Optional(validate.IsAny),
 
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.freeze)
+	// When the agent is running, the guest file systems are frozen while snapshots and backups are taken, making them application-consistent.
+	//
+	// See {ref}`instance-options-snapshots-freeze` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `true`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether to freeze the guest file systems during snapshots and backups
+	"snapshots.freeze": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.freeze.timeout)
+	// The guest file systems are thawed automatically once this delay is exceeded, even if the snapshot or backup is still running.
+	// ---
+	//  type: integer
+	//  defaultdesc: `30`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Number of seconds after which frozen guest file systems are thawed
+	"snapshots.freeze.timeout": validate.Optional(validate.IsUint32),
+
 	// gendoc:generate(entity=instance, group=miscellaneous, key=agent.nic_config)
 	// For containers, the name and MTU of the default network interfaces is used for the instance devices.
 	// For virtual machines, set this option to `true` to set the name and MTU of the default network interfaces to be the same as the instance devices.
@@ -1089,6 +1336,13 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to regenerate VM NVRAM the next time the instance starts
 	"volatile.apply_nvram": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.memory.boot)
+	//
+	// ---
+	//  type: integer
+	//  shortdesc: Memory size in bytes the VM was booted with when using memory hotplug
+	"volatile.memory.boot": validate.Optional(validate.IsInt64),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.vsock_id)
 	//
 	// ---
@@ -0,0 +1,96 @@
+package instance
+
+import (
+	"slices"
+	"strings"
+)
+
+// ParseDependencies returns the instance names listed in a comma separated `boot.depends_on` value.
+func ParseDependencies(value string) []string {
+	dependencies := []string{}
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(dependencies, name) {
+			continue
+		}
+
+		dependencies = append(dependencies, name)
+	}
+
+	return dependencies
+}
+
+// DependencyCycle returns the cycle going through the named instance (starting and ending with it), or nil if
+// there is none. The dependencies map holds the direct dependencies of each instance.
+func DependencyCycle(name string, dependencies map[string][]string) []string {
+	visited := map[string]bool{}
+
+	var walk func(current string, path []string) []string
+	walk = func(current string, path []string) []string {
+		for _, dependency := range dependencies[current] {
+			if dependency == name {
+				return append(path, dependency)
+			}
+
+			if visited[dependency] {
+				continue
+			}
+
+			visited[dependency] = true
+
+			cycle := walk(dependency, append(path, dependency))
+			if cycle != nil {
+				return cycle
+			}
+		}
+
+		return nil
+	}
+
+	return walk(name, []string{name})
+}
+
+// DependencyLevels returns, for each instance of the dependencies map, the length of its longest chain of
+// dependencies within the map. Instances must be started in increasing level order.
+// Reversing the map (mapping each instance to its dependents) gives the order in which to stop them instead.
+func DependencyLevels(dependencies map[string][]string) map[string]int {
+	levels := map[string]int{}
+	visiting := map[string]bool{}
+
+	var level func(name string) int
+	level = func(name string) int {
+		value, ok := levels[name]
+		if ok {
+			return value
+		}
+
+		// Ignore cycles, those are rejected when the configuration is set.
+		if visiting[name] {
+			return 0
+		}
+
+		visiting[name] = true
+
+		value = 0
+		for _, dependency := range dependencies[name] {
+			_, ok := dependencies[dependency]
+			if !ok {
+				continue
+			}
+
+			value = max(value, level(dependency)+1)
+		}
+
+		visiting[name] = false
+		levels[name] = value
+
+		return value
+	}
+
+	for name := range dependencies {
+		level(name)
+	}
+
+	return levels
+}
@@ -0,0 +1,39 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDependencies(t *testing.T) {
+	assert.Equal(t, []string{}, ParseDependencies(""))
+	assert.Equal(t, []string{"db", "cache"}, ParseDependencies("db, cache,,db"))
+}
+
+func TestDependencyCycle(t *testing.T) {
+	dependencies := map[string][]string{
+		"web":   {"db", "cache"},
+		"cache": {"db"},
+		"db":    {},
+	}
+
+	assert.Nil(t, DependencyCycle("web", dependencies))
+
+	dependencies["db"] = []string{"web"}
+	assert.Equal(t, []string{"db", "web", "db"}, DependencyCycle("db", dependencies))
+
+	dependencies["db"] = []string{"db"}
+	assert.Equal(t, []string{"db", "db"}, DependencyCycle("db", dependencies))
+}
+
+func TestDependencyLevels(t *testing.T) {
+	dependencies := map[string][]string{
+		"web":   {"db", "cache", "missing"},
+		"cache": {"db"},
+		"db":    {},
+		"other": {},
+	}
+
+	assert.Equal(t, map[string]int{"web": 2, "cache": 1, "db": 0, "other": 0}, DependencyLevels(dependencies))
+}
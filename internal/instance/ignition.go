@@ -0,0 +1,134 @@
+package instance
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ignitionListKeys are the fields identifying the entries of Ignition lists.
+// Entries sharing the same value for one of them are merged rather than appended.
+var ignitionListKeys = []string{"path", "name", "device", "label"}
+
+// IsIgnitionConfig validates an Ignition configuration.
+func IsIgnitionConfig(value string) error {
+	config := map[string]any{}
+
+	err := json.Unmarshal([]byte(value), &config)
+	if err != nil {
+		return fmt.Errorf("Invalid Ignition configuration: %w", err)
+	}
+
+	ignition, ok := config["ignition"].(map[string]any)
+	if !ok {
+		return fmt.Errorf(`Ignition configuration is missing the "ignition" section`)
+	}
+
+	version, _ := ignition["version"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return fmt.Errorf("Unsupported Ignition configuration version %q, only version 3 is supported", version)
+	}
+
+	return nil
+}
+
+// MergeIgnitionConfigs merges a list of Ignition configurations, later ones taking precedence.
+// Objects are merged recursively and list entries are matched on their path, name, device or label
+// so that a configuration can override the files, units or users defined by an earlier one.
+func MergeIgnitionConfigs(configs []string) (string, error) {
+	configs = slices.DeleteFunc(slices.Clone(configs), func(config string) bool { return strings.TrimSpace(config) == "" })
+	if len(configs) == 0 {
+		return "", nil
+	}
+
+	if len(configs) == 1 {
+		return configs[0], nil
+	}
+
+	var merged any
+	for _, config := range configs {
+		var value any
+
+		err := json.Unmarshal([]byte(config), &value)
+		if err != nil {
+			return "", fmt.Errorf("Invalid Ignition configuration: %w", err)
+		}
+
+		merged = ignitionMerge(merged, value)
+	}
+
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// ignitionMerge merges the child value on top of the parent one.
+func ignitionMerge(parent any, child any) any {
+	switch childValue := child.(type) {
+	case map[string]any:
+		parentValue, ok := parent.(map[string]any)
+		if !ok {
+			return childValue
+		}
+
+		result := maps.Clone(parentValue)
+		for key, value := range childValue {
+			result[key] = ignitionMerge(parentValue[key], value)
+		}
+
+		return result
+	case []any:
+		parentValue, ok := parent.([]any)
+		if !ok {
+			return childValue
+		}
+
+		result := slices.Clone(parentValue)
+		for _, entry := range childValue {
+			index := ignitionListIndex(result, entry)
+			if index < 0 {
+				result = append(result, entry)
+				continue
+			}
+
+			result[index] = ignitionMerge(result[index], entry)
+		}
+
+		return result
+	}
+
+	return child
+}
+
+// ignitionListIndex returns the index of the list entry matching the given one or -1 if none does.
+func ignitionListIndex(list []any, entry any) int {
+	entryValue, ok := entry.(map[string]any)
+	if !ok {
+		// Scalar entries are only added once.
+		switch entry.(type) {
+		case string, float64, bool:
+			return slices.Index(list, entry)
+		}
+
+		return -1
+	}
+
+	for _, key := range ignitionListKeys {
+		id, ok := entryValue[key].(string)
+		if !ok {
+			continue
+		}
+
+		return slices.IndexFunc(list, func(value any) bool {
+			listEntry, ok := value.(map[string]any)
+			return ok && listEntry[key] == id
+		})
+	}
+
+	return -1
+}
@@ -0,0 +1,43 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsIgnitionConfig(t *testing.T) {
+	assert.NoError(t, IsIgnitionConfig(`{"ignition": {"version": "3.4.0"}}`))
+	assert.Error(t, IsIgnitionConfig(`{"ignition": {"version": "2.2.0"}}`))
+	assert.Error(t, IsIgnitionConfig(`{"storage": {}}`))
+	assert.Error(t, IsIgnitionConfig(`ignition: {}`))
+}
+
+func TestMergeIgnitionConfigs(t *testing.T) {
+	config, err := MergeIgnitionConfigs([]string{"", `{"ignition": {"version": "3.4.0"}}`})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ignition": {"version": "3.4.0"}}`, config)
+
+	profile := `{
+		"ignition": {"version": "3.3.0"},
+		"passwd": {"users": [{"name": "core", "sshAuthorizedKeys": ["key1"]}]},
+		"storage": {"files": [{"path": "/etc/hostname", "mode": 420, "contents": {"source": "data:,profile"}}]}
+	}`
+
+	local := `{
+		"ignition": {"version": "3.4.0"},
+		"passwd": {"users": [{"name": "core", "sshAuthorizedKeys": ["key1", "key2"]}, {"name": "admin"}]},
+		"storage": {"files": [{"path": "/etc/hostname", "contents": {"source": "data:,local"}}]}
+	}`
+
+	config, err = MergeIgnitionConfigs([]string{profile, local})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"ignition": {"version": "3.4.0"},
+		"passwd": {"users": [{"name": "core", "sshAuthorizedKeys": ["key1", "key2"]}, {"name": "admin"}]},
+		"storage": {"files": [{"path": "/etc/hostname", "mode": 420, "contents": {"source": "data:,local"}}]}
+	}`, config)
+
+	_, err = MergeIgnitionConfigs([]string{profile, "{"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package instance
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PlacementModeHard indicates that a placement rule must be satisfied.
+const PlacementModeHard = "hard"
+
+// PlacementModeSoft indicates that a placement rule should be satisfied when possible.
+const PlacementModeSoft = "soft"
+
+// PlacementRule represents an instance affinity or anti-affinity rule.
+type PlacementRule struct {
+	// Selector is the set of configuration keys and values another instance must have to match the rule.
+	Selector map[string]string
+
+	// Hard indicates whether the rule must be satisfied or is only a preference.
+	Hard bool
+}
+
+// Matches returns whether the provided (expanded) instance configuration matches the rule's selector.
+func (r *PlacementRule) Matches(config map[string]string) bool {
+	for k, v := range r.Selector {
+		if config[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParsePlacementSelector parses a comma separated list of `key=value` pairs.
+func ParsePlacementSelector(value string) (map[string]string, error) {
+	selector := map[string]string{}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, val, found := strings.Cut(entry, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("Invalid placement selector %q, expected key=value", entry)
+		}
+
+		if strings.HasPrefix(key, ConfigVolatilePrefix) {
+			return nil, fmt.Errorf("Placement selector cannot use volatile key %q", key)
+		}
+
+		_, ok := selector[key]
+		if ok {
+			return nil, fmt.Errorf("Duplicate placement selector key %q", key)
+		}
+
+		selector[key] = val
+	}
+
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("Empty placement selector")
+	}
+
+	return selector, nil
+}
+
+// GetPlacementRules returns the affinity and anti-affinity rules from an (expanded) instance configuration.
+// A nil rule is returned when the matching configuration key isn't set.
+func GetPlacementRules(config map[string]string) (*PlacementRule, *PlacementRule, error) {
+	getRule := func(key string) (*PlacementRule, error) {
+		if config[key] == "" {
+			return nil, nil
+		}
+
+		selector, err := ParsePlacementSelector(config[key])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %q: %w", key, err)
+		}
+
+		return &PlacementRule{
+			Selector: selector,
+			Hard:     config[key+".mode"] == PlacementModeHard,
+		}, nil
+	}
+
+	affinity, err := getRule("placement.affinity")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	antiAffinity, err := getRule("placement.anti_affinity")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return affinity, antiAffinity, nil
+}
+
+// validatePlacementSelector validates a placement selector.
+func validatePlacementSelector(value string) error {
+	_, err := ParsePlacementSelector(value)
+
+	return err
+}
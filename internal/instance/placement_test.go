@@ -0,0 +1,130 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlacementSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]string
+		err      string
+	}{
+		{"Single pair", "user.app=web", map[string]string{"user.app": "web"}, ""},
+		{"Multiple pairs", "user.app=web, user.tier=frontend", map[string]string{"user.app": "web", "user.tier": "frontend"}, ""},
+		{"Empty entries", ",user.app=web,,", map[string]string{"user.app": "web"}, ""},
+		{"Empty value", "user.app=", map[string]string{"user.app": ""}, ""},
+		{"Value with equal sign", "user.app=a=b", map[string]string{"user.app": "a=b"}, ""},
+		{"Missing value", "user.app", nil, `Invalid placement selector "user.app", expected key=value`},
+		{"Missing key", "=web", nil, `Invalid placement selector "=web", expected key=value`},
+		{"Volatile key", "volatile.uuid=1234", nil, `Placement selector cannot use volatile key "volatile.uuid"`},
+		{"Duplicate key", "user.app=web,user.app=db", nil, `Duplicate placement selector key "user.app"`},
+		{"Empty", "", nil, "Empty placement selector"},
+		{"Only separators", " , ,", nil, "Empty placement selector"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := ParsePlacementSelector(tt.value)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				assert.Nil(t, selector)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, selector)
+		})
+	}
+}
+
+func TestPlacementRuleMatches(t *testing.T) {
+	rule := &PlacementRule{Selector: map[string]string{"user.app": "web", "user.tier": "frontend"}}
+
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected bool
+	}{
+		{"All keys match", map[string]string{"user.app": "web", "user.tier": "frontend"}, true},
+		{"Extra keys", map[string]string{"user.app": "web", "user.tier": "frontend", "limits.cpu": "2"}, true},
+		{"Different value", map[string]string{"user.app": "web", "user.tier": "backend"}, false},
+		{"Missing key", map[string]string{"user.app": "web"}, false},
+		{"Empty configuration", map[string]string{}, false},
+		{"Nil configuration", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rule.Matches(tt.config))
+		})
+	}
+}
+
+func TestGetPlacementRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       map[string]string
+		affinity     *PlacementRule
+		antiAffinity *PlacementRule
+		err          string
+	}{
+		{
+			name:   "No rules",
+			config: map[string]string{"limits.cpu": "2"},
+		},
+		{
+			name:     "Soft affinity by default",
+			config:   map[string]string{"placement.affinity": "user.app=db"},
+			affinity: &PlacementRule{Selector: map[string]string{"user.app": "db"}},
+		},
+		{
+			name:         "Hard anti-affinity",
+			config:       map[string]string{"placement.anti_affinity": "user.app=web", "placement.anti_affinity.mode": "hard"},
+			antiAffinity: &PlacementRule{Selector: map[string]string{"user.app": "web"}, Hard: true},
+		},
+		{
+			name: "Both rules",
+			config: map[string]string{
+				"placement.affinity":           "user.app=db",
+				"placement.affinity.mode":      "soft",
+				"placement.anti_affinity":      "user.app=web",
+				"placement.anti_affinity.mode": "hard",
+			},
+			affinity:     &PlacementRule{Selector: map[string]string{"user.app": "db"}},
+			antiAffinity: &PlacementRule{Selector: map[string]string{"user.app": "web"}, Hard: true},
+		},
+		{
+			name:   "Mode without a rule",
+			config: map[string]string{"placement.affinity.mode": "hard"},
+		},
+		{
+			name:   "Invalid affinity",
+			config: map[string]string{"placement.affinity": "user.app"},
+			err:    `Invalid "placement.affinity": Invalid placement selector "user.app", expected key=value`,
+		},
+		{
+			name:   "Invalid anti-affinity",
+			config: map[string]string{"placement.affinity": "user.app=db", "placement.anti_affinity": "volatile.uuid=1"},
+			err:    `Invalid "placement.anti_affinity": Placement selector cannot use volatile key "volatile.uuid"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			affinity, antiAffinity, err := GetPlacementRules(tt.config)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.affinity, affinity)
+			assert.Equal(t, tt.antiAffinity, antiAffinity)
+		})
+	}
+}
@@ -0,0 +1,635 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// ErrTampered is returned when the hash chain of the audit log doesn't match its content.
+var ErrTampered = errors.New("Audit log integrity check failed")
+
+const (
+	// defaultMaxSize is the size above which the log file is rotated.
+	defaultMaxSize = 32 * 1024 * 1024
+
+	// rotatedFiles is the number of rotated log files kept next to the current one.
+	rotatedFiles = 4
+
+	// handlerQueueSize is the number of entries buffered for each handler.
+	handlerQueueSize = 1024
+)
+
+// Filter restricts the entries returned from the audit log.
+type Filter struct {
+	Since    time.Time
+	Until    time.Time
+	Method   string
+	Project  string
+	Username string
+	Protocol string
+
+	// Only return the most recent entries (0 for no limit).
+	Limit int
+}
+
+// head records the most recent entry of the log, so that the removal of entries can be detected.
+// It also identifies the key of the log, so that a change of key can be told apart from tampering.
+type head struct {
+	Sequence int64  `json:"sequence"`
+	Hash     string `json:"hash"`
+	KeyID    string `json:"key_id,omitempty"`
+	MAC      string `json:"mac"`
+}
+
+// handler forwards the entries to a function from its own goroutine.
+type handler struct {
+	queue   chan api.AuditEntry
+	dropped int
+}
+
+// Log is an append-only audit log stored in local files.
+//
+// Each entry is numbered and records the keyed hash of the previous one, so that modifying or removing entries
+// can be detected by Verify without knowing the key. The most recent entry is also recorded in a separate head
+// file, which reveals the truncation of the log, and the log is rotated once it grows too large.
+//
+// If the log fails its integrity check when opened, its files are moved aside and a new chain is started.
+type Log struct {
+	mu       sync.Mutex
+	path     string
+	key      []byte
+	maxSize  int64
+	file     *os.File
+	size     int64
+	sequence int64
+	lastHash string
+
+	handlersMu sync.Mutex
+	handlers   map[string]*handler
+}
+
+// Open opens the audit log at the given path, creating it if missing.
+// The key is used to compute the hashes of the entries and must be kept secret.
+func Open(path string, key []byte) (*Log, error) {
+	if len(key) == 0 {
+		return nil, errors.New("Audit log requires a key")
+	}
+
+	l := &Log{
+		path:     path,
+		key:      key,
+		maxSize:  defaultMaxSize,
+		handlers: map[string]*handler{},
+	}
+
+	// Drop what's left of an entry which was being written when the daemon stopped.
+	err := truncateTornLine(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue the chain from the most recent entry.
+	for _, name := range l.files() {
+		err := readFile(name, func(entry api.AuditEntry) error {
+			l.sequence = entry.Sequence
+			l.lastHash = entry.Hash
+			return nil
+		})
+		if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, ErrTampered) {
+			return nil, err
+		}
+	}
+
+	// Check the existing entries against the head, which may only lag behind if the daemon stopped while recording.
+	hd, err := l.readHead()
+	if err != nil && (!errors.Is(err, os.ErrNotExist) || l.sequence > 0) {
+		err = l.restart(err)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = l.verify(l.openFiles(), l.sequence, l.lastHash, hd, nil)
+		if err != nil {
+			err = l.restart(err)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// restart moves the files of a log which failed its integrity check aside and starts a new chain with an entry
+// recording why and where they went, so that the log keeps being usable.
+func (l *Log) restart(cause error) error {
+	reason := "the previous one failed its integrity check"
+
+	// Tell a change of key, like when the server certificate is regenerated, apart from tampering.
+	data, err := os.ReadFile(l.path + ".head")
+	if err == nil {
+		hd := head{}
+		err = json.Unmarshal(data, &hd)
+		if err == nil && hd.KeyID != "" && hd.KeyID != l.keyID() {
+			reason = "the key of the previous one changed"
+		}
+	}
+
+	logger.Error("Audit log integrity check failed, starting a new chain", logger.Ctx{"path": l.path, "reason": reason, "err": cause})
+
+	suffix := fmt.Sprintf(".broken-%s", time.Now().UTC().Format("20060102T150405Z"))
+	for _, name := range append(l.files(), l.path+".head") {
+		err := os.Rename(name, name+suffix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("Failed moving audit log aside: %w", err)
+		}
+	}
+
+	// Keep the numbering going, the chain itself starts over.
+	l.lastHash = ""
+
+	return l.Append(api.AuditEntry{
+		Timestamp: time.Now().UTC(),
+		Message:   fmt.Sprintf("Started a new chain as %s (%v), its files were renamed with the %q suffix", reason, cause, suffix),
+	})
+}
+
+// truncateTornLine removes the incomplete last line of a log file.
+func truncateTornLine(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed reading audit log: %w", err)
+	}
+
+	if len(data) == 0 || data[len(data)-1] == '\n' {
+		return nil
+	}
+
+	size := bytes.LastIndexByte(data, '\n') + 1
+	logger.Warn("Removing incomplete entry from the end of the audit log", logger.Ctx{"path": path, "size": len(data) - size})
+
+	err = os.Truncate(path, int64(size))
+	if err != nil {
+		return fmt.Errorf("Failed truncating audit log: %w", err)
+	}
+
+	return nil
+}
+
+// hash returns the keyed hash of an entry, computed over all its fields but the hash itself.
+func (l *Log) hash(entry api.AuditEntry) (string, error) {
+	entry.Hash = ""
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	return l.mac(data), nil
+}
+
+// mac returns the hex encoded HMAC of the data.
+func (l *Log) mac(data []byte) string {
+	h := hmac.New(sha256.New, l.key)
+	_, _ = h.Write(data)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// keyID returns a public identifier of the key.
+func (l *Log) keyID() string {
+	return l.mac([]byte("audit-log-key-id"))[:16]
+}
+
+// AddHandler registers a function called with every new entry, used to forward the entries elsewhere.
+// Each handler is called from its own goroutine, so that a slow handler doesn't delay the API requests.
+// Entries are dropped if a handler falls too far behind.
+func (l *Log) AddHandler(name string, f func(entry api.AuditEntry)) {
+	l.RemoveHandler(name)
+
+	h := &handler{queue: make(chan api.AuditEntry, handlerQueueSize)}
+
+	go func() {
+		for entry := range h.queue {
+			f(entry)
+		}
+	}()
+
+	l.handlersMu.Lock()
+	l.handlers[name] = h
+	l.handlersMu.Unlock()
+}
+
+// RemoveHandler removes a handler previously added with AddHandler.
+// Entries already queued for it are still passed to it.
+func (l *Log) RemoveHandler(name string) {
+	l.handlersMu.Lock()
+	defer l.handlersMu.Unlock()
+
+	h, ok := l.handlers[name]
+	if !ok {
+		return
+	}
+
+	close(h.queue)
+	delete(l.handlers, name)
+}
+
+// dispatch queues an entry for all the handlers.
+func (l *Log) dispatch(entry api.AuditEntry) {
+	l.handlersMu.Lock()
+	defer l.handlersMu.Unlock()
+
+	for name, h := range l.handlers {
+		select {
+		case h.queue <- entry:
+			if h.dropped > 0 {
+				logger.Warn("Dropped audit log entries for slow handler", logger.Ctx{"handler": name, "count": h.dropped})
+				h.dropped = 0
+			}
+
+		default:
+			h.dropped++
+		}
+	}
+}
+
+// Append chains the entry to the log and writes it out.
+func (l *Log) Append(entry api.AuditEntry) error {
+	l.mu.Lock()
+
+	entry.Sequence = l.sequence + 1
+	entry.PreviousHash = l.lastHash
+
+	hash, err := l.hash(entry)
+	if err != nil {
+		l.mu.Unlock()
+		return err
+	}
+
+	entry.Hash = hash
+
+	err = l.write(entry)
+	if err != nil {
+		l.mu.Unlock()
+		return err
+	}
+
+	l.sequence = entry.Sequence
+	l.lastHash = entry.Hash
+
+	// The head may lag behind the log, so it doesn't need to be synced.
+	err = l.writeHead()
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	l.dispatch(entry)
+
+	return nil
+}
+
+// write appends an entry to the current file, rotating it first if it's too large.
+func (l *Log) write(entry api.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if l.file != nil && l.size >= l.maxSize {
+		err = l.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if l.file == nil {
+		l.file, err = os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return fmt.Errorf("Failed opening audit log: %w", err)
+		}
+
+		info, err := l.file.Stat()
+		if err != nil {
+			return fmt.Errorf("Failed opening audit log: %w", err)
+		}
+
+		l.size = info.Size()
+	}
+
+	n, err := l.file.Write(append(data, '\n'))
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("Failed writing audit log: %w", err)
+	}
+
+	err = l.file.Sync()
+	if err != nil {
+		return fmt.Errorf("Failed syncing audit log: %w", err)
+	}
+
+	return nil
+}
+
+// rotate moves the current file out of the way, dropping the oldest rotated file.
+func (l *Log) rotate() error {
+	err := l.file.Close()
+	l.file = nil
+	if err != nil {
+		return fmt.Errorf("Failed closing audit log: %w", err)
+	}
+
+	err = os.Remove(fmt.Sprintf("%s.%d", l.path, rotatedFiles))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Failed rotating audit log: %w", err)
+	}
+
+	for i := rotatedFiles - 1; i >= 0; i-- {
+		source := l.path
+		if i > 0 {
+			source = fmt.Sprintf("%s.%d", l.path, i)
+		}
+
+		err = os.Rename(source, fmt.Sprintf("%s.%d", l.path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("Failed rotating audit log: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeHead records the most recent entry in the head file.
+func (l *Log) writeHead() error {
+	hd := head{Sequence: l.sequence, Hash: l.lastHash, KeyID: l.keyID()}
+	hd.MAC = l.mac(fmt.Appendf(nil, "%d:%s", hd.Sequence, hd.Hash))
+
+	data, err := json.Marshal(hd)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(l.path+".head.tmp", data, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed writing audit log head: %w", err)
+	}
+
+	err = os.Rename(l.path+".head.tmp", l.path+".head")
+	if err != nil {
+		return fmt.Errorf("Failed writing audit log head: %w", err)
+	}
+
+	return nil
+}
+
+// readHead returns the most recent entry recorded in the head file.
+func (l *Log) readHead() (*head, error) {
+	data, err := os.ReadFile(l.path + ".head")
+	if err != nil {
+		return nil, err
+	}
+
+	hd := head{}
+	err = json.Unmarshal(data, &hd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid head: %v", ErrTampered, err)
+	}
+
+	if !hmac.Equal([]byte(hd.MAC), []byte(l.mac(fmt.Appendf(nil, "%d:%s", hd.Sequence, hd.Hash)))) {
+		return nil, fmt.Errorf("%w: invalid head", ErrTampered)
+	}
+
+	return &hd, nil
+}
+
+// Entries returns the entries matching the filter, checking the integrity of the whole log.
+// Entries appended while the log is being read aren't returned.
+func (l *Log) Entries(filter Filter) ([]api.AuditEntry, error) {
+	l.mu.Lock()
+	files := l.openFiles()
+	sequence := l.sequence
+	lastHash := l.lastHash
+	l.mu.Unlock()
+
+	entries := []api.AuditEntry{}
+
+	err := l.verify(files, sequence, lastHash, nil, func(entry api.AuditEntry) {
+		if filter.match(entry) {
+			entries = append(entries, entry)
+
+			// Only keep what's needed for the limit.
+			if filter.Limit > 0 && len(entries) > 2*filter.Limit {
+				entries = append(entries[:0], entries[len(entries)-filter.Limit:]...)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+
+	return entries, nil
+}
+
+// Verify checks the integrity of the whole log.
+func (l *Log) Verify() error {
+	l.mu.Lock()
+	files := l.openFiles()
+	sequence := l.sequence
+	lastHash := l.lastHash
+	hd, err := l.readHead()
+	l.mu.Unlock()
+
+	if err != nil && (!errors.Is(err, os.ErrNotExist) || sequence > 0) {
+		closeFiles(files)
+		return err
+	}
+
+	return l.verify(files, sequence, lastHash, hd, nil)
+}
+
+// files returns the paths of the log files, oldest first.
+func (l *Log) files() []string {
+	names := make([]string, 0, rotatedFiles+1)
+	for i := rotatedFiles; i > 0; i-- {
+		names = append(names, fmt.Sprintf("%s.%d", l.path, i))
+	}
+
+	return append(names, l.path)
+}
+
+// openFiles opens the existing log files, oldest first.
+// Opening them while holding the lock guarantees a consistent view in case of concurrent rotation.
+func (l *Log) openFiles() []*os.File {
+	var files []*os.File
+	for _, name := range l.files() {
+		file, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// closeFiles closes all the given files.
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		_ = file.Close()
+	}
+}
+
+// verify walks through the log files up to the given entry, checking the hash chain and passing each entry to the
+// given function. If a head is given, the log must include the entry it records. The files are closed on return.
+func (l *Log) verify(files []*os.File, sequence int64, lastHash string, hd *head, f func(entry api.AuditEntry)) error {
+	defer closeFiles(files)
+
+	var previousSequence int64
+	previousHash := ""
+	first := true
+	headFound := hd == nil || hd.Sequence == 0
+
+	for _, file := range files {
+		err := read(file, func(entry api.AuditEntry) error {
+			// Ignore the entries appended after the requested point.
+			if entry.Sequence > sequence {
+				return nil
+			}
+
+			hash, err := l.hash(entry)
+			if err != nil {
+				return err
+			}
+
+			if !hmac.Equal([]byte(entry.Hash), []byte(hash)) {
+				return fmt.Errorf("%w at entry %d", ErrTampered, entry.Sequence)
+			}
+
+			// The oldest entries may have been rotated out.
+			if !first && (entry.Sequence != previousSequence+1 || entry.PreviousHash != previousHash) {
+				return fmt.Errorf("%w at entry %d", ErrTampered, entry.Sequence)
+			}
+
+			first = false
+			previousSequence = entry.Sequence
+			previousHash = entry.Hash
+
+			if hd != nil && entry.Sequence == hd.Sequence {
+				if entry.Hash != hd.Hash {
+					return fmt.Errorf("%w at entry %d", ErrTampered, entry.Sequence)
+				}
+
+				headFound = true
+			}
+
+			if f != nil {
+				f(entry)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Detect the removal of the most recent entries.
+	if previousSequence != sequence || previousHash != lastHash || !headFound {
+		return fmt.Errorf("%w: entries are missing from the end of the log", ErrTampered)
+	}
+
+	return nil
+}
+
+// readFile parses a log file, calling the given function for each entry.
+func readFile(name string, f func(entry api.AuditEntry) error) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	return read(file, f)
+}
+
+// read parses a log file, calling the given function for each entry.
+func read(file *os.File, f func(entry api.AuditEntry) error) error {
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if len(line) > 0 {
+			entry := api.AuditEntry{}
+
+			err := json.Unmarshal(line, &entry)
+			if err != nil {
+				return fmt.Errorf("%w: invalid entry: %v", ErrTampered, err)
+			}
+
+			err = f(entry)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// match returns whether the entry matches the filter.
+func (f Filter) match(entry api.AuditEntry) bool {
+	if !f.Since.IsZero() && entry.Timestamp.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && entry.Timestamp.After(f.Until) {
+		return false
+	}
+
+	if f.Method != "" && entry.Method != f.Method {
+		return false
+	}
+
+	if f.Project != "" && entry.Project != f.Project {
+		return false
+	}
+
+	if f.Username != "" && entry.Username != f.Username {
+		return false
+	}
+
+	if f.Protocol != "" && entry.Protocol != f.Protocol {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	key := []byte("secret")
+
+	_, err := Open(path, nil)
+	require.Error(t, err)
+
+	l, err := Open(path, key)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	entries := []api.AuditEntry{
+		{Timestamp: now, Method: "POST", URL: "/1.0/instances", Project: "default", Username: "alice", Protocol: "tls", StatusCode: 202},
+		{Timestamp: now.Add(time.Minute), Method: "DELETE", URL: "/1.0/instances/c1?project=frontend", Project: "frontend", Username: "bob", Protocol: "oidc", StatusCode: 403},
+		{Timestamp: now.Add(2 * time.Minute), Method: "PUT", URL: "/1.0", Project: "default", Username: "alice", Protocol: "tls", StatusCode: 200},
+	}
+
+	var forwarded []api.AuditEntry
+	var wg sync.WaitGroup
+	wg.Add(len(entries))
+	l.AddHandler("test", func(entry api.AuditEntry) {
+		forwarded = append(forwarded, entry)
+		wg.Done()
+	})
+
+	for _, entry := range entries {
+		require.NoError(t, l.Append(entry))
+	}
+
+	wg.Wait()
+	l.RemoveHandler("test")
+	assert.Len(t, forwarded, 3)
+
+	// Entries are chained.
+	all, err := l.Entries(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, int64(1), all[0].Sequence)
+	assert.Equal(t, int64(3), all[2].Sequence)
+	assert.Equal(t, "", all[0].PreviousHash)
+	assert.Equal(t, all[0].Hash, all[1].PreviousHash)
+	assert.Equal(t, all[1].Hash, all[2].PreviousHash)
+
+	// Filters.
+	filtered, err := l.Entries(Filter{Username: "alice"})
+	require.NoError(t, err)
+	assert.Len(t, filtered, 2)
+
+	filtered, err = l.Entries(Filter{Since: now.Add(30 * time.Second), Project: "default"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "PUT", filtered[0].Method)
+
+	filtered, err = l.Entries(Filter{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, all[2].Hash, filtered[0].Hash)
+
+	// Re-opening the log continues the chain.
+	l, err = Open(path, key)
+	require.NoError(t, err)
+	require.NoError(t, l.Append(api.AuditEntry{Timestamp: now.Add(3 * time.Minute), Method: "PATCH"}))
+	require.NoError(t, l.Verify())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// Modifying an entry is detected.
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(content), `"status_code":403`, `"status_code":200`, 1)), 0o600))
+	assert.ErrorIs(t, l.Verify(), ErrTampered)
+
+	// Removing the most recent entries is detected, including after re-opening the log.
+	lines := strings.SplitAfter(string(content), "\n")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines[:2], "")), 0o600))
+	assert.ErrorIs(t, l.Verify(), ErrTampered)
+
+	// Re-opening a tampered log moves it aside and starts a new chain.
+	l, err = Open(path, key)
+	require.NoError(t, err)
+	require.NoError(t, l.Verify())
+
+	all, err = l.Entries(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, int64(3), all[0].Sequence)
+	assert.Equal(t, "", all[0].PreviousHash)
+	assert.Contains(t, all[0].Message, "failed its integrity check")
+
+	broken, err := filepath.Glob(path + "*.broken-*")
+	require.NoError(t, err)
+	assert.Len(t, broken, 2)
+
+	require.NoError(t, l.Append(api.AuditEntry{Method: "POST"}))
+	require.NoError(t, l.Verify())
+}
+
+func TestLogKeyChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := Open(path, []byte("secret"))
+	require.NoError(t, err)
+	require.NoError(t, l.Append(api.AuditEntry{Method: "POST"}))
+
+	// Opening the log with another key starts a new chain recording the change.
+	l, err = Open(path, []byte("other"))
+	require.NoError(t, err)
+	require.NoError(t, l.Verify())
+
+	all, err := l.Entries(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Contains(t, all[0].Message, "key of the previous one changed")
+
+	require.NoError(t, l.Append(api.AuditEntry{Method: "PUT"}))
+
+	all, err = l.Entries(Filter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	// The previous chain and its head are kept aside.
+	broken, err := filepath.Glob(path + "*.broken-*")
+	require.NoError(t, err)
+	require.Len(t, broken, 2)
+}
+
+func TestLogTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	key := []byte("secret")
+
+	l, err := Open(path, key)
+	require.NoError(t, err)
+	require.NoError(t, l.Append(api.AuditEntry{Method: "POST"}))
+	require.NoError(t, l.Append(api.AuditEntry{Method: "PUT"}))
+
+	// Simulate a crash in the middle of writing an entry.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"timestamp":"2024-`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// The incomplete entry is dropped and the chain continues.
+	l, err = Open(path, key)
+	require.NoError(t, err)
+	require.NoError(t, l.Verify())
+	require.NoError(t, l.Append(api.AuditEntry{Method: "DELETE"}))
+
+	all, err := l.Entries(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "DELETE", all[2].Method)
+	assert.Equal(t, all[1].Hash, all[2].PreviousHash)
+
+	broken, err := filepath.Glob(path + "*.broken-*")
+	require.NoError(t, err)
+	assert.Empty(t, broken)
+}
+
+func TestLogRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	key := []byte("secret")
+
+	l, err := Open(path, key)
+	require.NoError(t, err)
+
+	l.maxSize = 1
+
+	for i := range 10 {
+		require.NoError(t, l.Append(api.AuditEntry{Method: "POST", StatusCode: 200 + i}))
+	}
+
+	// Only the current file and the rotated ones are kept.
+	_, err = os.Stat(path + ".4")
+	require.NoError(t, err)
+
+	_, err = os.Stat(path + ".5")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	all, err := l.Entries(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, rotatedFiles+1)
+	assert.Equal(t, int64(10), all[len(all)-1].Sequence)
+
+	// The chain continues across the files after re-opening the log.
+	l, err = Open(path, key)
+	require.NoError(t, err)
+	require.NoError(t, l.Append(api.AuditEntry{Method: "PUT"}))
+	require.NoError(t, l.Verify())
+
+	// Removing a rotated file in the middle of the chain is detected.
+	require.NoError(t, os.Remove(path+".2"))
+	assert.ErrorIs(t, l.Verify(), ErrTampered)
+}
+
+func TestValidateSyslogTarget(t *testing.T) {
+	assert.NoError(t, ValidateSyslogTarget("local"))
+	assert.NoError(t, ValidateSyslogTarget("udp://syslog.example.net:514"))
+	assert.NoError(t, ValidateSyslogTarget("tcp://[2001:db8::1]:514"))
+	assert.Error(t, ValidateSyslogTarget("syslog.example.net"))
+	assert.Error(t, ValidateSyslogTarget("http://syslog.example.net:514"))
+	assert.Error(t, ValidateSyslogTarget("udp://syslog.example.net"))
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net/url"
+	"slices"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// SyslogForwarder sends the audit log entries to a syslog server.
+type SyslogForwarder struct {
+	writer *syslog.Writer
+}
+
+// parseSyslogTarget returns the network and address of a syslog target.
+// The target is either "local" for the local syslog daemon or a URL like udp://syslog.example.net:514.
+func parseSyslogTarget(target string) (string, string, error) {
+	if target == "local" {
+		return "", "", nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !slices.Contains([]string{"udp", "tcp"}, u.Scheme) || u.Host == "" || u.Port() == "" {
+		return "", "", fmt.Errorf("Syslog target must be either %q or a URL like udp://<host>:<port> or tcp://<host>:<port>", "local")
+	}
+
+	return u.Scheme, u.Host, nil
+}
+
+// ValidateSyslogTarget validates a syslog target.
+func ValidateSyslogTarget(target string) error {
+	_, _, err := parseSyslogTarget(target)
+	return err
+}
+
+// NewSyslogForwarder connects to the given syslog target.
+func NewSyslogForwarder(target string) (*SyslogForwarder, error) {
+	network, address, err := parseSyslogTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_AUTH, "incus-audit")
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to syslog: %w", err)
+	}
+
+	return &SyslogForwarder{writer: writer}, nil
+}
+
+// Handle sends an entry to syslog, it can be passed to Log.AddHandler.
+func (s *SyslogForwarder) Handle(entry api.AuditEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	_ = s.writer.Info(string(data))
+}
+
+// Close disconnects from syslog.
+func (s *SyslogForwarder) Close() error {
+	return s.writer.Close()
+}
@@ -19,6 +19,9 @@ const (
 
 	// DriverScriptlet provides scriptlet-based authorization. It is compatible with any authentication method.
 	DriverScriptlet string = "scriptlet"
+
+	// DriverRBAC provides role based authorization using the groups stored in the database. It is compatible with any authentication method.
+	DriverRBAC string = "rbac"
 )
 
 // ErrUnknownDriver is the "Unknown driver" error.
@@ -28,6 +31,7 @@ var authorizers = map[string]func() authorizer{
 	DriverTLS:       func() authorizer { return &TLS{} },
 	DriverOpenFGA:   func() authorizer { return &FGA{} },
 	DriverScriptlet: func() authorizer { return &Scriptlet{} },
+	DriverRBAC:      func() authorizer { return &RBAC{} },
 }
 
 type authorizer interface {
@@ -107,6 +111,8 @@ type Opts struct {
 	config          map[string]any
 	projectsGetFunc func(ctx context.Context) (map[int64]string, error)
 	resourcesFunc   func() (*Resources, error)
+	groupsFunc      func(ctx context.Context) ([]api.AuthGroup, error)
+	tokensFunc      func(ctx context.Context, id string) (*api.AuthToken, error)
 }
 
 // Resources represents a set of current API resources as Object slices for use when loading an Authorizer.
@@ -146,6 +152,20 @@ func WithResourcesFunc(f func() (*Resources, error)) func(*Opts) {
 	}
 }
 
+// WithGroupsFunc should be passed into LoadAuthorizer when DriverRBAC is used.
+func WithGroupsFunc(f func(ctx context.Context) ([]api.AuthGroup, error)) func(*Opts) {
+	return func(o *Opts) {
+		o.groupsFunc = f
+	}
+}
+
+// WithTokensFunc can be passed into LoadAuthorizer to allow the use of authentication tokens.
+func WithTokensFunc(f func(ctx context.Context, id string) (*api.AuthToken, error)) func(*Opts) {
+	return func(o *Opts) {
+		o.tokensFunc = f
+	}
+}
+
 // LoadAuthorizer instantiates, configures, and initializes an Authorizer.
 func LoadAuthorizer(ctx context.Context, driver string, logger logger.Logger, certificateCache *certificate.Cache, options ...func(opts *Opts)) (Authorizer, error) {
 	opts := &Opts{}
@@ -169,5 +189,12 @@ func LoadAuthorizer(ctx context.Context, driver string, logger logger.Logger, ce
 		return nil, fmt.Errorf("Failed to load authorizer: %w", err)
 	}
 
-	return d, nil
+	// Restrict the requests made with authentication tokens to their scope, whatever the driver.
+	common := &commonAuthorizer{}
+	err = common.init(driver, logger)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to initialize authorizer: %w", err)
+	}
+
+	return &tokenAuthorizer{Authorizer: d, common: common, tokensFunc: opts.tokensFunc}, nil
 }
@@ -6,4 +6,7 @@ type RequestDetails struct {
 	Protocol             string
 	IsAllProjectsRequest bool
 	ProjectName          string
+
+	// IdentityProviderGroups is only set for OIDC users when a groups claim is configured.
+	IdentityProviderGroups []string
 }
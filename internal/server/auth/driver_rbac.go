@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// RBAC represents the built-in role based authorizer.
+//
+// Identities are granted the roles of the groups they are members of, either directly or through the groups
+// provided by the identity provider. TLS clients which aren't a member of any group keep the access granted by
+// their certificate, as do restricted TLS clients on top of the roles of their groups.
+type RBAC struct {
+	commonAuthorizer
+
+	tls        *TLS
+	groupsFunc func(ctx context.Context) ([]api.AuthGroup, error)
+}
+
+func (rb *RBAC) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
+	if opts.groupsFunc == nil {
+		return errors.New("RBAC authorization driver requires a groups function")
+	}
+
+	rb.tls = &TLS{}
+	err := rb.tls.init(DriverTLS, rb.logger)
+	if err != nil {
+		return err
+	}
+
+	err = rb.tls.load(ctx, certificateCache, opts)
+	if err != nil {
+		return err
+	}
+
+	rb.groupsFunc = opts.groupsFunc
+	return nil
+}
+
+// identityRoles returns the roles granted to the requestor and whether it is a member of any group.
+func (rb *RBAC) identityRoles(ctx context.Context, details *requestDetails) ([]api.AuthGroupRole, bool, error) {
+	return rbacIdentityRoles(ctx, rb.groupsFunc, details)
+}
+
+// rbacIdentityRoles returns the roles granted to the requestor by the groups and whether it is a member of any group.
+func rbacIdentityRoles(ctx context.Context, groupsFunc func(ctx context.Context) ([]api.AuthGroup, error), details *requestDetails) ([]api.AuthGroupRole, bool, error) {
+	groups, err := groupsFunc(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("Failed loading authorization groups: %w", err)
+	}
+
+	protocol := details.authenticationProtocol()
+	username := details.username()
+	identityProviderGroups := details.groups()
+
+	var roles []api.AuthGroupRole
+	isMember := false
+
+	for _, group := range groups {
+		if !slices.Contains(group.Identities[protocol], username) && !slices.ContainsFunc(group.IdentityProviderGroups, func(name string) bool {
+			return protocol == api.AuthenticationMethodOIDC && slices.Contains(identityProviderGroups, name)
+		}) {
+			continue
+		}
+
+		isMember = true
+		roles = append(roles, group.Roles...)
+	}
+
+	return roles, isMember, nil
+}
+
+// keepsCertificateAccess returns whether the requestor keeps the access granted by its certificate.
+// This is the case for TLS clients which aren't a member of any group and for restricted TLS clients, whose
+// certificate only grants access to its projects.
+func (rb *RBAC) keepsCertificateAccess(details *requestDetails, isMember bool) bool {
+	if details.authenticationProtocol() != api.AuthenticationMethodTLS {
+		return false
+	}
+
+	if !isMember {
+		return true
+	}
+
+	_, isNotRestricted, _, err := rb.tls.certificateDetails(details.username())
+
+	return err == nil && !isNotRestricted
+}
+
+// CheckPermission returns an error if the user does not have the given Entitlement on the given Object.
+func (rb *RBAC) CheckPermission(ctx context.Context, r *http.Request, object Object, entitlement Entitlement) error {
+	details, err := rb.requestDetails(r)
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return nil
+	}
+
+	roles, isMember, err := rb.identityRoles(ctx, details)
+	if err != nil {
+		return err
+	}
+
+	if isMember && rbacAllowed(roles, object, entitlement) {
+		return nil
+	}
+
+	if rb.keepsCertificateAccess(details, isMember) {
+		return rb.tls.CheckPermission(ctx, r, object, entitlement)
+	}
+
+	return api.StatusErrorf(http.StatusForbidden, "Permission denied")
+}
+
+// GetPermissionChecker returns a function that can be used to check whether a user has the required entitlement on an authorization object.
+func (rb *RBAC) GetPermissionChecker(ctx context.Context, r *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	details, err := rb.requestDetails(r)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return func(Object) bool { return true }, nil
+	}
+
+	roles, isMember, err := rb.identityRoles(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rb.keepsCertificateAccess(details, isMember) {
+		return func(object Object) bool {
+			return rbacAllowed(roles, object, entitlement)
+		}, nil
+	}
+
+	// The certificate may not grant access to the project of the request while the roles do.
+	tlsChecker, err := rb.tls.GetPermissionChecker(ctx, r, entitlement, objectType)
+	if err != nil {
+		if !isMember {
+			return nil, err
+		}
+
+		tlsChecker = func(Object) bool { return false }
+	}
+
+	return func(object Object) bool {
+		return rbacAllowed(roles, object, entitlement) || tlsChecker(object)
+	}, nil
+}
+
+// GetInstanceAccess returns the list of entities who have access to the instance.
+func (rb *RBAC) GetInstanceAccess(ctx context.Context, projectName string, instanceName string) (*api.Access, error) {
+	access, err := rb.tls.GetInstanceAccess(ctx, projectName, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return rb.projectAccess(ctx, projectName, access)
+}
+
+// GetProjectAccess returns the list of entities who have access to the project.
+func (rb *RBAC) GetProjectAccess(ctx context.Context, projectName string) (*api.Access, error) {
+	access, err := rb.tls.GetProjectAccess(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return rb.projectAccess(ctx, projectName, access)
+}
+
+// projectAccess replaces the certificate based access entries of group members by the roles they hold in the project.
+// Restricted certificates keep their access entries as their access is kept on top of their roles.
+func (rb *RBAC) projectAccess(ctx context.Context, projectName string, tlsAccess *api.Access) (*api.Access, error) {
+	groups, err := rb.groupsFunc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading authorization groups: %w", err)
+	}
+
+	access := api.Access{}
+	members := map[string]bool{}
+
+	for _, group := range groups {
+		for _, fingerprint := range group.Identities[api.AuthenticationMethodTLS] {
+			_, isNotRestricted, _, err := rb.tls.certificateDetails(fingerprint)
+			members[fingerprint] = err != nil || isNotRestricted
+		}
+
+		for _, role := range group.Roles {
+			if role.Project != "" && role.Project != projectName {
+				continue
+			}
+
+			for method, identifiers := range group.Identities {
+				for _, identifier := range identifiers {
+					access = append(access, api.AccessEntry{Identifier: identifier, Role: role.Role, Provider: method})
+				}
+			}
+
+			for _, name := range group.IdentityProviderGroups {
+				access = append(access, api.AccessEntry{Identifier: name, Role: role.Role, Provider: "oidc-group"})
+			}
+		}
+	}
+
+	for _, entry := range *tlsAccess {
+		if !members[entry.Identifier] {
+			access = append(access, entry)
+		}
+	}
+
+	return &access, nil
+}
+
+// rbacInheritedObjectTypes are the object types which projects can inherit from the default project.
+var rbacInheritedObjectTypes = []ObjectType{ObjectTypeImage, ObjectTypeProfile, ObjectTypeStorageVolume, ObjectTypeStorageBucket, ObjectTypeNetwork, ObjectTypeNetworkZone}
+
+// rbacAllowed returns whether any of the roles grants the entitlement on the object.
+func rbacAllowed(roles []api.AuthGroupRole, object Object, entitlement Entitlement) bool {
+	for _, role := range roles {
+		if rbacRoleAllowed(role, object, entitlement) {
+			return true
+		}
+	}
+
+	// Also allow read-only access to inherited resources.
+	if len(roles) > 0 && object.Project() == api.ProjectDefaultName && entitlement == EntitlementCanView && slices.Contains(rbacInheritedObjectTypes, object.Type()) {
+		return true
+	}
+
+	return false
+}
+
+// rbacRoleAllowed returns whether the role grants the entitlement on the object.
+func rbacRoleAllowed(role api.AuthGroupRole, object Object, entitlement Entitlement) bool {
+	// Server-wide administrators can do anything.
+	if role.Role == api.AuthRoleAdmin && role.Project == "" {
+		return true
+	}
+
+	// Server level objects.
+	switch object.Type() {
+	case ObjectTypeServer:
+		return slices.Contains([]Entitlement{EntitlementCanView, EntitlementCanViewResources, EntitlementCanViewMetrics}, entitlement)
+	case ObjectTypeCertificate, ObjectTypeStoragePool, ObjectTypeNetworkIntegration:
+		return entitlement == EntitlementCanView
+	case ObjectTypeUser:
+		return false
+	}
+
+	// Project level objects.
+	if role.Project != "" && role.Project != object.Project() {
+		return false
+	}
+
+	switch role.Role {
+	case api.AuthRoleViewer:
+		return slices.Contains([]Entitlement{EntitlementCanView, EntitlementCanViewEvents, EntitlementCanViewOperations, EntitlementCanViewMetrics}, entitlement)
+	case api.AuthRoleOperator:
+		// Operators can't modify the project itself.
+		return object.Type() != ObjectTypeProject || entitlement != EntitlementCanEdit
+	case api.AuthRoleAdmin:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+func TestRBACAllowed(t *testing.T) {
+	projectViewer := []api.AuthGroupRole{{Role: api.AuthRoleViewer, Project: "foo"}}
+	projectOperator := []api.AuthGroupRole{{Role: api.AuthRoleOperator, Project: "foo"}}
+	projectAdmin := []api.AuthGroupRole{{Role: api.AuthRoleAdmin, Project: "foo"}}
+	serverViewer := []api.AuthGroupRole{{Role: api.AuthRoleViewer}}
+	serverAdmin := []api.AuthGroupRole{{Role: api.AuthRoleAdmin}}
+
+	instance := ObjectInstance("foo", "c1")
+	otherInstance := ObjectInstance("bar", "c1")
+
+	// No roles grant nothing.
+	assert.False(t, rbacAllowed(nil, ObjectServer(), EntitlementCanView))
+	assert.False(t, rbacAllowed(nil, instance, EntitlementCanView))
+
+	// Viewers.
+	assert.True(t, rbacAllowed(projectViewer, instance, EntitlementCanView))
+	assert.False(t, rbacAllowed(projectViewer, instance, EntitlementCanExec))
+	assert.False(t, rbacAllowed(projectViewer, otherInstance, EntitlementCanView))
+	assert.True(t, rbacAllowed(serverViewer, otherInstance, EntitlementCanView))
+	assert.True(t, rbacAllowed(projectViewer, ObjectProject("foo"), EntitlementCanViewEvents))
+
+	// Operators can manage the project resources but not the project.
+	assert.True(t, rbacAllowed(projectOperator, instance, EntitlementCanExec))
+	assert.True(t, rbacAllowed(projectOperator, ObjectProject("foo"), EntitlementCanCreateInstances))
+	assert.False(t, rbacAllowed(projectOperator, ObjectProject("foo"), EntitlementCanEdit))
+	assert.False(t, rbacAllowed(projectOperator, otherInstance, EntitlementCanExec))
+
+	// Project administrators can edit the project, but not the server.
+	assert.True(t, rbacAllowed(projectAdmin, ObjectProject("foo"), EntitlementCanEdit))
+	assert.True(t, rbacAllowed(projectAdmin, ObjectServer(), EntitlementCanView))
+	assert.False(t, rbacAllowed(projectAdmin, ObjectServer(), EntitlementCanEdit))
+	assert.False(t, rbacAllowed(projectAdmin, ObjectStoragePool("default"), EntitlementCanEdit))
+	assert.True(t, rbacAllowed(projectAdmin, ObjectStoragePool("default"), EntitlementCanView))
+
+	// Server administrators can do anything.
+	assert.True(t, rbacAllowed(serverAdmin, ObjectServer(), EntitlementCanEdit))
+	assert.True(t, rbacAllowed(serverAdmin, otherInstance, EntitlementCanExec))
+
+	// Resources inherited from the default project are visible.
+	assert.True(t, rbacAllowed(projectViewer, ObjectProfile(api.ProjectDefaultName, "default"), EntitlementCanView))
+	assert.False(t, rbacAllowed(projectViewer, ObjectProfile(api.ProjectDefaultName, "default"), EntitlementCanEdit))
+	assert.False(t, rbacAllowed(projectOperator, ObjectInstance(api.ProjectDefaultName, "c1"), EntitlementCanView))
+}
+
+func TestRBACCertificateAccess(t *testing.T) {
+	groups := []api.AuthGroup{
+		{
+			Name:         "project-bar-admin",
+			AuthGroupPut: api.AuthGroupPut{Roles: []api.AuthGroupRole{{Role: api.AuthRoleAdmin, Project: "bar"}}},
+			Identities:   map[string][]string{api.AuthenticationMethodTLS: {"restricted", "unrestricted"}},
+		},
+	}
+
+	cache := &certificate.Cache{}
+	cache.SetCertificatesAndProjects(map[certificate.Type]map[string]x509.Certificate{
+		certificate.TypeClient: {"restricted": {}, "unrestricted": {}, "other": {}},
+	}, map[string][]string{
+		"restricted": {"foo"},
+		"other":      {"foo"},
+	})
+
+	authorizer := &RBAC{}
+	require.NoError(t, authorizer.init(DriverRBAC, logger.Log))
+	require.NoError(t, authorizer.load(context.Background(), cache, Opts{groupsFunc: func(ctx context.Context) ([]api.AuthGroup, error) { return groups, nil }}))
+
+	newRequest := func(fingerprint string, projectName string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/1.0/instances?project="+projectName, nil)
+		ctx := context.WithValue(r.Context(), request.CtxUsername, fingerprint)
+		ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodTLS)
+
+		return r.WithContext(ctx)
+	}
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		object      Object
+		entitlement Entitlement
+		allowed     bool
+	}{
+		{"Restricted member keeps its certificate projects", "restricted", ObjectInstance("foo", "c1"), EntitlementCanExec, true},
+		{"Restricted member gets its roles", "restricted", ObjectProject("bar"), EntitlementCanEdit, true},
+		{"Restricted member can't access other projects", "restricted", ObjectInstance("baz", "c1"), EntitlementCanView, false},
+		{"Restricted member can't edit its certificate projects", "restricted", ObjectProject("foo"), EntitlementCanEdit, false},
+		{"Unrestricted member is limited to its roles", "unrestricted", ObjectServer(), EntitlementCanEdit, false},
+		{"Non-member keeps its certificate access", "other", ObjectInstance("foo", "c1"), EntitlementCanExec, true},
+		{"Non-member has no roles", "other", ObjectProject("bar"), EntitlementCanEdit, false},
+		{"Restricted member lists the instances of its roles", "restricted", ObjectInstance("bar", "c1"), EntitlementCanView, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest(tt.fingerprint, tt.object.Project())
+
+			err := authorizer.CheckPermission(context.Background(), r, tt.object, tt.entitlement)
+			assert.Equal(t, tt.allowed, err == nil)
+
+			if tt.object.Type() != ObjectTypeInstance {
+				return
+			}
+
+			checker, err := authorizer.GetPermissionChecker(context.Background(), r, tt.entitlement, tt.object.Type())
+			if err == nil {
+				assert.Equal(t, tt.allowed, checker(tt.object))
+			} else {
+				assert.False(t, tt.allowed)
+			}
+		})
+	}
+}
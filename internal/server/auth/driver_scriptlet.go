@@ -13,6 +13,8 @@ import (
 // Scriptlet represents a scriptlet authorizer.
 type Scriptlet struct {
 	commonAuthorizer
+
+	groupsFunc func(ctx context.Context) ([]api.AuthGroup, error)
 }
 
 // CheckPermission returns an error if the user does not have the given Entitlement on the given Object.
@@ -26,6 +28,16 @@ func (s *Scriptlet) CheckPermission(ctx context.Context, r *http.Request, object
 		return nil
 	}
 
+	// The roles of the authorization groups grant access without running the scriptlet.
+	roles, err := s.identityRoles(ctx, details)
+	if err != nil {
+		return err
+	}
+
+	if rbacAllowed(roles, object, entitlement) {
+		return nil
+	}
+
 	authorized, err := authScriptlet.AuthorizationRun(logger.Log, details.actualDetails(), object.String(), string(entitlement))
 	if err != nil {
 		return api.StatusErrorf(http.StatusForbidden, "Authorization scriptlet execution failed with error: %v", err)
@@ -60,7 +72,16 @@ func (s *Scriptlet) GetPermissionChecker(ctx context.Context, r *http.Request, e
 		return allowFunc(true), nil
 	}
 
+	roles, err := s.identityRoles(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+
 	permissionChecker := func(o Object) bool {
+		if rbacAllowed(roles, o, entitlement) {
+			return true
+		}
+
 		authorized, err := authScriptlet.AuthorizationRun(logger.Log, details.actualDetails(), o.String(), string(entitlement))
 		if err != nil {
 			logger.Error("Authorization scriptlet execution failed", logger.Ctx{"err": err})
@@ -79,5 +100,20 @@ func (s *Scriptlet) GetProjectAccess(ctx context.Context, projectName string) (*
 }
 
 func (s *Scriptlet) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
+	s.groupsFunc = opts.groupsFunc
 	return nil
 }
+
+// identityRoles returns the roles granted to the requestor by the authorization groups.
+func (s *Scriptlet) identityRoles(ctx context.Context, details *requestDetails) ([]api.AuthGroupRole, error) {
+	if s.groupsFunc == nil {
+		return nil, nil
+	}
+
+	roles, _, err := rbacIdentityRoles(ctx, s.groupsFunc, details)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"slices"
 
@@ -17,6 +18,7 @@ import (
 type TLS struct {
 	commonAuthorizer
 	certificates *certificate.Cache
+	groupsFunc   func(ctx context.Context) ([]api.AuthGroup, error)
 }
 
 func (t *TLS) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
@@ -25,6 +27,7 @@ func (t *TLS) load(ctx context.Context, certificateCache *certificate.Cache, opt
 	}
 
 	t.certificates = certificateCache
+	t.groupsFunc = opts.groupsFunc
 	return nil
 }
 
@@ -39,7 +42,25 @@ func (t *TLS) CheckPermission(ctx context.Context, r *http.Request, object Objec
 		return nil
 	}
 
+	isMappedOIDC, err := t.isMappedOIDC(ctx, details)
+	if err != nil {
+		return err
+	}
+
 	authenticationProtocol := details.authenticationProtocol()
+	if isMappedOIDC {
+		roles, _, err := rbacIdentityRoles(ctx, t.groupsFunc, details)
+		if err != nil {
+			return err
+		}
+
+		if rbacAllowed(roles, object, entitlement) {
+			return nil
+		}
+
+		return api.StatusErrorf(http.StatusForbidden, "User does not have permission for %q", object)
+	}
+
 	if authenticationProtocol != api.AuthenticationMethodTLS {
 		t.logger.Warn("Authentication protocol is not compatible with authorization driver", logger.Ctx{"protocol": authenticationProtocol})
 		// Return nil. If the server has been configured with an authentication method but no associated authorization driver,
@@ -117,7 +138,23 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 		return allowFunc(true), nil
 	}
 
+	isMappedOIDC, err := t.isMappedOIDC(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+
 	authenticationProtocol := details.authenticationProtocol()
+	if isMappedOIDC {
+		roles, _, err := rbacIdentityRoles(ctx, t.groupsFunc, details)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(object Object) bool {
+			return rbacAllowed(roles, object, entitlement)
+		}, nil
+	}
+
 	if authenticationProtocol != api.AuthenticationMethodTLS {
 		t.logger.Warn("Authentication protocol is not compatible with authorization driver", logger.Ctx{"protocol": authenticationProtocol})
 		// Allow all. If the server has been configured with an authentication method but no associated authorization driver,
@@ -190,6 +227,28 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 	}, nil
 }
 
+// isMappedOIDC returns whether the request comes from an OIDC user while identity provider groups are mapped to
+// authorization groups. Those users are restricted to the roles of the authorization groups they belong to, even
+// when their identity provider groups are missing from the request.
+func (t *TLS) isMappedOIDC(ctx context.Context, details *requestDetails) (bool, error) {
+	if t.groupsFunc == nil || details.authenticationProtocol() != api.AuthenticationMethodOIDC {
+		return false, nil
+	}
+
+	groups, err := t.groupsFunc(ctx)
+	if err != nil {
+		return false, fmt.Errorf("Failed loading authorization groups: %w", err)
+	}
+
+	for _, group := range groups {
+		if len(group.IdentityProviderGroups) > 0 || len(group.Identities[api.AuthenticationMethodOIDC]) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // certificateDetails returns the certificate type, a boolean indicating if the certificate is *not* restricted, a slice of
 // project names for this certificate, or an error if the certificate could not be found.
 func (t *TLS) certificateDetails(fingerprint string) (certificate.Type, bool, []string, error) {
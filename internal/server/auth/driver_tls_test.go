@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+func TestTLSMappedOIDC(t *testing.T) {
+	groups := []api.AuthGroup{{
+		Name: "frontend",
+		AuthGroupPut: api.AuthGroupPut{
+			Roles:                  []api.AuthGroupRole{{Role: api.AuthRoleOperator, Project: "frontend"}},
+			IdentityProviderGroups: []string{"developers"},
+		},
+	}}
+
+	authorizer := &TLS{groupsFunc: func(ctx context.Context) ([]api.AuthGroup, error) { return groups, nil }}
+	require.NoError(t, authorizer.init(DriverTLS, logger.Log))
+
+	newRequest := func(identityProviderGroups []string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/1.0/instances", nil)
+		ctx := context.WithValue(r.Context(), request.CtxUsername, "jane.doe@example.com")
+		ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodOIDC)
+		if identityProviderGroups != nil {
+			ctx = context.WithValue(ctx, request.CtxIdentityProviderGroups, identityProviderGroups)
+		}
+
+		return r.WithContext(ctx)
+	}
+
+	// Without a groups claim, OIDC users get no access once groups are mapped.
+	r := newRequest(nil)
+	assert.Error(t, authorizer.CheckPermission(context.Background(), r, ObjectServer(), EntitlementCanEdit))
+	assert.Error(t, authorizer.CheckPermission(context.Background(), r, ObjectInstance("frontend", "c1"), EntitlementCanView))
+
+	// The same applies to requests forwarded without valid groups.
+	r = newRequest(nil)
+	ctx := context.WithValue(r.Context(), request.CtxProtocol, "cluster")
+	ctx = context.WithValue(ctx, request.CtxForwardedUsername, "jane.doe@example.com")
+	ctx = context.WithValue(ctx, request.CtxForwardedProtocol, api.AuthenticationMethodOIDC)
+	assert.Error(t, authorizer.CheckPermission(context.Background(), r.WithContext(ctx), ObjectServer(), EntitlementCanEdit))
+
+	// With a groups claim, OIDC users are restricted to the roles mapped to their groups.
+	r = newRequest([]string{"developers"})
+	assert.NoError(t, authorizer.CheckPermission(context.Background(), r, ObjectInstance("frontend", "c1"), EntitlementCanExec))
+	assert.Error(t, authorizer.CheckPermission(context.Background(), r, ObjectInstance("backend", "c1"), EntitlementCanExec))
+	assert.Error(t, authorizer.CheckPermission(context.Background(), r, ObjectServer(), EntitlementCanEdit))
+
+	checker, err := authorizer.GetPermissionChecker(context.Background(), r, EntitlementCanView, ObjectTypeInstance)
+	require.NoError(t, err)
+	assert.True(t, checker(ObjectInstance("frontend", "c1")))
+	assert.False(t, checker(ObjectInstance("backend", "c1")))
+
+	// Users without any mapped group have no access.
+	r = newRequest([]string{})
+	assert.Error(t, authorizer.CheckPermission(context.Background(), r, ObjectInstance("frontend", "c1"), EntitlementCanView))
+
+	// Without any mapped group, OIDC users keep full access.
+	groups = []api.AuthGroup{}
+	r = newRequest(nil)
+	assert.NoError(t, authorizer.CheckPermission(context.Background(), r, ObjectServer(), EntitlementCanEdit))
+}
@@ -22,12 +22,21 @@ import (
 type Verifier struct {
 	accessTokenVerifier *op.AccessTokenVerifier
 
-	clientID  string
-	issuer    string
-	scopes    []string
-	audience  string
-	claim     string
-	cookieKey []byte
+	clientID    string
+	issuer      string
+	scopes      []string
+	audience    string
+	claim       string
+	groupsClaim string
+	cookieKey   []byte
+}
+
+// AuthenticationResult represents an authenticated OIDC user.
+type AuthenticationResult struct {
+	Username string
+
+	// IdentityProviderGroups is nil unless a groups claim is configured.
+	IdentityProviderGroups []string
 }
 
 // AuthError represents an authentication error.
@@ -44,7 +53,7 @@ func (e AuthError) Unwrap() error {
 }
 
 // Auth extracts the token, validates it and returns the user information.
-func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error) {
+func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Request) (*AuthenticationResult, error) {
 	var token string
 
 	auth := r.Header.Get("Authorization")
@@ -57,7 +66,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// Both returned errors contain information which are needed for the client to authenticate.
 		parts := strings.Split(auth, "Bearer ")
 		if len(parts) != 2 {
-			return "", &AuthError{fmt.Errorf("Bad authorization token, expected a Bearer token")}
+			return nil, &AuthError{fmt.Errorf("Bad authorization token, expected a Bearer token")}
 		}
 
 		token = parts[1]
@@ -65,7 +74,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// When not using a Bearer token, fetch the equivalent from a cookie and move on with it.
 		cookie, err := r.Cookie("oidc_access")
 		if err != nil {
-			return "", &AuthError{err}
+			return nil, &AuthError{err}
 		}
 
 		token = cookie.Value
@@ -76,7 +85,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 
 		o.accessTokenVerifier, err = getAccessTokenVerifier(o.issuer)
 		if err != nil {
-			return "", &AuthError{err}
+			return nil, &AuthError{err}
 		}
 	}
 
@@ -85,25 +94,25 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// See if we can refresh the access token.
 		cookie, cookieErr := r.Cookie("oidc_refresh")
 		if cookieErr != nil {
-			return "", &AuthError{err}
+			return nil, &AuthError{err}
 		}
 
 		// Get the provider.
 		provider, err := o.getProvider(r)
 		if err != nil {
-			return "", &AuthError{err}
+			return nil, &AuthError{err}
 		}
 
 		// Attempt the refresh.
 		tokens, err := rp.RefreshTokens[*oidc.IDTokenClaims](context.TODO(), provider, cookie.Value, "", "")
 		if err != nil {
-			return "", &AuthError{err}
+			return nil, &AuthError{err}
 		}
 
 		// Validate the refreshed token.
 		claims, err = o.VerifyAccessToken(ctx, tokens.AccessToken)
 		if err != nil {
-			return "", &AuthError{err}
+			return nil, &AuthError{err}
 		}
 
 		// If we have a ResponseWriter, refresh the cookies.
@@ -136,22 +145,55 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		}
 	}
 
+	result := &AuthenticationResult{Username: claims.Subject}
+
 	if o.claim != "" {
 		claim := claims.Claims[o.claim]
 		username, ok := claim.(string)
 		if claim == nil || !ok || username == "" {
-			return "", fmt.Errorf("OIDC user is missing required claim %q", o.claim)
+			return nil, fmt.Errorf("OIDC user is missing required claim %q", o.claim)
 		}
 
-		return username, nil
+		result.Username = username
+	} else {
+		user, ok := claims.Claims["email"]
+		if ok && user != nil && user.(string) != "" {
+			result.Username = user.(string)
+		}
+	}
+
+	if o.groupsClaim != "" {
+		result.IdentityProviderGroups = groupsFromClaim(claims.Claims[o.groupsClaim])
 	}
 
-	user, ok := claims.Claims["email"]
-	if ok && user != nil && user.(string) != "" {
-		return user.(string), nil
+	return result, nil
+}
+
+// groupsFromClaim converts the value of a groups claim into a list of group names.
+// A missing or invalid claim results in an empty list.
+func groupsFromClaim(claim any) []string {
+	groups := []string{}
+
+	switch value := claim.(type) {
+	case string:
+		for _, group := range util.SplitNTrimSpace(value, ",", -1, true) {
+			if group != "" {
+				groups = append(groups, group)
+			}
+		}
+
+	case []string:
+		groups = append(groups, value...)
+	case []any:
+		for _, entry := range value {
+			group, ok := entry.(string)
+			if ok && group != "" {
+				groups = append(groups, group)
+			}
+		}
 	}
 
-	return claims.Subject, nil
+	return groups
 }
 
 func (o *Verifier) Login(w http.ResponseWriter, r *http.Request) {
@@ -351,14 +393,14 @@ func getAccessTokenVerifier(issuer string) (*op.AccessTokenVerifier, error) {
 }
 
 // NewVerifier returns a Verifier.
-func NewVerifier(issuer string, clientid string, scope string, audience string, claim string) (*Verifier, error) {
+func NewVerifier(issuer string, clientid string, scope string, audience string, claim string, groupsClaim string) (*Verifier, error) {
 	cookieKey, err := uuid.New().MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create UUID: %w", err)
 	}
 
 	scopes := util.SplitNTrimSpace(scope, ",", -1, false)
-	verifier := &Verifier{issuer: issuer, clientID: clientid, scopes: scopes, audience: audience, cookieKey: cookieKey, claim: claim}
+	verifier := &Verifier{issuer: issuer, clientID: clientid, scopes: scopes, audience: audience, cookieKey: cookieKey, claim: claim, groupsClaim: groupsClaim}
 	verifier.accessTokenVerifier, _ = getAccessTokenVerifier(issuer)
 
 	return verifier, nil
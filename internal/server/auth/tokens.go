@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// TokenEntitlements are the entitlements which can be granted by an authentication token.
+// They apply to the project the token is restricted to and to the resources within it.
+var TokenEntitlements = []Entitlement{
+	EntitlementCanView,
+	EntitlementCanEdit,
+	EntitlementCanCreateImageAliases,
+	EntitlementCanCreateImages,
+	EntitlementCanCreateInstances,
+	EntitlementCanCreateNetworkACLs,
+	EntitlementCanCreateNetworks,
+	EntitlementCanCreateNetworkZones,
+	EntitlementCanCreateProfiles,
+	EntitlementCanCreateStorageBuckets,
+	EntitlementCanCreateStorageVolumes,
+	EntitlementCanViewEvents,
+	EntitlementCanViewOperations,
+	EntitlementCanAccessConsole,
+	EntitlementCanAccessFiles,
+	EntitlementCanConnectSFTP,
+	EntitlementCanExec,
+	EntitlementCanUpdateState,
+	EntitlementCanManageBackups,
+	EntitlementCanManageSnapshots,
+}
+
+// ValidateTokenEntitlements checks that all the given entitlements can be granted by an authentication token.
+func ValidateTokenEntitlements(entitlements []string) error {
+	if len(entitlements) == 0 {
+		return fmt.Errorf("At least one entitlement must be provided")
+	}
+
+	for _, entitlement := range entitlements {
+		if !slices.Contains(TokenEntitlements, Entitlement(entitlement)) {
+			return fmt.Errorf("Entitlement %q can't be granted by an authentication token", entitlement)
+		}
+	}
+
+	return nil
+}
+
+// tokenAuthorizer restricts the requests authenticated with an authentication token to the scope of that token.
+// All other requests are handled by the wrapped authorizer.
+type tokenAuthorizer struct {
+	Authorizer
+
+	common     *commonAuthorizer
+	tokensFunc func(ctx context.Context, id string) (*api.AuthToken, error)
+}
+
+// token returns the authentication token used by the requestor, making sure it's still valid.
+func (t *tokenAuthorizer) token(ctx context.Context, details *requestDetails) (*api.AuthToken, error) {
+	if t.tokensFunc == nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Authentication tokens aren't supported")
+	}
+
+	token, err := t.tokensFunc(ctx, details.username())
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, api.StatusErrorf(http.StatusForbidden, "Authentication token has been revoked")
+		}
+
+		return nil, fmt.Errorf("Failed loading authentication token: %w", err)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Authentication token has expired")
+	}
+
+	return token, nil
+}
+
+// CheckPermission returns an error if the user does not have the given Entitlement on the given Object.
+func (t *tokenAuthorizer) CheckPermission(ctx context.Context, r *http.Request, object Object, entitlement Entitlement) error {
+	details, err := t.common.requestDetails(r)
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.authenticationProtocol() != api.AuthenticationMethodToken {
+		return t.Authorizer.CheckPermission(ctx, r, object, entitlement)
+	}
+
+	token, err := t.token(ctx, details)
+	if err != nil {
+		return err
+	}
+
+	if !tokenAllowed(token, object, entitlement) {
+		return api.StatusErrorf(http.StatusForbidden, "Authentication token does not grant %q on %q", entitlement, object)
+	}
+
+	return nil
+}
+
+// GetPermissionChecker returns a function that can be used to check whether a user has the required entitlement on an authorization object.
+func (t *tokenAuthorizer) GetPermissionChecker(ctx context.Context, r *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	details, err := t.common.requestDetails(r)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.authenticationProtocol() != api.AuthenticationMethodToken {
+		return t.Authorizer.GetPermissionChecker(ctx, r, entitlement, objectType)
+	}
+
+	token, err := t.token(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(object Object) bool {
+		return tokenAllowed(token, object, entitlement)
+	}, nil
+}
+
+// tokenAllowed returns whether the authentication token grants the entitlement on the object.
+func tokenAllowed(token *api.AuthToken, object Object, entitlement Entitlement) bool {
+	// Allow basic access to the server so that clients can connect.
+	if object.Type() == ObjectTypeServer {
+		return entitlement == EntitlementCanView
+	}
+
+	// Allow read-only access to the resources inherited from the default project.
+	if object.Project() == api.ProjectDefaultName && entitlement == EntitlementCanView && slices.Contains(rbacInheritedObjectTypes, object.Type()) {
+		return true
+	}
+
+	if object.Project() != token.Project || slices.Contains([]ObjectType{ObjectTypeUser, ObjectTypeCertificate, ObjectTypeStoragePool, ObjectTypeNetworkIntegration}, object.Type()) {
+		return false
+	}
+
+	return slices.Contains(token.Entitlements, string(entitlement))
+}
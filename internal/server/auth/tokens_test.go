@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestTokenAllowed(t *testing.T) {
+	token := &api.AuthToken{Project: "frontend", Entitlements: []string{"can_view", "can_exec"}}
+
+	tests := []struct {
+		name        string
+		object      Object
+		entitlement Entitlement
+		allowed     bool
+	}{
+		{"Server view", ObjectServer(), EntitlementCanView, true},
+		{"Server edit", ObjectServer(), EntitlementCanEdit, false},
+		{"Project view", ObjectProject("frontend"), EntitlementCanView, true},
+		{"Project edit", ObjectProject("frontend"), EntitlementCanEdit, false},
+		{"Instance exec", ObjectInstance("frontend", "c1"), EntitlementCanExec, true},
+		{"Instance state", ObjectInstance("frontend", "c1"), EntitlementCanUpdateState, false},
+		{"Other project instance", ObjectInstance("backend", "c1"), EntitlementCanExec, false},
+		{"Inherited profile", ObjectProfile(api.ProjectDefaultName, "default"), EntitlementCanView, true},
+		{"Inherited profile edit", ObjectProfile(api.ProjectDefaultName, "default"), EntitlementCanEdit, false},
+		{"Storage pool", ObjectStoragePool("default"), EntitlementCanView, false},
+		{"Certificate", ObjectCertificate("abcdef"), EntitlementCanView, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tokenAllowed(token, tt.object, tt.entitlement))
+		})
+	}
+}
+
+func TestValidateTokenEntitlements(t *testing.T) {
+	assert.NoError(t, ValidateTokenEntitlements([]string{"can_view", "can_exec"}))
+	assert.Error(t, ValidateTokenEntitlements(nil))
+	assert.Error(t, ValidateTokenEntitlements([]string{"can_view", "can_create_projects"}))
+	assert.Error(t, ValidateTokenEntitlements([]string{"admin"}))
+}
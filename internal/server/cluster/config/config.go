@@ -10,9 +10,11 @@ import (
 	"github.com/sirupsen/logrus"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/audit"
 	"github.com/lxc/incus/v6/internal/server/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
 
@@ -80,6 +82,18 @@ func (c *Config) TrustCACertificates() bool {
 	return c.m.GetBool("core.trust_ca_certificates")
 }
 
+// TrustCACRLURL returns the URL from which to periodically fetch the CA
+// certificate revocation list, if any.
+func (c *Config) TrustCACRLURL() string {
+	return c.m.GetString("core.trust_ca_crl_url")
+}
+
+// TrustCAOCSP returns whether certificates signed by the CA are checked
+// against their OCSP responders.
+func (c *Config) TrustCAOCSP() bool {
+	return c.m.GetBool("core.trust_ca_ocsp")
+}
+
 // ProxyHTTPS returns the configured HTTPS proxy, if any.
 func (c *Config) ProxyHTTPS() string {
 	return c.m.GetString("core.proxy_https")
@@ -140,6 +154,21 @@ func (c *Config) ClusterRebalanceInterval() int64 {
 	return c.m.GetInt64("cluster.rebalance.interval")
 }
 
+// ClusterRebalanceScriptlet returns the cluster re-balancing scriptlet source code.
+func (c *Config) ClusterRebalanceScriptlet() string {
+	return c.m.GetString("cluster.rebalance.scriptlet")
+}
+
+// ClusterRebalanceWeights returns the weight of each component of the re-balancing score.
+func (c *Config) ClusterRebalanceWeights() map[string]int64 {
+	weights := map[string]int64{}
+	for _, name := range []string{"cpu", "memory", "storage", "pressure", "network"} {
+		weights[name] = c.m.GetInt64("cluster.rebalance.weight." + name)
+	}
+
+	return weights
+}
+
 // ClusterRebalanceThreshold returns load difference between most and least busy server
 // needed to trigger a migration.
 func (c *Config) ClusterRebalanceThreshold() int64 {
@@ -208,6 +237,31 @@ func (c *Config) AuthorizationScriptlet() string {
 	return c.m.GetString("authorization.scriptlet")
 }
 
+// AdmissionScriptlet returns the admission scriptlet source code.
+func (c *Config) AdmissionScriptlet() string {
+	return c.m.GetString("admission.scriptlet")
+}
+
+// AuditSyslog returns the syslog server to forward the audit log to.
+func (c *Config) AuditSyslog() string {
+	return c.m.GetString("audit.syslog")
+}
+
+// AuthorizationRBAC returns whether the built-in role based authorization is enabled.
+func (c *Config) AuthorizationRBAC() bool {
+	return c.m.GetBool("authorization.rbac")
+}
+
+// ProjectsTemplatesSelfService returns the project templates that can be used for self-service project creation.
+func (c *Config) ProjectsTemplatesSelfService() []string {
+	return util.SplitNTrimSpace(c.m.GetString("projects.templates.self_service"), ",", -1, true)
+}
+
+// ProjectsTemplatesSelfServiceLimit returns the maximum number of projects an identity can create from self-service templates.
+func (c *Config) ProjectsTemplatesSelfServiceLimit() int64 {
+	return c.m.GetInt64("projects.templates.self_service_limit")
+}
+
 // InstancesLXCFSPerInstance returns whether LXCFS should be run on a per-instance basis.
 func (c *Config) InstancesLXCFSPerInstance() bool {
 	return c.m.GetBool("instances.lxcfs.per_instance")
@@ -229,6 +283,18 @@ func (c *Config) LokiServer() (string, string, string, string, string, string, [
 	return c.m.GetString("loki.api.url"), c.m.GetString("loki.auth.username"), c.m.GetString("loki.auth.password"), c.m.GetString("loki.api.ca_cert"), c.m.GetString("loki.instance"), c.m.GetString("loki.loglevel"), labels, types
 }
 
+// OTLPServer returns all the OpenTelemetry settings needed to export traces.
+func (c *Config) OTLPServer() (string, map[string]string, string) {
+	headers := map[string]string{}
+
+	for _, header := range util.SplitNTrimSpace(c.m.GetString("otlp.headers"), ",", -1, true) {
+		key, value, _ := strings.Cut(header, "=")
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return c.m.GetString("otlp.endpoint"), headers, c.m.GetString("otlp.ca_cert")
+}
+
 // ACME returns all ACME settings needed for certificate renewal.
 func (c *Config) ACME() (string, string, string, bool, string) {
 	return c.m.GetString("acme.domain"), c.m.GetString("acme.email"), c.m.GetString("acme.ca_url"), c.m.GetBool("acme.agree_tos"), c.m.GetString("acme.challenge")
@@ -278,9 +344,14 @@ func (c *Config) RemoteTokenExpiry() string {
 	return c.m.GetString("core.remote_token_expiry")
 }
 
+// TrustExpiryWarningDays returns the number of days before the expiry of a trusted client certificate at which to warn about it.
+func (c *Config) TrustExpiryWarningDays() int64 {
+	return c.m.GetInt64("core.trust_expiry_warning")
+}
+
 // OIDCServer returns all the OpenID Connect settings needed to connect to a server.
-func (c *Config) OIDCServer() (string, string, string, string, string) {
-	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim")
+func (c *Config) OIDCServer() (string, string, string, string, string, string) {
+	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim"), c.m.GetString("oidc.groups.claim")
 }
 
 // ClusterHealingThreshold returns the configured healing threshold, i.e. the
@@ -419,6 +490,34 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Comma-separated list of DNS resolvers (used by DNS-01)
 	"acme.provider.resolvers": {Type: config.String, Default: ""},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=admission.scriptlet)
+	// This scriptlet is run on the creation and update of instances, profiles, custom storage volumes and networks.
+	// See {ref}`authorization-admission-scriptlet`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Admission scriptlet
+	"admission.scriptlet": {Validator: validate.Optional(scriptletLoad.AdmissionValidate)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=audit.syslog)
+	// Set to `local` to use the local syslog daemon, or to a URL like `udp://<host>:<port>` or `tcp://<host>:<port>`.
+	// See {ref}`audit-log`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Syslog server to forward the audit log to
+	"audit.syslog": {Validator: validate.Optional(audit.ValidateSyslogTarget)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.rbac)
+	// When enabled, access is granted through the roles of the groups managed under `/1.0/auth/groups`.
+	// See {ref}`authorization-rbac`.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to use the built-in role based authorization
+	"authorization.rbac": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.scriptlet)
 	// When using scriptlet-based authorization, this option stores the scriptlet.
 	// ---
@@ -521,6 +620,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: How often (in minutes) to consider re-balancing things. 0 to disable (default)
 	"cluster.rebalance.interval": {Type: config.Int64, Default: "0"},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.scriptlet)
+	// When set, the scriptlet is used to score cluster members instead of the weighted resource usage.
+	// See {ref}`cluster-rebalance-scoring` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Scriptlet used to score cluster members during re-balancing
+	"cluster.rebalance.scriptlet": {Validator: validate.Optional(scriptletLoad.ClusterRebalanceValidate)},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.threshold)
 	//
 	// ---
@@ -530,6 +638,51 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Percentage load difference between most and least busy server needed to trigger a migration
 	"cluster.rebalance.threshold": {Type: config.Int64, Default: "20", Validator: validate.Optional(rebalanceThresholdValidator)},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.cpu)
+	//
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `1`
+	//  shortdesc: Weight of the CPU load in the re-balancing score
+	"cluster.rebalance.weight.cpu": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsUint32)},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.memory)
+	//
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `1`
+	//  shortdesc: Weight of the memory usage in the re-balancing score
+	"cluster.rebalance.weight.memory": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsUint32)},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.storage)
+	//
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Weight of the storage pool usage in the re-balancing score
+	"cluster.rebalance.weight.storage": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsUint32)},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.pressure)
+	//
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Weight of the pressure stall information in the re-balancing score
+	"cluster.rebalance.weight.pressure": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsUint32)},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.network)
+	//
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Weight of the network throughput in the re-balancing score
+	"cluster.rebalance.weight.network": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsUint32)},
+
 	// gendoc:generate(entity=server, group=core, key=core.metrics_authentication)
 	//
 	// ---
@@ -641,6 +794,35 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to automatically trust clients signed by the CA
 	"core.trust_ca_certificates": {Type: config.Bool, Default: "false"},
 
+	// gendoc:generate(entity=server, group=core, key=core.trust_ca_crl_url)
+	// The certificate revocation list is fetched hourly and whenever this option changes.
+	// It must be signed by the CA. If it can't be retrieved, the previously fetched list remains in use.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL of the CA certificate revocation list
+	"core.trust_ca_crl_url": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// gendoc:generate(entity=server, group=core, key=core.trust_ca_ocsp)
+	// When enabled, the OCSP responders listed in certificates signed by the CA are queried and their answers cached.
+	// Certificates are only rejected when reported as revoked; unreachable responders don't block access.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to check certificates signed by the CA through OCSP
+	"core.trust_ca_ocsp": {Type: config.Bool, Default: "false"},
+
+	// gendoc:generate(entity=server, group=core, key=core.trust_expiry_warning)
+	// Specify the number of days before the expiry of a trusted client certificate at which to raise a warning.
+	// To disable the warnings, set this option to `0`.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `30`
+	//  shortdesc: When to warn about expiring client certificates
+	"core.trust_expiry_warning": {Type: config.Int64, Default: "30"},
+
 	// gendoc:generate(entity=server, group=images, key=images.auto_update_cached)
 	//
 	// ---
@@ -723,6 +905,26 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Instance placement scriptlet for automatic instance placement
 	"instances.placement.scriptlet": {Validator: validate.Optional(scriptletLoad.InstancePlacementValidate)},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=projects.templates.self_service)
+	// Specify a comma-separated list of project templates.
+	// Users that aren't allowed to create projects can create projects from those templates and are granted the `admin` role on the new projects.
+	// See {ref}`projects-templates`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Project templates available for self-service project creation
+	"projects.templates.self_service": {},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=projects.templates.self_service_limit)
+	// Specify the maximum number of existing projects an identity can have created from self-service templates.
+	// To remove the limit, set this option to `0`.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `5`
+	//  shortdesc: Maximum number of self-service projects per identity
+	"projects.templates.self_service_limit": {Type: config.Int64, Default: "5"},
+
 	// gendoc:generate(entity=server, group=loki, key=loki.auth.username)
 	//
 	// ---
@@ -783,13 +985,13 @@ var ConfigSchema = config.Schema{
 
 	// gendoc:generate(entity=server, group=loki, key=loki.types)
 	// Specify a comma-separated list of events to send to the Loki server.
-	// The events can be any combination of `lifecycle`, `logging`, and `network-acl`.
+	// The events can be any combination of `audit`, `lifecycle`, `logging`, and `network-acl`.
 	// ---
 	//  type: string
 	//  scope: global
 	//  defaultdesc: `lifecycle,logging`
 	//  shortdesc: Events to send to the Loki server
-	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging"},
+	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("audit", "lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging"},
 
 	// gendoc:generate(entity=server, group=openfga, key=openfga.api.token)
 	//
@@ -815,6 +1017,30 @@ var ConfigSchema = config.Schema{
 	// shortdesc: ID of the OpenFGA permission store
 	"openfga.store.id": {},
 
+	// gendoc:generate(entity=server, group=otlp, key=otlp.ca_cert)
+	//
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: CA certificate for the OTLP endpoint
+	"otlp.ca_cert": {},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.endpoint)
+	// Specify the protocol, name or IP and port of the OTLP/HTTP endpoint. For example `https://otel.example.com:4318`. Incus will automatically add the `/v1/traces` suffix so there's no need to add it here.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: URL of the OpenTelemetry collector
+	"otlp.endpoint": {Validator: validate.Optional(validate.IsRequestURL)},
+
+	// gendoc:generate(entity=server, group=otlp, key=otlp.headers)
+	// Specify a comma-separated list of `key=value` pairs. For example `Authorization=Bearer 1234,X-Scope-OrgID=incus`.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: HTTP headers to send to the OTLP endpoint
+	"otlp.headers": {Validator: validate.Optional(otlpHeadersValidator)},
+
 	// gendoc:generate(entity=server, group=oidc, key=oidc.client.id)
 	//
 	// ---
@@ -855,6 +1081,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OpenID Connect claim to use as the username
 	"oidc.claim": {},
 
+	// gendoc:generate(entity=server, group=oidc, key=oidc.groups.claim)
+	// The claim can either be a list of strings or a comma-separated string.
+	// When set, OIDC users only get the roles of the authorization groups mapped to their identity provider groups.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: OpenID Connect claim containing the groups of the user
+	"oidc.groups.claim": {},
+
 	// OVN networking global keys.
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=network.ovn.integration_bridge)
@@ -985,6 +1220,17 @@ func maxStandByValidator(value string) error {
 	return nil
 }
 
+func otlpHeadersValidator(value string) error {
+	for _, header := range util.SplitNTrimSpace(value, ",", -1, true) {
+		key, _, ok := strings.Cut(header, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("Invalid header %q, expected key=value", header)
+		}
+	}
+
+	return nil
+}
+
 func rebalanceThresholdValidator(value string) error {
 	n, err := strconv.Atoi(value)
 	if err != nil {
@@ -3,6 +3,7 @@ package cluster
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/proxy"
@@ -70,8 +72,24 @@ func Connect(address string, networkCert *localtls.CertInfo, serverCert *localtl
 				req.Header.Add(request.HeaderForwardedProtocol, val)
 			}
 
+			groups, ok := ctx.Value(request.CtxIdentityProviderGroups).([]string)
+			if ok {
+				groupsJSON, err := json.Marshal(groups)
+				if err == nil {
+					req.Header.Add(request.HeaderForwardedIdentityProviderGroups, string(groupsJSON))
+				}
+			}
+
 			req.Header.Add(request.HeaderForwardedAddress, r.RemoteAddr)
 
+			// Propagate the trace context, preferring any span started for this specific request.
+			traceCtx := req.Context()
+			if !tracing.HasSpan(traceCtx) {
+				traceCtx = ctx
+			}
+
+			tracing.Inject(traceCtx, req.Header)
+
 			return proxy.FromEnvironment(req)
 		}
 
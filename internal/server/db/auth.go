@@ -0,0 +1,435 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetAuthGroups returns all the authorization groups.
+func (c *ClusterTx) GetAuthGroups(ctx context.Context) ([]api.AuthGroup, error) {
+	groups := []api.AuthGroup{}
+	groupIndex := map[int64]int{}
+
+	err := query.Scan(ctx, c.tx, "SELECT id, name, description FROM auth_groups ORDER BY name", func(scan func(dest ...any) error) error {
+		var id int64
+		group := api.AuthGroup{
+			AuthGroupPut: api.AuthGroupPut{
+				Roles:                  []api.AuthGroupRole{},
+				IdentityProviderGroups: []string{},
+			},
+			Identities: map[string][]string{},
+		}
+
+		err := scan(&id, &group.Name, &group.Description)
+		if err != nil {
+			return err
+		}
+
+		groupIndex[id] = len(groups)
+		groups = append(groups, group)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = authGroupsFill(ctx, c, groups, groupIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetAuthGroup returns the authorization group with the given name.
+func (c *ClusterTx) GetAuthGroup(ctx context.Context, name string) (*api.AuthGroup, error) {
+	var id int64
+	group := api.AuthGroup{
+		AuthGroupPut: api.AuthGroupPut{
+			Roles:                  []api.AuthGroupRole{},
+			IdentityProviderGroups: []string{},
+		},
+		Identities: map[string][]string{},
+	}
+
+	err := c.tx.QueryRowContext(ctx, "SELECT id, name, description FROM auth_groups WHERE name=?", name).Scan(&id, &group.Name, &group.Description)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Authorization group not found")
+		}
+
+		return nil, err
+	}
+
+	groups := []api.AuthGroup{group}
+
+	err = authGroupsFill(ctx, c, groups, map[int64]int{id: 0})
+	if err != nil {
+		return nil, err
+	}
+
+	return &groups[0], nil
+}
+
+// authGroupsFill populates the roles, identity provider groups and members of the given groups.
+func authGroupsFill(ctx context.Context, tx *ClusterTx, groups []api.AuthGroup, groupIndex map[int64]int) error {
+	q := `
+		SELECT auth_groups_roles.auth_group_id, auth_groups_roles.role, IFNULL(projects.name, '')
+		FROM auth_groups_roles
+		LEFT JOIN projects ON projects.id=auth_groups_roles.project_id
+		ORDER BY auth_groups_roles.id
+	`
+
+	err := query.Scan(ctx, tx.tx, q, func(scan func(dest ...any) error) error {
+		var id int64
+		var role api.AuthGroupRole
+
+		err := scan(&id, &role.Role, &role.Project)
+		if err != nil {
+			return err
+		}
+
+		i, ok := groupIndex[id]
+		if ok {
+			groups[i].Roles = append(groups[i].Roles, role)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading group roles: %w", err)
+	}
+
+	err = query.Scan(ctx, tx.tx, "SELECT auth_group_id, name FROM auth_groups_identity_provider_groups ORDER BY name", func(scan func(dest ...any) error) error {
+		var id int64
+		var name string
+
+		err := scan(&id, &name)
+		if err != nil {
+			return err
+		}
+
+		i, ok := groupIndex[id]
+		if ok {
+			groups[i].IdentityProviderGroups = append(groups[i].IdentityProviderGroups, name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading identity provider groups: %w", err)
+	}
+
+	q = `
+		SELECT auth_identities_groups.auth_group_id, auth_identities.authentication_method, auth_identities.identifier
+		FROM auth_identities_groups
+		JOIN auth_identities ON auth_identities.id=auth_identities_groups.auth_identity_id
+		ORDER BY auth_identities.identifier
+	`
+
+	err = query.Scan(ctx, tx.tx, q, func(scan func(dest ...any) error) error {
+		var id int64
+		var method string
+		var identifier string
+
+		err := scan(&id, &method, &identifier)
+		if err != nil {
+			return err
+		}
+
+		i, ok := groupIndex[id]
+		if ok {
+			groups[i].Identities[method] = append(groups[i].Identities[method], identifier)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading group members: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAuthGroup creates a new authorization group.
+func (c *ClusterTx) CreateAuthGroup(ctx context.Context, info api.AuthGroupsPost) error {
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO auth_groups (name, description) VALUES (?, ?)", info.Name, info.Description)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return authGroupSet(ctx, c, id, info.AuthGroupPut)
+}
+
+// UpdateAuthGroup updates the authorization group with the given name.
+func (c *ClusterTx) UpdateAuthGroup(ctx context.Context, name string, info api.AuthGroupPut) error {
+	id, err := authGroupID(ctx, c, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE auth_groups SET description=? WHERE id=?", info.Description, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_groups_roles WHERE auth_group_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_groups_identity_provider_groups WHERE auth_group_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	return authGroupSet(ctx, c, id, info)
+}
+
+// authGroupSet inserts the roles and identity provider groups of an authorization group.
+func authGroupSet(ctx context.Context, tx *ClusterTx, id int64, info api.AuthGroupPut) error {
+	for _, role := range info.Roles {
+		var projectID any
+		if role.Project != "" {
+			var pID int64
+
+			err := tx.tx.QueryRowContext(ctx, "SELECT id FROM projects WHERE name=?", role.Project).Scan(&pID)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return api.StatusErrorf(http.StatusNotFound, "Project %q not found", role.Project)
+				}
+
+				return err
+			}
+
+			projectID = pID
+		}
+
+		_, err := tx.tx.ExecContext(ctx, "INSERT INTO auth_groups_roles (auth_group_id, role, project_id) VALUES (?, ?, ?)", id, role.Role, projectID)
+		if err != nil {
+			return fmt.Errorf("Failed inserting role %q: %w", role.Role, err)
+		}
+	}
+
+	for _, name := range info.IdentityProviderGroups {
+		_, err := tx.tx.ExecContext(ctx, "INSERT INTO auth_groups_identity_provider_groups (auth_group_id, name) VALUES (?, ?)", id, name)
+		if err != nil {
+			return fmt.Errorf("Failed inserting identity provider group %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// RenameAuthGroup renames the authorization group with the given name.
+func (c *ClusterTx) RenameAuthGroup(ctx context.Context, name string, newName string) error {
+	id, err := authGroupID(ctx, c, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE auth_groups SET name=? WHERE id=?", newName, id)
+
+	return err
+}
+
+// DeleteAuthGroup deletes the authorization group with the given name.
+func (c *ClusterTx) DeleteAuthGroup(ctx context.Context, name string) error {
+	id, err := authGroupID(ctx, c, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_groups WHERE id=?", id)
+
+	return err
+}
+
+// authGroupID returns the ID of the authorization group with the given name.
+func authGroupID(ctx context.Context, tx *ClusterTx, name string) (int64, error) {
+	var id int64
+
+	err := tx.tx.QueryRowContext(ctx, "SELECT id FROM auth_groups WHERE name=?", name).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return -1, api.StatusErrorf(http.StatusNotFound, "Authorization group %q not found", name)
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// GetAuthIdentities returns all the identities.
+func (c *ClusterTx) GetAuthIdentities(ctx context.Context) ([]api.AuthIdentity, error) {
+	identities := []api.AuthIdentity{}
+	identityIndex := map[int64]int{}
+
+	err := query.Scan(ctx, c.tx, "SELECT id, authentication_method, identifier, name FROM auth_identities ORDER BY authentication_method, identifier", func(scan func(dest ...any) error) error {
+		var id int64
+		identity := api.AuthIdentity{AuthIdentityPut: api.AuthIdentityPut{Groups: []string{}}}
+
+		err := scan(&id, &identity.AuthenticationMethod, &identity.Identifier, &identity.Name)
+		if err != nil {
+			return err
+		}
+
+		identityIndex[id] = len(identities)
+		identities = append(identities, identity)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+		SELECT auth_identities_groups.auth_identity_id, auth_groups.name
+		FROM auth_identities_groups
+		JOIN auth_groups ON auth_groups.id=auth_identities_groups.auth_group_id
+		ORDER BY auth_groups.name
+	`
+
+	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var id int64
+		var group string
+
+		err := scan(&id, &group)
+		if err != nil {
+			return err
+		}
+
+		i, ok := identityIndex[id]
+		if ok {
+			identities[i].Groups = append(identities[i].Groups, group)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading identity groups: %w", err)
+	}
+
+	return identities, nil
+}
+
+// GetAuthIdentity returns the identity with the given authentication method and identifier.
+func (c *ClusterTx) GetAuthIdentity(ctx context.Context, authenticationMethod string, identifier string) (*api.AuthIdentity, error) {
+	identities, err := c.GetAuthIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := slices.IndexFunc(identities, func(identity api.AuthIdentity) bool {
+		return identity.AuthenticationMethod == authenticationMethod && identity.Identifier == identifier
+	})
+
+	if idx < 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Identity not found")
+	}
+
+	return &identities[idx], nil
+}
+
+// CreateAuthIdentity creates a new identity.
+func (c *ClusterTx) CreateAuthIdentity(ctx context.Context, info api.AuthIdentitiesPost) error {
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO auth_identities (authentication_method, identifier, name) VALUES (?, ?, ?)", info.AuthenticationMethod, info.Identifier, info.Name)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return authIdentityGroupsSet(ctx, c, id, info.Groups)
+}
+
+// UpdateAuthIdentity updates the identity with the given authentication method and identifier.
+func (c *ClusterTx) UpdateAuthIdentity(ctx context.Context, authenticationMethod string, identifier string, info api.AuthIdentityPut) error {
+	id, err := authIdentityID(ctx, c, authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE auth_identities SET name=? WHERE id=?", info.Name, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_identities_groups WHERE auth_identity_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	return authIdentityGroupsSet(ctx, c, id, info.Groups)
+}
+
+// authIdentityGroupsSet adds the identity to the given authorization groups.
+func authIdentityGroupsSet(ctx context.Context, tx *ClusterTx, id int64, groups []string) error {
+	for _, group := range groups {
+		groupID, err := authGroupID(ctx, tx, group)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.tx.ExecContext(ctx, "INSERT INTO auth_identities_groups (auth_identity_id, auth_group_id) VALUES (?, ?)", id, groupID)
+		if err != nil {
+			return fmt.Errorf("Failed adding identity to group %q: %w", group, err)
+		}
+	}
+
+	return nil
+}
+
+// RenameAuthIdentity changes the identifier of the identity with the given authentication method and identifier.
+// The identity keeps its name and groups. Nothing is done if no such identity exists.
+func (c *ClusterTx) RenameAuthIdentity(ctx context.Context, authenticationMethod string, identifier string, newIdentifier string) error {
+	_, err := c.tx.ExecContext(ctx, "UPDATE auth_identities SET identifier=? WHERE authentication_method=? AND identifier=?", newIdentifier, authenticationMethod, identifier)
+
+	return err
+}
+
+// DeleteAuthIdentity deletes the identity with the given authentication method and identifier.
+func (c *ClusterTx) DeleteAuthIdentity(ctx context.Context, authenticationMethod string, identifier string) error {
+	id, err := authIdentityID(ctx, c, authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "DELETE FROM auth_identities WHERE id=?", id)
+
+	return err
+}
+
+// authIdentityID returns the ID of the identity with the given authentication method and identifier.
+func authIdentityID(ctx context.Context, tx *ClusterTx, authenticationMethod string, identifier string) (int64, error) {
+	var id int64
+
+	err := tx.tx.QueryRowContext(ctx, "SELECT id FROM auth_identities WHERE authentication_method=? AND identifier=?", authenticationMethod, identifier).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return -1, api.StatusErrorf(http.StatusNotFound, "Identity not found")
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,201 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetAuthTokens returns all the authentication tokens, optionally restricted to a project.
+func (c *ClusterTx) GetAuthTokens(ctx context.Context, projectName *string) ([]api.AuthToken, error) {
+	tokens := []api.AuthToken{}
+	tokenIndex := map[int64]int{}
+
+	q := `
+		SELECT auth_tokens.id, auth_tokens.uuid, auth_tokens.description, projects.name, auth_tokens.creation_date, auth_tokens.expiry_date
+		FROM auth_tokens
+		JOIN projects ON projects.id=auth_tokens.project_id
+	`
+
+	args := []any{}
+	if projectName != nil {
+		q += "WHERE projects.name=?\n"
+		args = append(args, *projectName)
+	}
+
+	q += "ORDER BY auth_tokens.creation_date"
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var id int64
+		token := api.AuthToken{Entitlements: []string{}}
+
+		err := scan(&id, &token.ID, &token.Description, &token.Project, &token.CreatedAt, &token.ExpiresAt)
+		if err != nil {
+			return err
+		}
+
+		tokenIndex[id] = len(tokens)
+		tokens = append(tokens, token)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authTokensFill(ctx, c, tokens, tokenIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetAuthToken returns the authentication token with the given UUID.
+func (c *ClusterTx) GetAuthToken(ctx context.Context, uuid string) (*api.AuthToken, error) {
+	var id int64
+	token := api.AuthToken{Entitlements: []string{}}
+
+	q := `
+		SELECT auth_tokens.id, auth_tokens.uuid, auth_tokens.description, projects.name, auth_tokens.creation_date, auth_tokens.expiry_date
+		FROM auth_tokens
+		JOIN projects ON projects.id=auth_tokens.project_id
+		WHERE auth_tokens.uuid=?
+	`
+
+	err := c.tx.QueryRowContext(ctx, q, uuid).Scan(&id, &token.ID, &token.Description, &token.Project, &token.CreatedAt, &token.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Authentication token not found")
+		}
+
+		return nil, err
+	}
+
+	tokens := []api.AuthToken{token}
+
+	err = authTokensFill(ctx, c, tokens, map[int64]int{id: 0})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tokens[0], nil
+}
+
+// authTokensFill populates the entitlements of the given tokens.
+func authTokensFill(ctx context.Context, tx *ClusterTx, tokens []api.AuthToken, tokenIndex map[int64]int) error {
+	err := query.Scan(ctx, tx.tx, "SELECT auth_token_id, entitlement FROM auth_tokens_entitlements ORDER BY entitlement", func(scan func(dest ...any) error) error {
+		var id int64
+		var entitlement string
+
+		err := scan(&id, &entitlement)
+		if err != nil {
+			return err
+		}
+
+		i, ok := tokenIndex[id]
+		if ok {
+			tokens[i].Entitlements = append(tokens[i].Entitlements, entitlement)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading token entitlements: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAuthToken adds a new authentication token.
+func (c *ClusterTx) CreateAuthToken(ctx context.Context, token api.AuthToken) error {
+	var projectID int64
+
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM projects WHERE name=?", token.Project).Scan(&projectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return api.StatusErrorf(http.StatusNotFound, "Project %q not found", token.Project)
+		}
+
+		return err
+	}
+
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO auth_tokens (uuid, description, project_id, creation_date, expiry_date) VALUES (?, ?, ?, ?, ?)", token.ID, token.Description, projectID, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, entitlement := range token.Entitlements {
+		_, err := c.tx.ExecContext(ctx, "INSERT INTO auth_tokens_entitlements (auth_token_id, entitlement) VALUES (?, ?)", id, entitlement)
+		if err != nil {
+			return fmt.Errorf("Failed inserting entitlement %q: %w", entitlement, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteAuthToken deletes the authentication token with the given UUID.
+func (c *ClusterTx) DeleteAuthToken(ctx context.Context, uuid string) error {
+	result, err := c.tx.ExecContext(ctx, "DELETE FROM auth_tokens WHERE uuid=?", uuid)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Authentication token not found")
+	}
+
+	return nil
+}
+
+// DeleteExpiredAuthTokens deletes the authentication tokens which expired before the given time.
+// It returns the number of deleted tokens.
+func (c *ClusterTx) DeleteExpiredAuthTokens(ctx context.Context, now time.Time) (int, error) {
+	ids := []int64{}
+
+	err := query.Scan(ctx, c.tx, "SELECT id, expiry_date FROM auth_tokens", func(scan func(dest ...any) error) error {
+		var id int64
+		var expiry time.Time
+
+		err := scan(&id, &expiry)
+		if err != nil {
+			return err
+		}
+
+		if expiry.Before(now) {
+			ids = append(ids, id)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	for _, id := range ids {
+		_, err := c.tx.ExecContext(ctx, "DELETE FROM auth_tokens WHERE id=?", id)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	return len(ids), nil
+}
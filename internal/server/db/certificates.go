@@ -8,6 +8,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/certificate"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
 )
 
 // UpdateCertificate updates a certificate in the db.
@@ -29,6 +30,26 @@ func (db *DB) UpdateCertificate(ctx context.Context, fingerprint string, cert cl
 	return err
 }
 
+// RenewCertificate replaces a certificate by a renewed one.
+// The renewed certificate keeps the name, type, restrictions and projects of the replaced one, as well as its
+// authorization identity.
+func (c *ClusterTx) RenewCertificate(ctx context.Context, fingerprint string, newFingerprint string, newCertificate string) error {
+	cert, err := cluster.GetCertificate(ctx, c.tx, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	cert.Fingerprint = newFingerprint
+	cert.Certificate = newCertificate
+
+	err = cluster.UpdateCertificate(ctx, c.tx, fingerprint, *cert)
+	if err != nil {
+		return err
+	}
+
+	return c.RenameAuthIdentity(ctx, api.AuthenticationMethodTLS, fingerprint, newFingerprint)
+}
+
 // GetCertificates returns all available local certificates.
 func (n *NodeTx) GetCertificates(ctx context.Context) ([]cluster.Certificate, error) {
 	type cert struct {
@@ -4,6 +4,7 @@ package db_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
 )
 
 func TestGetCertificate(t *testing.T) {
@@ -25,3 +27,36 @@ func TestGetCertificate(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, cert.Fingerprint, "foobar")
 }
+
+func TestRenewCertificate(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	id, err := cluster.CreateCertificateWithProjects(ctx, tx.Tx(), cluster.Certificate{Fingerprint: "foo", Name: "client", Restricted: true}, []string{"default"})
+	require.NoError(t, err)
+
+	err = tx.CreateAuthIdentity(ctx, api.AuthIdentitiesPost{AuthenticationMethod: api.AuthenticationMethodTLS, Identifier: "foo"})
+	require.NoError(t, err)
+
+	err = tx.RenewCertificate(ctx, "foo", "bar", "new certificate")
+	require.NoError(t, err)
+
+	_, err = cluster.GetCertificate(ctx, tx.Tx(), "foo")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	cert, err := cluster.GetCertificate(ctx, tx.Tx(), "bar")
+	require.NoError(t, err)
+	assert.Equal(t, int(id), cert.ID)
+	assert.Equal(t, "client", cert.Name)
+	assert.Equal(t, "new certificate", cert.Certificate)
+	assert.True(t, cert.Restricted)
+
+	projects, err := cluster.GetCertificateProjects(ctx, tx.Tx(), cert.ID)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "default", projects[0].Name)
+
+	_, err = tx.GetAuthIdentity(ctx, api.AuthenticationMethodTLS, "bar")
+	require.NoError(t, err)
+}
@@ -6,6 +6,59 @@ package cluster
 // modify the database schema, please add a new schema update to update.go
 // and the run 'make update-schema'.
 const freshSchema = `
+CREATE TABLE auth_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    UNIQUE (name)
+);
+CREATE TABLE auth_groups_identity_provider_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (auth_group_id, name),
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_groups_roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    role TEXT NOT NULL,
+    project_id INTEGER,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX auth_groups_roles_unique_auth_group_id_role_project_id ON auth_groups_roles (auth_group_id, role, IFNULL(project_id, -1));
+CREATE TABLE auth_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    UNIQUE (authentication_method, identifier)
+);
+CREATE TABLE auth_identities_groups (
+    auth_identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    UNIQUE (auth_identity_id, auth_group_id),
+    FOREIGN KEY (auth_identity_id) REFERENCES auth_identities (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    project_id INTEGER NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    UNIQUE (uuid),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_tokens_entitlements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_token_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    UNIQUE (auth_token_id, entitlement),
+    FOREIGN KEY (auth_token_id) REFERENCES auth_tokens (id) ON DELETE CASCADE
+);
 CREATE TABLE certificates (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -452,6 +505,16 @@ CREATE TABLE "profiles_devices_config" (
     FOREIGN KEY (profile_device_id) REFERENCES "profiles_devices" (id) ON DELETE CASCADE
 );
 CREATE INDEX profiles_project_id_idx ON profiles (project_id);
+CREATE TABLE project_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    config TEXT NOT NULL,
+    default_profile TEXT NOT NULL,
+    networks TEXT NOT NULL,
+    network_acls TEXT NOT NULL,
+    UNIQUE (name)
+);
 CREATE TABLE "projects" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -466,6 +529,26 @@ CREATE TABLE "projects_config" (
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
     UNIQUE (project_id, key)
 );
+CREATE TABLE projects_owners (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    auth_identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER,
+    UNIQUE (project_id),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_identity_id) REFERENCES auth_identities (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE SET NULL
+);
+CREATE TABLE projects_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    type TEXT NOT NULL,
+    pool TEXT NOT NULL DEFAULT '',
+    value REAL NOT NULL DEFAULT 0,
+    UNIQUE (project_id, date, type, pool),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
 CREATE TABLE "storage_buckets" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -656,5 +739,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (75, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (80, strftime("%s"))
 `
@@ -112,6 +112,160 @@ var updates = map[int]schema.Update{
 	73: updateFromV72,
 	74: updateFromV73,
 	75: updateFromV74,
+	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+}
+
+// updateFromV79 adds the table tracking the owners of self-service projects.
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE projects_owners (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    auth_identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER,
+    UNIQUE (project_id),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_identity_id) REFERENCES auth_identities (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE SET NULL
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating project owners table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV78 adds the table used for project templates.
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE project_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    config TEXT NOT NULL,
+    default_profile TEXT NOT NULL,
+    networks TEXT NOT NULL,
+    network_acls TEXT NOT NULL,
+    UNIQUE (name)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating project templates table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV77 adds the table used for project usage accounting.
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE projects_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    type TEXT NOT NULL,
+    pool TEXT NOT NULL DEFAULT '',
+    value REAL NOT NULL DEFAULT 0,
+    UNIQUE (project_id, date, type, pool),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating project usage table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV76 adds the tables used for authentication tokens.
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE auth_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    project_id INTEGER NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    UNIQUE (uuid),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+CREATE TABLE auth_tokens_entitlements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_token_id INTEGER NOT NULL,
+    entitlement TEXT NOT NULL,
+    UNIQUE (auth_token_id, entitlement),
+    FOREIGN KEY (auth_token_id) REFERENCES auth_tokens (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating auth token tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV75 adds the tables used for built-in identity and group management.
+func updateFromV75(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE auth_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    UNIQUE (name)
+);
+
+CREATE TABLE auth_groups_identity_provider_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (auth_group_id, name),
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+
+CREATE TABLE auth_groups_roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    role TEXT NOT NULL,
+    project_id INTEGER,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX auth_groups_roles_unique_auth_group_id_role_project_id ON auth_groups_roles (auth_group_id, role, IFNULL(project_id, -1));
+
+CREATE TABLE auth_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    UNIQUE (authentication_method, identifier)
+);
+
+CREATE TABLE auth_identities_groups (
+    auth_identity_id INTEGER NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    UNIQUE (auth_identity_id, auth_group_id),
+    FOREIGN KEY (auth_identity_id) REFERENCES auth_identities (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating auth tables: %w", err)
+	}
+
+	return nil
 }
 
 // updateFromV74 removes the index preventing the same integration to be used multiple times.
@@ -426,3 +426,23 @@ func (c *ClusterTx) GetNetworkForwards(ctx context.Context, networkID int64, mem
 
 	return forwards, nil
 }
+
+// GetProjectNetworkForwardsCount returns the number of network forwards on the networks of the given project.
+func (c *ClusterTx) GetProjectNetworkForwardsCount(ctx context.Context, projectName string) (int, error) {
+	q := `
+	SELECT COUNT(*)
+	FROM networks_forwards
+	JOIN networks ON networks.id = networks_forwards.network_id
+	JOIN projects ON projects.id = networks.project_id
+	WHERE projects.name = ?
+	`
+
+	var count int
+
+	err := c.tx.QueryRowContext(ctx, q, projectName).Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
@@ -449,3 +449,23 @@ func (c *ClusterTx) GetNetworkLoadBalancers(ctx context.Context, networkID int64
 
 	return loadBalancers, nil
 }
+
+// GetProjectNetworkLoadBalancersCount returns the number of network load balancers on the networks of the given project.
+func (c *ClusterTx) GetProjectNetworkLoadBalancersCount(ctx context.Context, projectName string) (int, error) {
+	q := `
+	SELECT COUNT(*)
+	FROM networks_load_balancers
+	JOIN networks ON networks.id = networks_load_balancers.network_id
+	JOIN projects ON projects.id = networks.project_id
+	WHERE projects.name = ?
+	`
+
+	var count int
+
+	err := c.tx.QueryRowContext(ctx, q, projectName).Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
@@ -79,6 +79,7 @@ const (
 	BucketBackupRemove
 	BucketBackupRename
 	BucketBackupRestore
+	ClusterRollout
 )
 
 // Description return a human-readable description of the operation type.
@@ -210,6 +211,8 @@ func (t Type) Description() string {
 		return "Renaming bucket backup"
 	case BucketBackupRestore:
 		return "Restoring bucket backup"
+	case ClusterRollout:
+		return "Rolling out cluster members"
 	default:
 		return "Executing operation"
 	}
@@ -0,0 +1,178 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// The configuration, default profile, networks and network ACLs of a template are stored as JSON.
+const projectTemplateColumns = "name, description, config, default_profile, networks, network_acls"
+
+// projectTemplateScan scans a project template row into the given template.
+func projectTemplateScan(template *api.ProjectTemplate, scan func(dest ...any) error) error {
+	var config, defaultProfile, networks, networkACLs string
+
+	err := scan(&template.Name, &template.Description, &config, &defaultProfile, &networks, &networkACLs)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range []struct {
+		value string
+		dest  any
+	}{
+		{config, &template.Config},
+		{defaultProfile, &template.DefaultProfile},
+		{networks, &template.Networks},
+		{networkACLs, &template.NetworkACLs},
+	} {
+		err = json.Unmarshal([]byte(field.value), field.dest)
+		if err != nil {
+			return fmt.Errorf("Failed unmarshalling project template %q: %w", template.Name, err)
+		}
+	}
+
+	if template.Config == nil {
+		template.Config = map[string]string{}
+	}
+
+	if template.Networks == nil {
+		template.Networks = []api.NetworksPost{}
+	}
+
+	if template.NetworkACLs == nil {
+		template.NetworkACLs = []api.NetworkACLsPost{}
+	}
+
+	return nil
+}
+
+// projectTemplateMarshal returns the JSON encoded configuration, default profile, networks and network ACLs of a template.
+func projectTemplateMarshal(info api.ProjectTemplatePut) ([]any, error) {
+	values := []any{}
+
+	for _, field := range []any{info.Config, info.DefaultProfile, info.Networks, info.NetworkACLs} {
+		value, err := json.Marshal(field)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, string(value))
+	}
+
+	return values, nil
+}
+
+// GetProjectTemplates returns all the project templates.
+func (c *ClusterTx) GetProjectTemplates(ctx context.Context) ([]api.ProjectTemplate, error) {
+	templates := []api.ProjectTemplate{}
+
+	err := query.Scan(ctx, c.tx, fmt.Sprintf("SELECT %s FROM project_templates ORDER BY name", projectTemplateColumns), func(scan func(dest ...any) error) error {
+		template := api.ProjectTemplate{}
+
+		err := projectTemplateScan(&template, scan)
+		if err != nil {
+			return err
+		}
+
+		templates = append(templates, template)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetProjectTemplate returns the project template with the given name.
+func (c *ClusterTx) GetProjectTemplate(ctx context.Context, name string) (*api.ProjectTemplate, error) {
+	template := api.ProjectTemplate{}
+
+	row := c.tx.QueryRowContext(ctx, fmt.Sprintf("SELECT %s FROM project_templates WHERE name=?", projectTemplateColumns), name)
+
+	err := projectTemplateScan(&template, row.Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Project template not found")
+		}
+
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// CreateProjectTemplate creates a new project template.
+func (c *ClusterTx) CreateProjectTemplate(ctx context.Context, info api.ProjectTemplatesPost) error {
+	values, err := projectTemplateMarshal(info.ProjectTemplatePut)
+	if err != nil {
+		return err
+	}
+
+	args := append([]any{info.Name, info.Description}, values...)
+
+	_, err = c.tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO project_templates (%s) VALUES (?, ?, ?, ?, ?, ?)", projectTemplateColumns), args...)
+
+	return err
+}
+
+// UpdateProjectTemplate updates the project template with the given name.
+func (c *ClusterTx) UpdateProjectTemplate(ctx context.Context, name string, info api.ProjectTemplatePut) error {
+	values, err := projectTemplateMarshal(info)
+	if err != nil {
+		return err
+	}
+
+	args := append(append([]any{info.Description}, values...), name)
+
+	result, err := c.tx.ExecContext(ctx, "UPDATE project_templates SET description=?, config=?, default_profile=?, networks=?, network_acls=? WHERE name=?", args...)
+	if err != nil {
+		return err
+	}
+
+	return projectTemplateCheckAffected(result)
+}
+
+// RenameProjectTemplate renames the project template with the given name.
+func (c *ClusterTx) RenameProjectTemplate(ctx context.Context, name string, newName string) error {
+	result, err := c.tx.ExecContext(ctx, "UPDATE project_templates SET name=? WHERE name=?", newName, name)
+	if err != nil {
+		return err
+	}
+
+	return projectTemplateCheckAffected(result)
+}
+
+// DeleteProjectTemplate deletes the project template with the given name.
+func (c *ClusterTx) DeleteProjectTemplate(ctx context.Context, name string) error {
+	result, err := c.tx.ExecContext(ctx, "DELETE FROM project_templates WHERE name=?", name)
+	if err != nil {
+		return err
+	}
+
+	return projectTemplateCheckAffected(result)
+}
+
+// projectTemplateCheckAffected returns a not found error if no project template was affected by a query.
+func projectTemplateCheckAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Project template not found")
+	}
+
+	return nil
+}
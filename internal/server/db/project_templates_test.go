@@ -0,0 +1,65 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestProjectTemplates(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.TODO()
+
+	err := tx.CreateProjectTemplate(ctx, api.ProjectTemplatesPost{
+		Name: "team",
+		ProjectTemplatePut: api.ProjectTemplatePut{
+			Description: "Team project",
+			Config:      map[string]string{"limits.instances": "10"},
+			DefaultProfile: api.ProfilePut{
+				Devices: map[string]map[string]string{"eth0": {"type": "nic", "network": "internal"}},
+			},
+			Networks: []api.NetworksPost{{Name: "internal"}},
+		},
+	})
+	require.NoError(t, err)
+
+	template, err := tx.GetProjectTemplate(ctx, "team")
+	require.NoError(t, err)
+	assert.Equal(t, "Team project", template.Description)
+	assert.Equal(t, "10", template.Config["limits.instances"])
+	assert.Equal(t, "internal", template.DefaultProfile.Devices["eth0"]["network"])
+	assert.Equal(t, "internal", template.Networks[0].Name)
+	assert.Empty(t, template.NetworkACLs)
+
+	template.Config = nil
+	err = tx.UpdateProjectTemplate(ctx, "team", template.Writable())
+	require.NoError(t, err)
+
+	err = tx.RenameProjectTemplate(ctx, "team", "team-small")
+	require.NoError(t, err)
+
+	templates, err := tx.GetProjectTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "team-small", templates[0].Name)
+	assert.Equal(t, map[string]string{}, templates[0].Config)
+
+	err = tx.DeleteProjectTemplate(ctx, "team-small")
+	require.NoError(t, err)
+
+	_, err = tx.GetProjectTemplate(ctx, "team-small")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	err = tx.DeleteProjectTemplate(ctx, "team-small")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+}
@@ -0,0 +1,89 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// CreateProjectOwner records the identity which created a project from a self-service template along with the
+// authorization group granting it access to the project.
+func (c *ClusterTx) CreateProjectOwner(ctx context.Context, projectName string, authenticationMethod string, identifier string, groupName string) error {
+	var projectID int64
+
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM projects WHERE name=?", projectName).Scan(&projectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return api.StatusErrorf(http.StatusNotFound, "Project %q not found", projectName)
+		}
+
+		return err
+	}
+
+	identityID, err := authIdentityID(ctx, c, authenticationMethod, identifier)
+	if err != nil {
+		return err
+	}
+
+	groupID, err := authGroupID(ctx, c, groupName)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "INSERT INTO projects_owners (project_id, auth_identity_id, auth_group_id) VALUES (?, ?, ?)", projectID, identityID, groupID)
+	if err != nil {
+		return fmt.Errorf("Failed recording owner of project %q: %w", projectName, err)
+	}
+
+	return nil
+}
+
+// GetProjectOwnerCount returns the number of existing projects created by the identity from self-service templates.
+func (c *ClusterTx) GetProjectOwnerCount(ctx context.Context, authenticationMethod string, identifier string) (int, error) {
+	q := `
+SELECT COUNT(*)
+FROM projects_owners
+JOIN auth_identities ON auth_identities.id = projects_owners.auth_identity_id
+WHERE auth_identities.authentication_method=? AND auth_identities.identifier=?
+`
+
+	var count int
+
+	err := c.tx.QueryRowContext(ctx, q, authenticationMethod, identifier).Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
+
+// GetProjectOwnerGroup returns the name of the authorization group granting the owner of a self-service project
+// access to it. An empty string is returned if the project wasn't created from a self-service template or the
+// group no longer exists.
+func (c *ClusterTx) GetProjectOwnerGroup(ctx context.Context, projectName string) (string, error) {
+	q := `
+SELECT auth_groups.name
+FROM projects_owners
+JOIN projects ON projects.id = projects_owners.project_id
+JOIN auth_groups ON auth_groups.id = projects_owners.auth_group_id
+WHERE projects.name=?
+`
+
+	var groupName string
+
+	err := c.tx.QueryRowContext(ctx, q, projectName).Scan(&groupName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return groupName, nil
+}
@@ -0,0 +1,70 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestProjectOwners(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	_, err := cluster.CreateProject(ctx, tx.Tx(), cluster.Project{Name: "p1"})
+	require.NoError(t, err)
+
+	err = tx.CreateAuthGroup(ctx, api.AuthGroupsPost{
+		Name:         "project-p1-admin",
+		AuthGroupPut: api.AuthGroupPut{Roles: []api.AuthGroupRole{{Role: api.AuthRoleAdmin, Project: "p1"}}},
+	})
+	require.NoError(t, err)
+
+	err = tx.CreateAuthIdentity(ctx, api.AuthIdentitiesPost{
+		AuthIdentityPut:      api.AuthIdentityPut{Groups: []string{"project-p1-admin"}},
+		AuthenticationMethod: api.AuthenticationMethodOIDC,
+		Identifier:           "jane.doe@example.com",
+	})
+	require.NoError(t, err)
+
+	err = tx.CreateProjectOwner(ctx, "p1", api.AuthenticationMethodOIDC, "jane.doe@example.com", "project-p1-admin")
+	require.NoError(t, err)
+
+	count, err := tx.GetProjectOwnerCount(ctx, api.AuthenticationMethodOIDC, "jane.doe@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// Ownership follows the project across renames.
+	err = cluster.RenameProject(ctx, tx.Tx(), "p1", "p2")
+	require.NoError(t, err)
+
+	groupName, err := tx.GetProjectOwnerGroup(ctx, "p2")
+	require.NoError(t, err)
+	assert.Equal(t, "project-p1-admin", groupName)
+
+	count, err = tx.GetProjectOwnerCount(ctx, api.AuthenticationMethodOIDC, "jane.doe@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// Projects which weren't created from a self-service template have no owner group.
+	groupName, err = tx.GetProjectOwnerGroup(ctx, api.ProjectDefaultName)
+	require.NoError(t, err)
+	assert.Equal(t, "", groupName)
+
+	// Deleting the project drops its ownership.
+	err = cluster.DeleteProject(ctx, tx.Tx(), "p2")
+	require.NoError(t, err)
+
+	count, err = tx.GetProjectOwnerCount(ctx, api.AuthenticationMethodOIDC, "jane.doe@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,117 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ProjectUsageEntry is the amount of a resource consumed by a project during an hour.
+type ProjectUsageEntry struct {
+	Project string
+	Date    time.Time
+	Type    string
+	Pool    string
+	Value   float64
+}
+
+// AddProjectUsage adds the given amounts to the usage recorded for the projects.
+// The entries are accounted to the hour they fall in and entries of projects which no longer exist are ignored.
+func (c *ClusterTx) AddProjectUsage(ctx context.Context, entries []ProjectUsageEntry) error {
+	projectIDs := map[string]int64{}
+
+	err := query.Scan(ctx, c.tx, "SELECT id, name FROM projects", func(scan func(dest ...any) error) error {
+		var id int64
+		var name string
+
+		err := scan(&id, &name)
+		if err != nil {
+			return err
+		}
+
+		projectIDs[name] = id
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading projects: %w", err)
+	}
+
+	q := `
+INSERT INTO projects_usage (project_id, date, type, pool, value) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (project_id, date, type, pool) DO UPDATE SET value=value+excluded.value
+`
+
+	for _, entry := range entries {
+		projectID, ok := projectIDs[entry.Project]
+		if !ok {
+			continue
+		}
+
+		_, err := c.tx.ExecContext(ctx, q, projectID, entry.Date.UTC().Truncate(time.Hour), entry.Type, entry.Pool, entry.Value)
+		if err != nil {
+			return fmt.Errorf("Failed recording %q usage of project %q: %w", entry.Type, entry.Project, err)
+		}
+	}
+
+	return nil
+}
+
+// GetProjectUsage returns the usage of a project between the given times, summed by type and pool.
+// As the usage is recorded hourly, the hours which started before the end of the period are included.
+func (c *ClusterTx) GetProjectUsage(ctx context.Context, projectName string, from time.Time, to time.Time) ([]ProjectUsageEntry, error) {
+	var projectID int64
+
+	err := c.tx.QueryRowContext(ctx, "SELECT id FROM projects WHERE name=?", projectName).Scan(&projectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Project not found")
+		}
+
+		return nil, err
+	}
+
+	q := `
+SELECT type, pool, SUM(value)
+FROM projects_usage
+WHERE project_id=? AND date >= ? AND date < ?
+GROUP BY type, pool
+ORDER BY type, pool
+`
+
+	entries := []ProjectUsageEntry{}
+	err = query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		entry := ProjectUsageEntry{Project: projectName}
+
+		err := scan(&entry.Type, &entry.Pool, &entry.Value)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry)
+
+		return nil
+	}, projectID, from.UTC().Truncate(time.Hour), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// DeleteProjectUsageBefore deletes the usage recorded for the hours which started before the given time.
+func (c *ClusterTx) DeleteProjectUsageBefore(ctx context.Context, before time.Time) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM projects_usage WHERE date < ?", before.UTC().Truncate(time.Hour))
+	if err != nil {
+		return fmt.Errorf("Failed deleting project usage: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+)
+
+func TestProjectUsage(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	hour := time.Date(2024, 11, 4, 14, 0, 0, 0, time.UTC)
+
+	err := tx.AddProjectUsage(context.TODO(), []db.ProjectUsageEntry{
+		{Project: "default", Date: hour.Add(5 * time.Minute), Type: "cpu", Value: 10},
+		{Project: "default", Date: hour.Add(20 * time.Minute), Type: "cpu", Value: 5},
+		{Project: "default", Date: hour.Add(time.Hour), Type: "cpu", Value: 1},
+		{Project: "default", Date: hour, Type: "storage", Pool: "p1", Value: 2},
+		{Project: "default", Date: hour, Type: "storage", Pool: "p2", Value: 3},
+		{Project: "missing", Date: hour, Type: "cpu", Value: 100},
+	})
+	require.NoError(t, err)
+
+	entries, err := tx.GetProjectUsage(context.TODO(), "default", hour.Add(30*time.Minute), hour.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "cpu", entries[0].Type)
+	assert.Equal(t, float64(15), entries[0].Value)
+	assert.Equal(t, "p1", entries[1].Pool)
+	assert.Equal(t, float64(2), entries[1].Value)
+	assert.Equal(t, "p2", entries[2].Pool)
+	assert.Equal(t, float64(3), entries[2].Value)
+
+	entries, err = tx.GetProjectUsage(context.TODO(), "default", hour, hour.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, float64(16), entries[0].Value)
+
+	_, err = tx.GetProjectUsage(context.TODO(), "missing", hour, hour.Add(time.Hour))
+	assert.Error(t, err)
+
+	// Pruning only keeps the hours which started at or after the given time.
+	err = tx.DeleteProjectUsageBefore(context.TODO(), hour.Add(30*time.Minute))
+	require.NoError(t, err)
+
+	entries, err = tx.GetProjectUsage(context.TODO(), "default", hour, hour.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, float64(16), entries[0].Value)
+
+	err = tx.DeleteProjectUsageBefore(context.TODO(), hour.Add(time.Hour))
+	require.NoError(t, err)
+
+	entries, err = tx.GetProjectUsage(context.TODO(), "default", hour, hour.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, float64(1), entries[0].Value)
+}
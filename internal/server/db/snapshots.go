@@ -96,3 +96,30 @@ func (c *ClusterTx) GetInstanceSnapshotID(ctx context.Context, project, instance
 	id, err := cluster.GetInstanceSnapshotID(ctx, c.tx, project, instance, name)
 	return int(id), err
 }
+
+// GetProjectSnapshotsCount returns the number of instance and custom volume snapshots in the given project.
+func (c *ClusterTx) GetProjectSnapshotsCount(ctx context.Context, projectName string) (int, error) {
+	q := `
+	SELECT
+		(SELECT COUNT(*)
+		FROM instances_snapshots
+		JOIN instances ON instances.id = instances_snapshots.instance_id
+		JOIN projects ON projects.id = instances.project_id
+		WHERE projects.name = ?1)
+		+
+		(SELECT COUNT(*)
+		FROM storage_volumes_snapshots
+		JOIN storage_volumes ON storage_volumes.id = storage_volumes_snapshots.storage_volume_id
+		JOIN projects ON projects.id = storage_volumes.project_id
+		WHERE projects.name = ?1 AND storage_volumes.type = ?2)
+	`
+
+	var count int
+
+	err := c.tx.QueryRowContext(ctx, q, projectName, StoragePoolVolumeTypeCustom).Scan(&count)
+	if err != nil {
+		return -1, err
+	}
+
+	return count, nil
+}
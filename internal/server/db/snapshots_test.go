@@ -182,6 +182,28 @@ func TestGetInstanceSnapshots_SameNameInDifferentProjects(t *testing.T) {
 	assert.Equal(t, "s1", snapshot.Name)
 }
 
+func TestGetProjectSnapshotsCount(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	nodeID1 := int64(1) // This is the default local member
+
+	addContainer(t, tx, nodeID1, "c1")
+	addContainer(t, tx, nodeID1, "c2")
+
+	count, err := tx.GetProjectSnapshotsCount(context.TODO(), "default")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	addInstanceSnapshot(t, tx, 1, "snap1")
+	addInstanceSnapshot(t, tx, 2, "snap2")
+	addInstanceSnapshot(t, tx, 2, "snap3")
+
+	count, err = tx.GetProjectSnapshotsCount(context.TODO(), "default")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
 func addInstanceSnapshot(t *testing.T, tx *db.ClusterTx, instanceID int64, name string) {
 	stmt := `
 INSERT INTO instances_snapshots(instance_id, name, creation_date, description) VALUES (?, ?, ?, '')
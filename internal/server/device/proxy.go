@@ -27,6 +27,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/ip"
 	"github.com/lxc/incus/v6/internal/server/network"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/server/warnings"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -82,16 +83,17 @@ func (d *proxy) validateConfig(instConf instance.ConfigReader) error {
 	}
 
 	rules := map[string]func(string) error{
-		"listen":         validate.Required(validateAddr),
-		"connect":        validate.Required(validateAddr),
-		"bind":           validate.Optional(validateBind),
-		"mode":           validate.Optional(unixValidOctalFileMode),
-		"nat":            validate.Optional(validate.IsBool),
-		"gid":            validate.Optional(unixValidUserID),
-		"uid":            validate.Optional(unixValidUserID),
-		"security.uid":   validate.Optional(unixValidUserID),
-		"security.gid":   validate.Optional(unixValidUserID),
-		"proxy_protocol": validate.Optional(validate.IsBool),
+		"listen":           validate.Required(validateAddr),
+		"connect":          validate.Required(validateAddr),
+		"bind":             validate.Optional(validateBind),
+		"mode":             validate.Optional(unixValidOctalFileMode),
+		"nat":              validate.Optional(validate.IsBool),
+		"gid":              validate.Optional(unixValidUserID),
+		"uid":              validate.Optional(unixValidUserID),
+		"security.uid":     validate.Optional(unixValidUserID),
+		"security.gid":     validate.Optional(unixValidUserID),
+		"proxy_protocol":   validate.Optional(validate.IsBool),
+		"start_on_connect": validate.Optional(validate.IsBool),
 	}
 
 	err := d.config.Validate(rules)
@@ -127,6 +129,16 @@ func (d *proxy) validateConfig(instConf instance.ConfigReader) error {
 		return fmt.Errorf("The PROXY header can only be sent to tcp servers in non-nat mode")
 	}
 
+	if util.IsTrue(d.config["start_on_connect"]) {
+		if d.config["bind"] != "" && d.config["bind"] != "host" {
+			return fmt.Errorf("Only host-bound proxies can start the instance on connection")
+		}
+
+		if listenAddr.ConnType != "tcp" || connectAddr.ConnType != "tcp" {
+			return fmt.Errorf("Only tcp <-> tcp proxies can start the instance on connection")
+		}
+	}
+
 	if (!strings.HasPrefix(d.config["listen"], "unix:") || strings.HasPrefix(d.config["listen"], "unix:@")) &&
 		(d.config["uid"] != "" || d.config["gid"] != "" || d.config["mode"] != "") {
 		return fmt.Errorf("Only proxy devices for non-abstract unix sockets can carry uid, gid, or mode properties")
@@ -221,6 +233,9 @@ func (d *proxy) Start() (*deviceConfig.RunConfig, error) {
 		return nil, err
 	}
 
+	// Release the listeners used to start the instance on connection.
+	ProxyActivationClose(d.inst.Project().Name, d.inst.Name(), d.name)
+
 	// Proxy devices have to be setup once the instance is running.
 	runConf := deviceConfig.RunConfig{}
 	runConf.PostHooks = []func() error{
@@ -395,50 +410,9 @@ func (d *proxy) setupNAT() error {
 		ipVersion = 6
 	}
 
-	var connectIP net.IP
-	var hostName string
-
-	for devName, devConfig := range d.inst.ExpandedDevices() {
-		if devConfig["type"] != "nic" {
-			continue
-		}
-
-		nicType, err := nictype.NICType(d.state, d.inst.Project().Name, devConfig)
-		if err != nil {
-			return err
-		}
-
-		// Check if the instance has a NIC with a static IP that is reachable from the host.
-		if !slices.Contains([]string{"bridged", "routed"}, nicType) {
-			continue
-		}
-
-		// Ensure the connect IP matches one of the NIC's static IPs otherwise we could mess with other
-		// instance's network traffic. If the wildcard address is supplied as the connect host then the
-		// first bridged NIC which has a static IP address defined is selected as the connect host IP.
-		if ipVersion == 4 && devConfig["ipv4.address"] != "" {
-			if connectAddr.Address == devConfig["ipv4.address"] || connectAddr.Address == "0.0.0.0" {
-				connectIP = net.ParseIP(devConfig["ipv4.address"])
-			}
-		} else if ipVersion == 6 && devConfig["ipv6.address"] != "" {
-			if connectAddr.Address == devConfig["ipv6.address"] || connectAddr.Address == "::" {
-				connectIP = net.ParseIP(devConfig["ipv6.address"])
-			}
-		}
-
-		if connectIP != nil {
-			// Get host_name of device so we can enable hairpin mode on bridge port.
-			hostName = d.inst.ExpandedConfig()[fmt.Sprintf("volatile.%s.host_name", devName)]
-			break // Found a match, stop searching.
-		}
-	}
-
-	if connectIP == nil {
-		if connectAddr.Address == "0.0.0.0" || connectAddr.Address == "::" {
-			return fmt.Errorf("Instance has no static IPv%d address assigned to be used as the connect IP", ipVersion)
-		}
-
-		return fmt.Errorf("Connect IP %q must be one of the instance's static IPv%d addresses", connectAddr.Address, ipVersion)
+	connectIP, hostName, err := proxyNATConnectIP(d.state, d.inst, connectAddr.Address, ipVersion)
+	if err != nil {
+		return err
 	}
 
 	// Override the host part of the connectAddr.Addr to the chosen connect IP.
@@ -491,6 +465,58 @@ func (d *proxy) setupNAT() error {
 	return nil
 }
 
+// proxyNATConnectIP returns the static IP of the instance NIC to forward NAT traffic to, along with the host side
+// name of that NIC.
+func proxyNATConnectIP(s *state.State, inst instance.Instance, connectAddress string, ipVersion uint) (net.IP, string, error) {
+	var connectIP net.IP
+	var hostName string
+
+	for devName, devConfig := range inst.ExpandedDevices() {
+		if devConfig["type"] != "nic" {
+			continue
+		}
+
+		nicType, err := nictype.NICType(s, inst.Project().Name, devConfig)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// Check if the instance has a NIC with a static IP that is reachable from the host.
+		if !slices.Contains([]string{"bridged", "routed"}, nicType) {
+			continue
+		}
+
+		// Ensure the connect IP matches one of the NIC's static IPs otherwise we could mess with other
+		// instance's network traffic. If the wildcard address is supplied as the connect host then the
+		// first bridged NIC which has a static IP address defined is selected as the connect host IP.
+		if ipVersion == 4 && devConfig["ipv4.address"] != "" {
+			if connectAddress == devConfig["ipv4.address"] || connectAddress == "0.0.0.0" {
+				connectIP = net.ParseIP(devConfig["ipv4.address"])
+			}
+		} else if ipVersion == 6 && devConfig["ipv6.address"] != "" {
+			if connectAddress == devConfig["ipv6.address"] || connectAddress == "::" {
+				connectIP = net.ParseIP(devConfig["ipv6.address"])
+			}
+		}
+
+		if connectIP != nil {
+			// Get host_name of device so we can enable hairpin mode on bridge port.
+			hostName = inst.ExpandedConfig()[fmt.Sprintf("volatile.%s.host_name", devName)]
+			break // Found a match, stop searching.
+		}
+	}
+
+	if connectIP == nil {
+		if connectAddress == "0.0.0.0" || connectAddress == "::" {
+			return nil, "", fmt.Errorf("Instance has no static IPv%d address assigned to be used as the connect IP", ipVersion)
+		}
+
+		return nil, "", fmt.Errorf("Connect IP %q must be one of the instance's static IPv%d addresses", connectAddress, ipVersion)
+	}
+
+	return connectIP, hostName, nil
+}
+
 func (d *proxy) rewriteHostAddr(addr string) string {
 	fields := strings.SplitN(addr, ":", 2)
 	proto := fields[0]
@@ -597,6 +623,8 @@ func (d *proxy) killProxyProc(pidPath string) error {
 }
 
 func (d *proxy) Remove() error {
+	ProxyActivationClose(d.inst.Project().Name, d.inst.Name(), d.name)
+
 	err := warnings.DeleteWarningsByLocalNodeAndProjectAndTypeAndEntity(d.state.DB.Cluster, d.inst.Project().Name, warningtype.ProxyBridgeNetfilterNotEnabled, cluster.TypeInstance, d.inst.ID())
 	if err != nil {
 		logger.Warn("Failed to delete warning", logger.Ctx{"err": err})
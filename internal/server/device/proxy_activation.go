@@ -0,0 +1,341 @@
+package device
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// proxyActivationListeners keeps track of the listeners of proxy devices which start their stopped instance on
+// connection, keyed by project, instance and device name.
+var proxyActivationListeners = map[string][]net.Listener{}
+var proxyActivationListenersMu sync.Mutex
+
+// ProxyActivationListen listens on the address of a proxy device while its instance is stopped.
+// The handler is called with every incoming connection along with the index of the listen port it was received on.
+func ProxyActivationListen(inst instance.Instance, devName string, devConfig deviceConfig.Device, handler func(conn net.Conn, portIndex int)) error {
+	listenAddr, err := network.ProxyParseAddr(devConfig["listen"])
+	if err != nil {
+		return err
+	}
+
+	if listenAddr.ConnType != "tcp" {
+		return fmt.Errorf("Only tcp proxies can start the instance on connection")
+	}
+
+	// Release any previous listeners for the device so the ports can be bound again.
+	ProxyActivationClose(inst.Project().Name, inst.Name(), devName)
+
+	listeners := make([]net.Listener, 0, len(listenAddr.Ports))
+	for _, port := range listenAddr.Ports {
+		listener, err := net.Listen("tcp", net.JoinHostPort(listenAddr.Address, strconv.FormatUint(port, 10)))
+		if err != nil {
+			for _, listener := range listeners {
+				_ = listener.Close()
+			}
+
+			return fmt.Errorf("Failed listening on port %d: %w", port, err)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	proxyActivationListenersMu.Lock()
+	proxyActivationListeners[inst.Project().Name+"/"+inst.Name()+"/"+devName] = listeners
+	proxyActivationListenersMu.Unlock()
+
+	for portIndex, listener := range listeners {
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					if errors.Is(err, net.ErrClosed) {
+						return
+					}
+
+					logger.Warn("Failed accepting proxy connection", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "device": devName, "err": err})
+					time.Sleep(100 * time.Millisecond)
+					continue
+				}
+
+				go handler(conn, portIndex)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// ProxyActivationClose stops listening on the address of a proxy device on behalf of its stopped instance.
+func ProxyActivationClose(projectName string, instanceName string, devName string) {
+	key := projectName + "/" + instanceName + "/" + devName
+
+	proxyActivationListenersMu.Lock()
+	listeners := proxyActivationListeners[key]
+	delete(proxyActivationListeners, key)
+	proxyActivationListenersMu.Unlock()
+
+	for _, listener := range listeners {
+		_ = listener.Close()
+	}
+}
+
+// proxyActivationConnect returns the connect address and port matching the listen port index of a proxy device.
+// For NAT proxies, the address is replaced by the instance's static IP.
+func proxyActivationConnect(s *state.State, inst instance.Instance, devConfig deviceConfig.Device, portIndex int) (string, uint64, error) {
+	listenAddr, err := network.ProxyParseAddr(devConfig["listen"])
+	if err != nil {
+		return "", 0, err
+	}
+
+	connectAddr, err := network.ProxyParseAddr(devConfig["connect"])
+	if err != nil {
+		return "", 0, err
+	}
+
+	if len(connectAddr.Ports) == 0 {
+		return "", 0, fmt.Errorf("Missing connect port")
+	}
+
+	port := connectAddr.Ports[len(connectAddr.Ports)-1]
+	if portIndex < len(connectAddr.Ports) {
+		port = connectAddr.Ports[portIndex]
+	}
+
+	if util.IsFalseOrEmpty(devConfig["nat"]) {
+		return connectAddr.Address, port, nil
+	}
+
+	ipVersion := uint(4)
+	if strings.Contains(listenAddr.Address, ":") {
+		ipVersion = 6
+	}
+
+	connectIP, _, err := proxyNATConnectIP(s, inst, connectAddr.Address, ipVersion)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return connectIP.String(), port, nil
+}
+
+// ProxyActivationDial connects to the target of a proxy device of a running instance, bypassing the proxy itself.
+// This is used to check whether the service behind the proxy is reachable and to relay the connections received
+// while the instance was starting.
+func ProxyActivationDial(ctx context.Context, s *state.State, inst instance.Instance, devConfig deviceConfig.Device, portIndex int) (net.Conn, error) {
+	address, port, err := proxyActivationConnect(s, inst, devConfig, portIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	target := net.JoinHostPort(address, strconv.FormatUint(port, 10))
+
+	// NAT proxies target an address reachable from the host.
+	if util.IsTrue(devConfig["nat"]) {
+		dialer := net.Dialer{}
+		return dialer.DialContext(ctx, "tcp", target)
+	}
+
+	if inst.Type() != instancetype.Container {
+		return nil, fmt.Errorf("Only NAT mode is supported for proxies on VM instances")
+	}
+
+	pid := inst.InitPID()
+	if pid <= 0 {
+		return nil, fmt.Errorf("Instance isn't running")
+	}
+
+	return proxyDialNetns(ctx, pid, target)
+}
+
+// proxyDialNetns connects to an address from within the network namespace of the given process.
+func proxyDialNetns(ctx context.Context, pid int, address string) (net.Conn, error) {
+	// The namespace is switched for the current thread only, keep the goroutine on it.
+	runtime.LockOSThread()
+
+	hostNetns, err := os.Open("/proc/thread-self/ns/net")
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+
+	defer func() { _ = hostNetns.Close() }()
+
+	instanceNetns, err := os.Open(fmt.Sprintf("/proc/%d/ns/net", pid))
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+
+	defer func() { _ = instanceNetns.Close() }()
+
+	err = unix.Setns(int(instanceNetns.Fd()), unix.CLONE_NEWNET)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("Failed entering the instance network namespace: %w", err)
+	}
+
+	// The socket belongs to the namespace it was created in, the connection remains usable from any thread.
+	dialer := net.Dialer{}
+	conn, dialErr := dialer.DialContext(ctx, "tcp", address)
+
+	err = unix.Setns(int(hostNetns.Fd()), unix.CLONE_NEWNET)
+	if err != nil {
+		// Leave the thread locked so that it gets terminated rather than reused in the wrong namespace.
+		if conn != nil {
+			_ = conn.Close()
+		}
+
+		return nil, fmt.Errorf("Failed restoring the host network namespace: %w", err)
+	}
+
+	runtime.UnlockOSThread()
+
+	return conn, dialErr
+}
+
+// ProxyConnections returns the number of established connections going through a host-bound tcp proxy device.
+// Container connections are counted from within the instance, VM connections from the host connection tracking.
+func ProxyConnections(s *state.State, inst instance.Instance, devConfig deviceConfig.Device) (int, error) {
+	connectAddr, err := network.ProxyParseAddr(devConfig["connect"])
+	if err != nil {
+		return 0, err
+	}
+
+	if inst.Type() == instancetype.Container {
+		pid := inst.InitPID()
+		if pid <= 0 {
+			return 0, nil
+		}
+
+		count := 0
+		for _, name := range []string{"tcp", "tcp6"} {
+			file, err := os.Open(fmt.Sprintf("/proc/%d/net/%s", pid, name))
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+
+				return 0, err
+			}
+
+			n, err := proxyCountSockets(bufio.NewScanner(file), connectAddr.Ports)
+			_ = file.Close()
+			if err != nil {
+				return 0, err
+			}
+
+			count += n
+		}
+
+		return count, nil
+	}
+
+	address, _, err := proxyActivationConnect(s, inst, devConfig, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.Open("/proc/net/nf_conntrack")
+	if err != nil {
+		return 0, fmt.Errorf("Failed reading the connection tracking table: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	return proxyCountConntrack(bufio.NewScanner(file), net.ParseIP(address), connectAddr.Ports)
+}
+
+// proxyCountSockets counts the established sockets using one of the local ports in a /proc/net/tcp{,6} table.
+func proxyCountSockets(scanner *bufio.Scanner, ports []uint64) (int, error) {
+	count := 0
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		// Only count established sockets ("01"), which also skips the header.
+		if len(fields) < 4 || fields[3] != "01" {
+			continue
+		}
+
+		_, localPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+
+		port, err := strconv.ParseUint(localPort, 16, 16)
+		if err != nil {
+			continue
+		}
+
+		if slices.Contains(ports, port) {
+			count++
+		}
+	}
+
+	return count, scanner.Err()
+}
+
+// proxyCountConntrack counts the established tcp connections to the given address and ports in the connection
+// tracking table. Connections are matched on the source of their reply direction as NAT rewrites the destination.
+func proxyCountConntrack(scanner *bufio.Scanner, address net.IP, ports []uint64) (int, error) {
+	count := 0
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if !slices.Contains(fields, "tcp") || !slices.Contains(fields, "ESTABLISHED") {
+			continue
+		}
+
+		// The first src/sport pair is the original direction, the second one the reply direction.
+		var sources []string
+		var sourcePorts []string
+		for _, field := range fields {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+
+			switch key {
+			case "src":
+				sources = append(sources, value)
+			case "sport":
+				sourcePorts = append(sourcePorts, value)
+			}
+		}
+
+		if len(sources) < 2 || len(sourcePorts) < 2 || !address.Equal(net.ParseIP(sources[1])) {
+			continue
+		}
+
+		port, err := strconv.ParseUint(sourcePorts[1], 10, 16)
+		if err != nil {
+			continue
+		}
+
+		if slices.Contains(ports, port) {
+			count++
+		}
+	}
+
+	return count, scanner.Err()
+}
@@ -448,6 +448,12 @@ func (d *common) LogPath() string {
 	return internalUtil.LogPath(name)
 }
 
+// recordingsPath returns the path to the instance's session recordings.
+func (d *common) recordingsPath() string {
+	name := project.Instance(d.project.Name, d.name)
+	return internalUtil.VarPath("recordings", name)
+}
+
 // RunPath returns the instance's runtime path.
 func (d *common) RunPath() string {
 	name := project.Instance(d.project.Name, d.name)
@@ -1046,6 +1052,44 @@ func (d *common) recordLastState() error {
 	})
 }
 
+// ignitionConfig returns the Ignition configuration of the instance.
+// The configurations of the profiles are merged in order, followed by the one of the instance itself.
+func (d *common) ignitionConfig() (string, error) {
+	configs := make([]string, 0, len(d.profiles)+1)
+	for _, profile := range d.profiles {
+		configs = append(configs, profile.Config["ignition.config"])
+	}
+
+	configs = append(configs, d.localConfig["ignition.config"])
+
+	return internalInstance.MergeIgnitionConfigs(configs)
+}
+
+// writeIgnitionConfig writes the Ignition configuration of the instance to the given path.
+// Any stale configuration is removed when none is set and false is returned.
+func (d *common) writeIgnitionConfig(path string) (bool, error) {
+	config, err := d.ignitionConfig()
+	if err != nil {
+		return false, err
+	}
+
+	if config == "" {
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	err = os.WriteFile(path, []byte(config), 0600)
+	if err != nil {
+		return false, fmt.Errorf("Failed writing Ignition configuration: %w", err)
+	}
+
+	return true, nil
+}
+
 func (d *common) setCoreSched(pids []int) error {
 	if !d.state.OS.CoreScheduling {
 		return nil
@@ -2471,6 +2471,33 @@ ff02::2 ip6-allrouters
 		}
 	}
 
+	// Expose the Ignition configuration at the location read by Ignition.
+	ignitionPath := filepath.Join(d.Path(), "ignition.ign")
+	hasIgnition, err := d.writeIgnitionConfig(ignitionPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if hasIgnition {
+		idMap, err := d.CurrentIdmap()
+		if err != nil {
+			return "", nil, err
+		}
+
+		if idMap != nil {
+			uid, gid := idMap.ShiftFromNS(0, 0)
+			err = os.Chown(ignitionPath, int(uid), int(gid))
+			if err != nil {
+				return "", nil, err
+			}
+		}
+
+		err = lxcSetConfigItem(cc, "lxc.mount.entry", fmt.Sprintf("%s usr/lib/ignition/user.ign none bind,ro,create=file,optional 0 0", ignitionPath))
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
 	// Check if we should start a dedicated LXCFS.
 	if d.state.GlobalConfig.InstancesLXCFSPerInstance() {
 		if !util.PathExists(filepath.Join(d.RunPath(), "lxcfs", "proc")) {
@@ -4141,6 +4168,12 @@ func (d *lxc) delete(force bool) error {
 
 		// Clean things up.
 		d.cleanup()
+
+		// Remove the session recordings.
+		err = os.RemoveAll(d.recordingsPath())
+		if err != nil {
+			return fmt.Errorf("Failed removing session recordings: %w", err)
+		}
 	}
 
 	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -4290,6 +4323,18 @@ func (d *lxc) Rename(newName string, applyTemplateTrigger bool) error {
 			return fmt.Errorf("Failed renaming instance: %w", err)
 		}
 	}
+	// Move the session recordings.
+	if !d.IsSnapshot() && util.PathExists(d.recordingsPath()) {
+		newRecordingsPath := internalUtil.VarPath("recordings", project.Instance(d.Project().Name, newName))
+		_ = os.RemoveAll(newRecordingsPath)
+
+		err := os.Rename(d.recordingsPath(), newRecordingsPath)
+		if err != nil {
+			d.logger.Error("Failed renaming instance", ctxMap)
+			return fmt.Errorf("Failed renaming instance: %w", err)
+		}
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -4635,6 +4680,14 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 			return fmt.Errorf("Invalid expanded config: %w", err)
 		}
 
+		// Validate the startup dependencies.
+		if slices.Contains(changedConfig, "boot.depends_on") {
+			err = instance.ValidDependencies(d.state, d.project.Name, d.name, d.expandedConfig)
+			if err != nil {
+				return fmt.Errorf("Invalid startup dependencies: %w", err)
+			}
+		}
+
 		// Do full expanded validation of the devices diff.
 		err = instance.ValidDevices(d.state, d.project, d.Type(), d.localDevices, d.expandedDevices)
 		if err != nil {
@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -114,6 +115,9 @@ const qemuBlockDevIDPrefix = "incus_"
 // qemuMigrationNBDExportName is the name of the disk device export by the migration NBD server.
 const qemuMigrationNBDExportName = "incus_root"
 
+// qemuMemoryHotplugBlockSize is the granularity at which memory gets hotplugged (default virtio-mem block size).
+const qemuMemoryHotplugBlockSize = 2 * 1024 * 1024
+
 // qemuSparseUSBPorts is the amount of sparse USB ports for VMs.
 // 4 are reserved, and the other 4 can be used for any USB device.
 const qemuSparseUSBPorts = 8
@@ -1110,7 +1114,10 @@ func (d *qemu) checkStateStorage() error {
 	}
 
 	memoryLimitStr := qemudefault.MemSize
-	if d.expandedConfig["limits.memory"] != "" {
+	if d.expandedConfig["limits.memory.max"] != "" {
+		// Hotplugged memory is part of the state too.
+		memoryLimitStr = d.expandedConfig["limits.memory.max"]
+	} else if d.expandedConfig["limits.memory"] != "" {
 		memoryLimitStr = d.expandedConfig["limits.memory"]
 	}
 
@@ -1120,7 +1127,7 @@ func (d *qemu) checkStateStorage() error {
 	}
 
 	if stateDiskSize < memoryLimit {
-		return fmt.Errorf("Stateful stop and snapshots require that the instance limits.memory is less than size.state on the root disk device")
+		return fmt.Errorf("Stateful stop and snapshots require that the instance limits.memory (or limits.memory.max) is less than size.state on the root disk device")
 	}
 
 	return nil
@@ -1296,6 +1303,35 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		volatileSet["volatile.uuid.generation"] = vmGenUUID
 	}
 
+	if d.expandedConfig["limits.memory.max"] != "" && !d.architectureSupportsMemoryHotplug() {
+		d.logger.Warn("Memory hotplug isn't supported, ignoring limits.memory.max")
+	}
+
+	// Record the boot time memory size as restoring the state of a VM using memory hotplug requires the same
+	// memory layout, regardless of later changes to limits.memory. This also records whether memory hotplug is
+	// in use for as long as the VM runs.
+	if !stateful || !d.stateful {
+		bootMemory := ""
+		if d.expandedConfig["limits.memory.max"] != "" && d.architectureSupportsMemoryHotplug() {
+			memSize := d.expandedConfig["limits.memory"]
+			if memSize == "" {
+				memSize = qemudefault.MemSize
+			}
+
+			memSizeBytes, err := ParseMemoryStr(memSize)
+			if err != nil {
+				op.Done(err)
+				return fmt.Errorf("limits.memory invalid: %w", err)
+			}
+
+			bootMemory = strconv.FormatInt(memSizeBytes, 10)
+		}
+
+		if d.localConfig["volatile.memory.boot"] != bootMemory {
+			volatileSet["volatile.memory.boot"] = bootMemory
+		}
+	}
+
 	// Generate the config drive.
 	err = d.generateConfigShare()
 	if err != nil {
@@ -1637,6 +1673,18 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		}
 	}
 
+	// Pass the Ignition configuration through the firmware configuration device.
+	ignitionPath := filepath.Join(d.RunPath(), "ignition.ign")
+	hasIgnition, err := d.writeIgnitionConfig(ignitionPath)
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
+	if hasIgnition {
+		qemuArgs = append(qemuArgs, "-fw_cfg", fmt.Sprintf("name=opt/com.coreos/config,file=%s", qemuEscapeCmdline(ignitionPath)))
+	}
+
 	// Attempt to drop privileges (doesn't work when restoring state).
 	if !stateful && d.state.OS.UnprivUser != "" {
 		qemuArgs = append(qemuArgs, "-runas", d.state.OS.UnprivUser)
@@ -3767,6 +3815,29 @@ func (d *qemu) generateQemuConfig(cpuInfo *cpuTopology, mountInfo *storagePools.
 		}
 	}
 
+	// Add the memory hotplug device.
+	_, hotplugSize, pluggedSize, err := d.memorySizes()
+	if err != nil {
+		return nil, err
+	}
+
+	if hotplugSize > 0 && slices.Contains([]string{"pcie", "pci"}, bus.name) {
+		devBus, devAddr, multi = bus.allocate(busFunctionGroupNone)
+		memoryHotplugOpts := qemuMemoryHotplugOpts{
+			dev: qemuDevOpts{
+				busName:       bus.name,
+				devBus:        devBus,
+				devAddr:       devAddr,
+				multifunction: multi,
+			},
+			sizeMB:          hotplugSize / 1024 / 1024,
+			requestedSizeMB: pluggedSize / 1024 / 1024,
+			numaNode:        d.architecture == osarch.ARCH_64BIT_INTEL_X86,
+		}
+
+		conf = append(conf, qemuMemoryHotplug(&memoryHotplugOpts)...)
+	}
+
 	// Allocate 8 PCI slots for hotplug devices.
 	for i := 0; i < 8; i++ {
 		bus.allocate(busFunctionGroupNone)
@@ -3900,14 +3971,9 @@ func (d *qemu) addCPUMemoryConfig(conf *[]cfg.Section, cpuInfo *cpuTopology) err
 	}
 
 	// Configure memory limit.
-	memSize := d.expandedConfig["limits.memory"]
-	if memSize == "" {
-		memSize = qemudefault.MemSize // Default if no memory limit specified.
-	}
-
-	memSizeBytes, err := ParseMemoryStr(memSize)
+	memSizeBytes, hotplugSizeBytes, _, err := d.memorySizes()
 	if err != nil {
-		return fmt.Errorf("limits.memory invalid: %w", err)
+		return err
 	}
 
 	cpuOpts.hugepages = ""
@@ -3925,14 +3991,76 @@ func (d *qemu) addCPUMemoryConfig(conf *[]cfg.Section, cpuInfo *cpuTopology) err
 	nodeMemory := int64(memSizeMB / int64(len(hostNodes)))
 	cpuOpts.memory = nodeMemory
 
+	// Memory hotplug requires the maximum memory size to be known at boot time.
+	maxMemSizeMB := int64(0)
+	if hotplugSizeBytes > 0 {
+		maxMemSizeMB = memSizeMB + hotplugSizeBytes/1024/1024
+	}
+
 	if conf != nil {
-		*conf = append(*conf, qemuMemory(&qemuMemoryOpts{memSizeMB})...)
+		*conf = append(*conf, qemuMemory(&qemuMemoryOpts{memSizeMB: memSizeMB, maxMemSizeMB: maxMemSizeMB})...)
 		*conf = append(*conf, qemuCPU(&cpuOpts, cpuPinning)...)
 	}
 
 	return nil
 }
 
+// memorySizes returns the boot time memory size, the size of the hotpluggable memory region and how much of that
+// region is plugged to reach limits.memory, all in bytes. There is no hotpluggable region unless limits.memory.max
+// is set and memory hotplug is supported.
+func (d *qemu) memorySizes() (int64, int64, int64, error) {
+	memSize := d.expandedConfig["limits.memory"]
+	if memSize == "" {
+		memSize = qemudefault.MemSize // Default if no memory limit specified.
+	}
+
+	memSizeBytes, err := ParseMemoryStr(memSize)
+	if err != nil {
+		return -1, -1, -1, fmt.Errorf("limits.memory invalid: %w", err)
+	}
+
+	maxMemSize := d.expandedConfig["limits.memory.max"]
+	if maxMemSize == "" {
+		return memSizeBytes, 0, 0, nil
+	}
+
+	if util.IsTrue(d.expandedConfig["limits.memory.hugepages"]) {
+		return -1, -1, -1, fmt.Errorf("limits.memory.max cannot be used with huge pages")
+	}
+
+	if !d.architectureSupportsMemoryHotplug() {
+		return memSizeBytes, 0, 0, nil
+	}
+
+	maxMemSizeBytes, err := ParseMemoryStr(maxMemSize)
+	if err != nil {
+		return -1, -1, -1, fmt.Errorf("limits.memory.max invalid: %w", err)
+	}
+
+	if maxMemSizeBytes < memSizeBytes {
+		return -1, -1, -1, fmt.Errorf("limits.memory cannot exceed limits.memory.max")
+	}
+
+	// Keep the memory layout the VM was started with.
+	bootSizeBytes := memSizeBytes
+	if d.localConfig["volatile.memory.boot"] != "" {
+		bootSizeBytes, err = strconv.ParseInt(d.localConfig["volatile.memory.boot"], 10, 64)
+		if err != nil {
+			return -1, -1, -1, fmt.Errorf("volatile.memory.boot invalid: %w", err)
+		}
+	}
+
+	hotplugSizeBytes := (maxMemSizeBytes - bootSizeBytes) / qemuMemoryHotplugBlockSize * qemuMemoryHotplugBlockSize
+	if hotplugSizeBytes <= 0 {
+		return bootSizeBytes, 0, 0, nil
+	}
+
+	pluggedSizeBytes := min(max(memSizeBytes-bootSizeBytes, 0), hotplugSizeBytes)
+	pluggedSizeBytes = (pluggedSizeBytes + qemuMemoryHotplugBlockSize - 1) / qemuMemoryHotplugBlockSize * qemuMemoryHotplugBlockSize
+
+	return bootSizeBytes, hotplugSizeBytes, pluggedSizeBytes, nil
+}
+
 // addFileDescriptor adds a file path to the list of files to open and pass file descriptor to qemu.
 // Returns the file descriptor number that qemu will receive.
 func (d *qemu) addFileDescriptor(fdFiles *[]*os.File, file *os.File) int {
@@ -5194,12 +5322,26 @@ func (d *qemu) snapshot(name string, expiry time.Time, stateful bool) error {
 		}
 	}
 
+	// Quiesce the guest file systems for an application-consistent snapshot.
+	thaw := func() error { return nil }
+	if !stateful {
+		thaw, err = d.FreezeFilesystems("snapshot")
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create the snapshot.
 	err = d.snapshotCommon(d, name, expiry, stateful)
+	thawErr := thaw()
 	if err != nil {
 		return err
 	}
 
+	if thawErr != nil {
+		d.logger.Warn("Snapshot may not be consistent", logger.Ctx{"snapshot": name, "err": thawErr})
+	}
+
 	// Resume the VM once the disk state has been saved.
 	if stateful {
 		// Remove the state from the main volume.
@@ -5460,6 +5602,18 @@ func (d *qemu) Rename(newName string, applyTemplateTrigger bool) error {
 		}
 	}
 
+	// Move the session recordings.
+	if !d.IsSnapshot() && util.PathExists(d.recordingsPath()) {
+		newRecordingsPath := internalUtil.VarPath("recordings", project.Instance(d.Project().Name, newName))
+		_ = os.RemoveAll(newRecordingsPath)
+
+		err := os.Rename(d.recordingsPath(), newRecordingsPath)
+		if err != nil {
+			d.logger.Error("Failed renaming instance", ctxMap)
+			return err
+		}
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -5751,6 +5905,14 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			return fmt.Errorf("Invalid expanded config: %w", err)
 		}
 
+		// Validate the startup dependencies.
+		if slices.Contains(changedConfig, "boot.depends_on") {
+			err = instance.ValidDependencies(d.state, d.project.Name, d.name, d.expandedConfig)
+			if err != nil {
+				return fmt.Errorf("Invalid startup dependencies: %w", err)
+			}
+		}
+
 		// Do full expanded validation of the devices diff.
 		err = instance.ValidDevices(d.state, d.project, d.Type(), d.localDevices, d.expandedDevices)
 		if err != nil {
@@ -6080,6 +6242,21 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 
 	baseSizeMB := baseSizeBytes / 1024 / 1024
 
+	// Plug or unplug memory above the boot time size when using memory hotplug.
+	hotplugDevices, err := monitor.GetVirtioMemDevices()
+	if err != nil {
+		return err
+	}
+
+	if len(hotplugDevices) > 0 {
+		err = d.updateMemoryHotplug(monitor, hotplugDevices[0], newSizeBytes-baseSizeBytes)
+		if err != nil {
+			return err
+		}
+
+		baseSizeMB += hotplugDevices[0].MaxSize / 1024 / 1024
+	}
+
 	curSizeBytes, err := monitor.GetMemoryBalloonSizeBytes()
 	if err != nil {
 		return err
@@ -6090,6 +6267,10 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 	if curSizeMB == newSizeMB {
 		return nil
 	} else if baseSizeMB < newSizeMB {
+		if len(hotplugDevices) > 0 {
+			return fmt.Errorf("Cannot increase memory size beyond limits.memory.max when VM is running (Maximum size %dMiB, new size %dMiB)", baseSizeMB, newSizeMB)
+		}
+
 		return fmt.Errorf("Cannot increase memory size beyond boot time size when VM is running (Boot time size %dMiB, new size %dMiB)", baseSizeMB, newSizeMB)
 	}
 
@@ -6126,6 +6307,60 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 	return fmt.Errorf("Failed setting memory to %dMiB (currently %dMiB) as it was taking too long", newSizeMB, curSizeMB)
 }
 
+// memoryHotplugged returns the amount of memory currently plugged through the memory hotplug device in bytes.
+func (d *qemu) memoryHotplugged() (int64, error) {
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return -1, err
+	}
+
+	return monitor.GetVirtioMemSize()
+}
+
+// updateMemoryHotplug sets how much memory the guest should plug from the memory hotplug device and waits for it to
+// do so. The balloon then adjusts the effective memory size on top of that.
+func (d *qemu) updateMemoryHotplug(monitor *qmp.Monitor, device qmp.VirtioMemDevice, sizeBytes int64) error {
+	blockSize := device.BlockSize
+	if blockSize <= 0 {
+		blockSize = qemuMemoryHotplugBlockSize
+	}
+
+	// Round up to the next block, the balloon takes care of the remainder.
+	sizeBytes = (max(sizeBytes, 0) + blockSize - 1) / blockSize * blockSize
+	if sizeBytes > device.MaxSize {
+		return fmt.Errorf("Cannot increase memory size beyond limits.memory.max when VM is running (Hotpluggable size %dMiB, requested %dMiB)", device.MaxSize/1024/1024, sizeBytes/1024/1024)
+	}
+
+	if sizeBytes == device.RequestedSize {
+		return nil
+	}
+
+	err := monitor.SetVirtioMemRequestedSize(device.ID, sizeBytes)
+	if err != nil {
+		return fmt.Errorf("Failed setting hotplugged memory size: %w", err)
+	}
+
+	// The guest (un)plugs memory asynchronously, wait for it so that the balloon target applies to the new size.
+	for i := 0; i < 20; i++ {
+		devices, err := monitor.GetVirtioMemDevices()
+		if err != nil {
+			return err
+		}
+
+		for _, dev := range devices {
+			if dev.ID == device.ID && dev.Size == sizeBytes {
+				return nil
+			}
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	d.logger.Warn("Guest didn't plug the requested memory in time, check that it supports virtio-mem", logger.Ctx{"requested": sizeBytes})
+
+	return nil
+}
+
 func (d *qemu) removeUnixDevices() error {
 	// Check that we indeed have devices to remove.
 	if !util.PathExists(d.DevicesPath()) {
@@ -6356,6 +6591,12 @@ func (d *qemu) delete(force bool) error {
 
 		// Clean things up.
 		d.cleanup()
+
+		// Remove the session recordings.
+		err = os.RemoveAll(d.recordingsPath())
+		if err != nil {
+			return fmt.Errorf("Failed removing session recordings: %w", err)
+		}
 	}
 
 	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -8117,6 +8358,16 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 			}
 		}
 
+		// Report the memory plugged through the memory hotplug device.
+		if d.localConfig["volatile.memory.boot"] != "" {
+			hotplugged, err := d.memoryHotplugged()
+			if err != nil {
+				d.logger.Warn("Could not get hotplugged memory size", logger.Ctx{"err": err})
+			} else {
+				status.Memory.Hotplugged = hotplugged
+			}
+		}
+
 		status.Pid = int64(pid)
 		status.StartedAt, err = d.processStartedAt(d.InitPID())
 		if err != nil {
@@ -8189,6 +8440,72 @@ func (d *qemu) agentGetState() (*api.InstanceState, error) {
 	return status, nil
 }
 
+// FreezeFilesystems asks the agent to run the guest pre-snapshot hooks and freeze the guest file systems.
+// The returned function thaws them again and fails if the agent already thawed them on its own because the
+// timeout was exceeded. Nothing is done if the instance isn't running, its agent isn't reachable or freezing
+// is disabled.
+func (d *qemu) FreezeFilesystems(reason string) (func() error, error) {
+	noop := func() error { return nil }
+
+	if !d.IsRunning() || util.IsFalse(d.expandedConfig["snapshots.freeze"]) {
+		return noop, nil
+	}
+
+	timeout := 30
+	if d.expandedConfig["snapshots.freeze.timeout"] != "" {
+		value, err := strconv.Atoi(d.expandedConfig["snapshots.freeze.timeout"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid snapshots.freeze.timeout: %w", err)
+		}
+
+		timeout = value
+	}
+
+	client, err := d.getAgentClient()
+	if err != nil {
+		if errors.Is(err, errQemuAgentOffline) {
+			d.logger.Debug("Agent isn't running, skipping file system freeze", logger.Ctx{"reason": reason})
+			return noop, nil
+		}
+
+		return nil, err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(nil, client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to agent: %w", err)
+	}
+
+	_, _, err = agent.RawQuery("POST", "/1.0/filesystems/freeze", agentAPI.FilesystemsFreezePost{Reason: reason, Timeout: timeout}, "")
+	if err != nil {
+		agent.Disconnect()
+
+		// Older agents don't support freezing.
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			d.logger.Debug("Agent doesn't support freezing, skipping file system freeze", logger.Ctx{"reason": reason})
+			return noop, nil
+		}
+
+		return nil, fmt.Errorf("Failed freezing guest file systems: %w", err)
+	}
+
+	d.logger.Debug("Froze guest file systems", logger.Ctx{"reason": reason})
+
+	thaw := func() error {
+		defer agent.Disconnect()
+
+		_, _, err := agent.RawQuery("POST", "/1.0/filesystems/thaw", agentAPI.FilesystemsThawPost{Reason: reason}, "")
+		if err != nil {
+			return fmt.Errorf("Failed thawing guest file systems: %w", err)
+		}
+
+		d.logger.Debug("Thawed guest file systems", logger.Ctx{"reason": reason})
+		return nil
+	}
+
+	return sync.OnceValue(thaw), nil
+}
+
 // IsRunning returns whether or not the instance is running.
 func (d *qemu) IsRunning() bool {
 	return d.isRunningStatusCode(d.statusCode())
@@ -8965,6 +9282,14 @@ func (d *qemu) checkFeatures(hostArch int, qemuPath string) (map[string]any, err
 		features["cpu_hotplug"] = struct{}{}
 	}
 
+	// Check memory hotplug feature.
+	err = monitor.CheckDeviceType("virtio-mem-pci")
+	if err != nil {
+		logger.Debug("Failed checking virtio-mem support during VM feature check", logger.Ctx{"err": err})
+	} else {
+		features["memory_hotplug"] = struct{}{}
+	}
+
 	// Check AMD SEV features (only for x86 architecture)
 	if hostArch == osarch.ARCH_64BIT_INTEL_X86 {
 		cmdline, err := os.ReadFile("/proc/cmdline")
@@ -9047,21 +9372,38 @@ func (d *qemu) Metrics(hostInterfaces []net.Interface) (*metrics.MetricSet, erro
 		return nil, ErrInstanceIsStopped
 	}
 
+	var metricSet *metrics.MetricSet
+	var err error
+
 	if d.agentMetricsEnabled() {
-		metrics, err := d.getAgentMetrics()
+		metricSet, err = d.getAgentMetrics()
 		if err != nil {
 			if !errors.Is(err, errQemuAgentOffline) {
 				d.logger.Warn("Could not get VM metrics from agent", logger.Ctx{"err": err})
 			}
 
 			// Fallback data if agent is not reachable.
-			return d.getQemuMetrics()
+			metricSet, err = d.getQemuMetrics()
 		}
+	} else {
+		metricSet, err = d.getQemuMetrics()
+	}
+
+	if err != nil {
+		return nil, err
+	}
 
-		return metrics, nil
+	// Add the memory plugged through the memory hotplug device.
+	if d.localConfig["volatile.memory.boot"] != "" {
+		hotplugged, err := d.memoryHotplugged()
+		if err != nil {
+			d.logger.Warn("Could not get hotplugged memory size", logger.Ctx{"err": err})
+		} else {
+			metricSet.AddSamples(metrics.MemoryHotpluggedBytes, metrics.Sample{Value: float64(hotplugged)})
+		}
 	}
 
-	return d.getQemuMetrics()
+	return metricSet, nil
 }
 
 func (d *qemu) getAgentMetrics() (*metrics.MetricSet, error) {
@@ -9335,6 +9677,18 @@ func (d *qemu) architectureSupportsCPUHotplug() bool {
 	return found
 }
 
+func (d *qemu) architectureSupportsMemoryHotplug() bool {
+	// Memory hotplug relies on the virtio-mem device, only used on x86_64 where its block size is predictable.
+	if d.architecture != osarch.ARCH_64BIT_INTEL_X86 {
+		return false
+	}
+
+	// Check supported features.
+	info := DriverStatuses()[instancetype.VM].Info
+	_, found := info.Features["memory_hotplug"]
+	return found
+}
+
 func (d *qemu) postCPUHotplug(monitor *qmp.Monitor) error {
 	// Get the vCPU PID list.
 	pids, err := monitor.GetCPUs()
@@ -9408,10 +9762,31 @@ func (d *qemu) postCPUHotplug(monitor *qmp.Monitor) error {
 
 // ConsoleLog returns all output sent to the instance's console's ring buffer since startup.
 func (d *qemu) ConsoleLog() (string, error) {
+	err := d.FlushConsoleLog()
+	if err != nil {
+		return "", err
+	}
+
+	// Read and return the complete log for this instance.
+	fullLog, err := os.ReadFile(d.common.ConsoleBufferLogPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// If there's no log file yet, such as right at VM creation, return an empty string.
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return string(fullLog), nil
+}
+
+// FlushConsoleLog drains the instance's console's ring buffer and appends its content to the console log file.
+func (d *qemu) FlushConsoleLog() error {
 	// Setup a new operation.
 	op, err := operationlock.CreateWaitGet(d.Project().Name, d.Name(), d.op, operationlock.ActionConsoleRetrieve, []operationlock.Action{operationlock.ActionRestart, operationlock.ActionRestore, operationlock.ActionMigrate}, false, true)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	// Only mark the operation as done if only processing the console retrieval.
@@ -9422,48 +9797,37 @@ func (d *qemu) ConsoleLog() (string, error) {
 	// Check if the agent is running.
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	logString, err := monitor.RingbufRead("console")
 	if err != nil {
 		// If a VM was started by an older version of Incus which was then upgraded, its
 		// console device won't be a ring buffer. We don't want to cause an error in this
-		// case, so just return an empty string.
+		// case, so just skip it.
 		if errors.Is(err, qmp.ErrNotARingbuf) {
-			return "", nil
+			return nil
 		}
 
-		return "", err
+		return err
 	}
 
 	// If we got data back, append it to the log file for this instance.
 	if logString != "" {
 		logFile, err := os.OpenFile(d.common.ConsoleBufferLogPath(), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
 		if err != nil {
-			return "", err
+			return err
 		}
 
 		defer logFile.Close()
 
 		_, err = logFile.WriteString(logString)
 		if err != nil {
-			return "", err
-		}
-	}
-
-	// Read and return the complete log for this instance.
-	fullLog, err := os.ReadFile(d.common.ConsoleBufferLogPath())
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			// If there's no log file yet, such as right at VM creation, return an empty string.
-			return "", nil
+			return err
 		}
-
-		return "", err
 	}
 
-	return string(fullLog), nil
+	return nil
 }
 
 // consoleSwapRBWithSocket swaps the qemu backend for the instance's console to a unix socket.
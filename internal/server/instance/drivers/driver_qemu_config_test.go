@@ -99,15 +99,21 @@ func TestQemuConfigTemplates(t *testing.T) {
 			opts     qemuMemoryOpts
 			expected string
 		}{{
-			qemuMemoryOpts{4096},
+			qemuMemoryOpts{4096, 0},
 			`# Memory
 			[memory]
 			size = "4096M"`,
 		}, {
-			qemuMemoryOpts{8192},
+			qemuMemoryOpts{8192, 0},
 			`# Memory
 			[memory]
 			size = "8192M"`,
+		}, {
+			qemuMemoryOpts{2048, 8192},
+			`# Memory
+			[memory]
+			size = "2048M"
+			maxmem = "8192M"`,
 		}}
 		for _, tc := range testCases {
 			runTest(tc.expected, qemuMemory(&tc.opts))
@@ -247,6 +253,46 @@ func TestQemuConfigTemplates(t *testing.T) {
 		}
 	})
 
+	t.Run("qemu_memory_hotplug", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuMemoryHotplugOpts
+			expected string
+		}{{
+			qemuMemoryHotplugOpts{qemuDevOpts{"pcie", "qemu_pcie0", "00.2", false}, 6144, 1024, true},
+			`# Memory hotplug
+			[object "qemu_memory"]
+			qom-type = "memory-backend-ram"
+			size = "6144M"
+
+			[device "dev-qemu_memory"]
+			driver = "virtio-mem-pci"
+			bus = "qemu_pcie0"
+			addr = "00.2"
+			memdev = "qemu_memory"
+			requested-size = "1024M"
+			node = "0"
+			`,
+		}, {
+			qemuMemoryHotplugOpts{qemuDevOpts{"pci", "qemu_pci0", "00.3", true}, 2048, 0, false},
+			`# Memory hotplug
+			[object "qemu_memory"]
+			qom-type = "memory-backend-ram"
+			size = "2048M"
+
+			[device "dev-qemu_memory"]
+			driver = "virtio-mem-pci"
+			bus = "qemu_pci0"
+			addr = "00.3"
+			multifunction = "on"
+			memdev = "qemu_memory"
+			requested-size = "0M"
+			`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuMemoryHotplug(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_rng", func(t *testing.T) {
 		testCases := []struct {
 			opts     qemuDevOpts
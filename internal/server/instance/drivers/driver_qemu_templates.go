@@ -110,14 +110,21 @@ func qemuBase(opts *qemuBaseOpts) []cfg.Section {
 }
 
 type qemuMemoryOpts struct {
-	memSizeMB int64
+	memSizeMB    int64
+	maxMemSizeMB int64
 }
 
 func qemuMemory(opts *qemuMemoryOpts) []cfg.Section {
+	entries := []cfg.Entry{{Key: "size", Value: fmt.Sprintf("%dM", opts.memSizeMB)}}
+
+	if opts.maxMemSizeMB > 0 {
+		entries = append(entries, cfg.Entry{Key: "maxmem", Value: fmt.Sprintf("%dM", opts.maxMemSizeMB)})
+	}
+
 	return []cfg.Section{{
 		Name:    "memory",
 		Comment: "Memory",
-		Entries: []cfg.Entry{{Key: "size", Value: fmt.Sprintf("%dM", opts.memSizeMB)}},
+		Entries: entries,
 	}}
 }
 
@@ -285,6 +292,40 @@ func qemuBalloon(opts *qemuDevOpts) []cfg.Section {
 	}}
 }
 
+type qemuMemoryHotplugOpts struct {
+	dev             qemuDevOpts
+	sizeMB          int64
+	requestedSizeMB int64
+	numaNode        bool
+}
+
+func qemuMemoryHotplug(opts *qemuMemoryHotplugOpts) []cfg.Section {
+	entriesOpts := qemuDevEntriesOpts{
+		dev:     opts.dev,
+		pciName: "virtio-mem-pci",
+	}
+
+	entries := append(qemuDeviceEntries(&entriesOpts),
+		cfg.Entry{Key: "memdev", Value: "qemu_memory"},
+		cfg.Entry{Key: "requested-size", Value: fmt.Sprintf("%dM", opts.requestedSizeMB)})
+
+	if opts.numaNode {
+		entries = append(entries, cfg.Entry{Key: "node", Value: "0"})
+	}
+
+	return []cfg.Section{{
+		Name:    `object "qemu_memory"`,
+		Comment: "Memory hotplug",
+		Entries: []cfg.Entry{
+			{Key: "qom-type", Value: "memory-backend-ram"},
+			{Key: "size", Value: fmt.Sprintf("%dM", opts.sizeMB)},
+		},
+	}, {
+		Name:    `device "dev-qemu_memory"`,
+		Entries: entries,
+	}}
+}
+
 func qemuRNG(opts *qemuDevOpts) []cfg.Section {
 	entriesOpts := qemuDevEntriesOpts{
 		dev:     *opts,
@@ -537,6 +537,89 @@ func (m *Monitor) SetMemoryBalloonSizeBytes(sizeBytes int64) error {
 	return m.Run("balloon", args, nil)
 }
 
+// VirtioMemDevice represents a virtio-mem memory device.
+type VirtioMemDevice struct {
+	ID            string `json:"id"`
+	Size          int64  `json:"size"`
+	RequestedSize int64  `json:"requested-size"`
+	MaxSize       int64  `json:"max-size"`
+	BlockSize     int64  `json:"block-size"`
+}
+
+// GetVirtioMemDevices returns the virtio-mem memory devices.
+func (m *Monitor) GetVirtioMemDevices() ([]VirtioMemDevice, error) {
+	// Prepare the response.
+	var resp struct {
+		Return []struct {
+			Type string          `json:"type"`
+			Data VirtioMemDevice `json:"data"`
+		} `json:"return"`
+	}
+
+	err := m.Run("query-memory-devices", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := []VirtioMemDevice{}
+	sizes := map[string]int64{}
+	for _, device := range resp.Return {
+		if device.Type == "virtio-mem" {
+			devices = append(devices, device.Data)
+			sizes[device.Data.ID] = device.Data.Size
+		}
+	}
+
+	// Refresh the sizes kept up to date through the size change events.
+	m.virtioMemSizesMu.Lock()
+	m.virtioMemSizes = sizes
+	m.virtioMemSizesMu.Unlock()
+
+	return devices, nil
+}
+
+// GetVirtioMemSize returns the total amount of memory in bytes plugged by the guest from virtio-mem devices.
+// The devices are only queried the first time, after which the size is tracked through QEMU's events.
+func (m *Monitor) GetVirtioMemSize() (int64, error) {
+	m.virtioMemSizesMu.Lock()
+	sizes := m.virtioMemSizes
+	m.virtioMemSizesMu.Unlock()
+
+	if sizes == nil {
+		_, err := m.GetVirtioMemDevices()
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	m.virtioMemSizesMu.Lock()
+	defer m.virtioMemSizesMu.Unlock()
+
+	total := int64(0)
+	for _, size := range m.virtioMemSizes {
+		total += size
+	}
+
+	return total, nil
+}
+
+// SetVirtioMemRequestedSize sets the amount of memory in bytes the guest should plug from a virtio-mem device.
+func (m *Monitor) SetVirtioMemRequestedSize(id string, sizeBytes int64) error {
+	args := map[string]any{
+		"path":     "/machine/peripheral/" + id,
+		"property": "requested-size",
+		"value":    sizeBytes,
+	}
+
+	return m.Run("qom-set", args, nil)
+}
+
+// CheckDeviceType checks whether QEMU supports the given device type.
+func (m *Monitor) CheckDeviceType(typeName string) error {
+	args := map[string]string{"typename": typeName}
+	return m.Run("device-list-properties", args, nil)
+}
+
 // AddBlockDevice adds a block device.
 func (m *Monitor) AddBlockDevice(blockDev map[string]any, device map[string]any) error {
 	revert := revert.New()
@@ -35,6 +35,9 @@ var EventVMShutdownReasonDisconnect = "disconnect"
 // EventDiskEjected is used to indicate that a disk device was ejected by the guest.
 var EventDiskEjected = "DEVICE_TRAY_MOVED"
 
+// EventMemoryDeviceSizeChange is used to indicate that the guest changed the size of a virtio-mem device.
+var EventMemoryDeviceSizeChange = "MEMORY_DEVICE_SIZE_CHANGE"
+
 // ExcludedCommands is used to filter verbose commands from the QMP logs.
 var ExcludedCommands = []string{"ringbuf-read"}
 
@@ -45,6 +48,8 @@ type Monitor struct {
 
 	agentStarted      bool
 	agentStartedMu    sync.Mutex
+	virtioMemSizes    map[string]int64
+	virtioMemSizesMu  sync.Mutex
 	disconnected      bool
 	chDisconnect      chan struct{}
 	eventHandler      func(name string, data map[string]any)
@@ -126,6 +131,20 @@ func (m *Monitor) start() error {
 					}
 				}
 
+				// Track the size of virtio-mem devices.
+				if e.Event == EventMemoryDeviceSizeChange {
+					id, okID := e.Data["id"].(string)
+					size, okSize := e.Data["size"].(float64)
+					if okID && okSize {
+						m.virtioMemSizesMu.Lock()
+						if m.virtioMemSizes != nil {
+							m.virtioMemSizes[id] = int64(size)
+						}
+
+						m.virtioMemSizesMu.Unlock()
+					}
+				}
+
 				// Deliver non-empty events to the event handler.
 				if m.eventHandler != nil && e.Event != "" {
 					if e.Event == EventVMShutdown {
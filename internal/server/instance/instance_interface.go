@@ -195,8 +195,10 @@ type VM interface {
 
 	AgentCertificate() *x509.Certificate
 	ConsoleLog() (string, error)
+	FlushConsoleLog() error
 	ConsoleScreenshot(screenshotFile *os.File) error
 	DumpGuestMemory(w *os.File, format string) error
+	FreezeFilesystems(reason string) (func() error, error)
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
@@ -730,6 +730,44 @@ func ValidName(instanceName string, isSnapshot bool) error {
 	return nil
 }
 
+// ValidDependencies validates the startup dependencies (boot.depends_on) of an instance given its expanded config.
+// Dependencies refer to instances of the same project and mustn't lead back to the instance.
+func ValidDependencies(s *state.State, projectName string, instanceName string, expandedConfig map[string]string) error {
+	instDependencies := instance.ParseDependencies(expandedConfig["boot.depends_on"])
+	if len(instDependencies) == 0 {
+		return nil
+	}
+
+	dependencies := map[string][]string{instanceName: instDependencies}
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			if dbInst.Name == instanceName {
+				return nil
+			}
+
+			config := db.ExpandInstanceConfig(dbInst.Config, dbInst.Profiles)
+			dependencies[dbInst.Name] = instance.ParseDependencies(config["boot.depends_on"])
+
+			return nil
+		}, cluster.InstanceFilter{Project: &projectName})
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading instances: %w", err)
+	}
+
+	if slices.Contains(instDependencies, instanceName) {
+		return fmt.Errorf("Instance can't depend on itself")
+	}
+
+	cycle := instance.DependencyCycle(instanceName, dependencies)
+	if cycle != nil {
+		return fmt.Errorf("Dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
 // CreateInternal creates an instance record and storage volume record in the database and sets up devices.
 // Accepts a reverter that revert steps this function does will be added to. It is up to the caller to
 // call the revert's Fail() or Success() function as needed.
@@ -847,6 +885,13 @@ func CreateInternal(s *state.State, args db.InstanceArgs, op *operations.Operati
 		checkedProfiles[profile.Name] = true
 	}
 
+	if !args.Snapshot {
+		err = ValidDependencies(s, args.Project, args.Name, db.ExpandInstanceConfig(args.Config, args.Profiles))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Invalid startup dependencies: %w", err)
+		}
+	}
+
 	if args.CreationDate.IsZero() {
 		args.CreationDate = time.Now().UTC()
 	}
@@ -0,0 +1,72 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// AuthGroupAction represents a lifecycle event action for authorization groups.
+type AuthGroupAction string
+
+// All supported lifecycle events for authorization groups.
+const (
+	AuthGroupCreated = AuthGroupAction(api.EventLifecycleAuthGroupCreated)
+	AuthGroupDeleted = AuthGroupAction(api.EventLifecycleAuthGroupDeleted)
+	AuthGroupRenamed = AuthGroupAction(api.EventLifecycleAuthGroupRenamed)
+	AuthGroupUpdated = AuthGroupAction(api.EventLifecycleAuthGroupUpdated)
+)
+
+// Event creates the lifecycle event for an action on an authorization group.
+func (a AuthGroupAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "auth", "groups", name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
+
+// AuthIdentityAction represents a lifecycle event action for identities.
+type AuthIdentityAction string
+
+// All supported lifecycle events for identities.
+const (
+	AuthIdentityCreated = AuthIdentityAction(api.EventLifecycleAuthIdentityCreated)
+	AuthIdentityDeleted = AuthIdentityAction(api.EventLifecycleAuthIdentityDeleted)
+	AuthIdentityUpdated = AuthIdentityAction(api.EventLifecycleAuthIdentityUpdated)
+)
+
+// Event creates the lifecycle event for an action on an identity.
+func (a AuthIdentityAction) Event(authenticationMethod string, identifier string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "auth", "identities", authenticationMethod, identifier)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
+
+// AuthTokenAction represents a lifecycle event action for authentication tokens.
+type AuthTokenAction string
+
+// All supported lifecycle events for authentication tokens.
+const (
+	AuthTokenCreated = AuthTokenAction(api.EventLifecycleAuthTokenCreated)
+	AuthTokenDeleted = AuthTokenAction(api.EventLifecycleAuthTokenDeleted)
+)
+
+// Event creates the lifecycle event for an action on an authentication token.
+func (a AuthTokenAction) Event(id string, projectName string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "auth", "tokens", id).Project(projectName)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
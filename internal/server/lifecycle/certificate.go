@@ -10,9 +10,11 @@ type CertificateAction string
 
 // All supported lifecycle events for Certificates.
 const (
-	CertificateCreated = CertificateAction(api.EventLifecycleCertificateCreated)
-	CertificateDeleted = CertificateAction(api.EventLifecycleCertificateDeleted)
-	CertificateUpdated = CertificateAction(api.EventLifecycleCertificateUpdated)
+	CertificateCreated  = CertificateAction(api.EventLifecycleCertificateCreated)
+	CertificateDeleted  = CertificateAction(api.EventLifecycleCertificateDeleted)
+	CertificateExpiring = CertificateAction(api.EventLifecycleCertificateExpiring)
+	CertificateRenewed  = CertificateAction(api.EventLifecycleCertificateRenewed)
+	CertificateUpdated  = CertificateAction(api.EventLifecycleCertificateUpdated)
 )
 
 // Event creates the lifecycle event for an action on a Certificate.
@@ -0,0 +1,30 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// InstanceSessionAction represents a lifecycle event action for instance sessions.
+type InstanceSessionAction string
+
+// All supported lifecycle events for instance sessions.
+const (
+	InstanceSessionFinished  = InstanceSessionAction(api.EventLifecycleInstanceSessionFinished)
+	InstanceSessionRetrieved = InstanceSessionAction(api.EventLifecycleInstanceSessionRetrieved)
+	InstanceSessionStarted   = InstanceSessionAction(api.EventLifecycleInstanceSessionStarted)
+)
+
+// Event creates the lifecycle event for an action on an instance session.
+func (a InstanceSessionAction) Event(id string, inst instance, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "instances", inst.Name(), "sessions", id).Project(inst.Project().Name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+		Name:      inst.Name(),
+		Project:   inst.Project().Name,
+	}
+}
@@ -380,6 +380,78 @@ func (c *Client) HandleEvent(event api.Event) {
 	c.entries <- entry
 }
 
+// HandleAuditEntry handles an entry appended to the audit log.
+func (c *Client) HandleAuditEntry(auditEntry api.AuditEntry) {
+	if !slices.Contains(c.cfg.types, "audit") {
+		return
+	}
+
+	location := auditEntry.Location
+	if c.cfg.location != "" {
+		location = c.cfg.location
+	}
+
+	line, err := json.Marshal(auditEntry)
+	if err != nil {
+		return
+	}
+
+	entry := entry{
+		labels: LabelSet{
+			"app":      "incus",
+			"type":     "audit",
+			"location": location,
+			"instance": c.cfg.instance,
+		},
+		Entry: Entry{
+			Timestamp: auditEntry.Timestamp,
+			Line:      string(line),
+		},
+	}
+
+	if auditEntry.Project != "" {
+		entry.labels["project"] = auditEntry.Project
+	}
+
+	// Don't block if the client was stopped in the meantime.
+	select {
+	case c.entries <- entry:
+	case <-c.quit:
+	case <-c.ctx.Done():
+	}
+}
+
+// HandleInstanceLog handles a line read from one of the logs of an instance.
+func (c *Client) HandleInstanceLog(location string, project string, name string, source string, timestamp time.Time, line string) {
+	// Support overriding the location field (used on standalone systems).
+	if c.cfg.location != "" {
+		location = c.cfg.location
+	}
+
+	entry := entry{
+		labels: LabelSet{
+			"app":      "incus",
+			"type":     "instance-log",
+			"location": location,
+			"instance": c.cfg.instance,
+			"project":  project,
+			"name":     name,
+			"source":   source,
+		},
+		Entry: Entry{
+			Timestamp: timestamp,
+			Line:      line,
+		},
+	}
+
+	// Don't block if the client was stopped in the meantime.
+	select {
+	case c.entries <- entry:
+	case <-c.quit:
+	case <-c.ctx.Done():
+	}
+}
+
 func buildNestedContext(prefix string, m map[string]any) map[string]string {
 	labels := map[string]string{}
 
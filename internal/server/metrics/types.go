@@ -44,6 +44,8 @@ const (
 	MemoryCachedBytes
 	// MemoryDirtyBytes represents the amount of memory waiting to get written back to the disk.
 	MemoryDirtyBytes
+	// MemoryHotpluggedBytes represents the amount of memory hotplugged into a virtual machine.
+	MemoryHotpluggedBytes
 	// MemoryHugePagesFreeBytes represents the amount of free memory for hugetlb.
 	MemoryHugePagesFreeBytes
 	// MemoryHugePagesTotalBytes represents the amount of used memory for hugetlb.
@@ -185,6 +187,7 @@ var MetricNames = map[MetricType]string{
 	MemoryActiveBytes:           "incus_memory_Active_bytes",
 	MemoryCachedBytes:           "incus_memory_Cached_bytes",
 	MemoryDirtyBytes:            "incus_memory_Dirty_bytes",
+	MemoryHotpluggedBytes:       "incus_memory_Hotplugged_bytes",
 	MemoryHugePagesFreeBytes:    "incus_memory_HugepagesFree_bytes",
 	MemoryHugePagesTotalBytes:   "incus_memory_HugepagesTotal_bytes",
 	MemoryInactiveAnonBytes:     "incus_memory_Inactive_anon_bytes",
@@ -253,6 +256,7 @@ var MetricHeaders = map[MetricType]string{
 	MemoryActiveBytes:           "# HELP incus_memory_Active_bytes The amount of memory on active LRU list.",
 	MemoryCachedBytes:           "# HELP incus_memory_Cached_bytes The amount of cached memory.",
 	MemoryDirtyBytes:            "# HELP incus_memory_Dirty_bytes The amount of memory waiting to get written back to the disk.",
+	MemoryHotpluggedBytes:       "# HELP incus_memory_Hotplugged_bytes The amount of memory hotplugged into a virtual machine.",
 	MemoryHugePagesFreeBytes:    "# HELP incus_memory_HugepagesFree_bytes The amount of free memory for hugetlb.",
 	MemoryHugePagesTotalBytes:   "# HELP incus_memory_HugepagesTotal_bytes The amount of used memory for hugetlb.",
 	MemoryInactiveAnonBytes:     "# HELP incus_memory_Inactive_anon_bytes The amount of anonymous memory on inactive LRU list.",
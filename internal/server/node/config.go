@@ -115,6 +115,11 @@ func (c *Config) StorageImagesVolume() string {
 	return c.m.GetString("storage.images_volume")
 }
 
+// StorageRecordingsVolume returns the name of the pool/volume to use for storing session recordings.
+func (c *Config) StorageRecordingsVolume() string {
+	return c.m.GetString("storage.recordings_volume")
+}
+
 // SyslogSocket returns true if the syslog socket is enabled, otherwise false.
 func (c *Config) SyslogSocket() bool {
 	return c.m.GetBool("core.syslog_socket")
@@ -257,7 +262,7 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OVS socket path
 	"network.ovs.connection": {Default: "unix:/run/openvswitch/db.sock"},
 
-	// Storage volumes to store backups/images on
+	// Storage volumes to store backups/images/recordings on
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=storage.backups_volume)
 	// Specify the volume using the syntax `POOL/VOLUME`.
@@ -273,4 +278,11 @@ var ConfigSchema = config.Schema{
 	//  scope: local
 	//  shortdesc: Volume to use to store the image tarballs
 	"storage.images_volume": {},
+	// gendoc:generate(entity=server, group=miscellaneous, key=storage.recordings_volume)
+	// Specify the volume using the syntax `POOL/VOLUME`.
+	// ---
+	//  type: string
+	//  scope: local
+	//  shortdesc: Volume to use to store session recordings
+	"storage.recordings_volume": {},
 }
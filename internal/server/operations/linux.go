@@ -5,6 +5,7 @@ package operations
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -12,12 +13,25 @@ import (
 	"github.com/lxc/incus/v6/shared/api"
 )
 
-func registerDBOperation(op *Operation, opType operationtype.Type) error {
+func registerDBOperation(op *Operation, opType operationtype.Type, exclusive bool) error {
 	if op.state == nil {
 		return nil
 	}
 
 	err := op.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		if exclusive {
+			ops, err := cluster.GetOperations(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			for _, existing := range ops {
+				if existing.Type == opType {
+					return api.StatusErrorf(http.StatusConflict, "%s is already in progress", opType.Description())
+				}
+			}
+		}
+
 		opInfo := cluster.Operation{
 			UUID:   op.id,
 			Type:   opType,
@@ -37,6 +51,10 @@ func registerDBOperation(op *Operation, opType operationtype.Type) error {
 		return err
 	})
 	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusConflict) {
+			return err
+		}
+
 		return fmt.Errorf("failed to add %q Operation %s to database: %w", opType.Description(), op.id, err)
 	}
 
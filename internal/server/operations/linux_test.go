@@ -0,0 +1,46 @@
+//go:build linux && cgo && !agent
+
+package operations_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Only a single exclusive operation of a given type can exist at a time.
+func TestOperationCreateExclusive(t *testing.T) {
+	c, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+
+	s := &state.State{
+		DB:          &db.DB{Cluster: c},
+		ShutdownCtx: context.Background(),
+	}
+
+	op1, err := operations.OperationCreateExclusive(s, "", operations.OperationClassTask, operationtype.ClusterRollout, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	_, err = operations.OperationCreateExclusive(s, "", operations.OperationClassTask, operationtype.ClusterRollout, nil, nil, nil, nil, nil, nil)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+
+	_, err = operations.OperationGetInternal(op1.ID())
+	require.NoError(t, err)
+
+	// Operations of other types aren't affected.
+	_, err = operations.OperationCreateExclusive(s, "", operations.OperationClassTask, operationtype.ClusterBootstrap, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	// Non-exclusive operations of the same type are still allowed.
+	_, err = operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterRollout, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/lxc/incus/v6/shared/api"
 )
 
-func registerDBOperation(op *Operation, opType operationtype.Type) error {
+func registerDBOperation(op *Operation, opType operationtype.Type, exclusive bool) error {
 	if op.state != nil {
 		return fmt.Errorf("registerDBOperation not supported on this platform")
 	}
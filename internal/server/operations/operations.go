@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
@@ -15,6 +16,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/cancel"
@@ -104,6 +106,7 @@ type Operation struct {
 	dbOpType    operationtype.Type
 	requestor   *api.EventLifecycleRequestor
 	logger      logger.Logger
+	traceCtx    context.Context
 
 	// Those functions are called at various points in the Operation lifecycle
 	onRun     func(*Operation) error
@@ -123,6 +126,17 @@ type Operation struct {
 // OperationCreate creates a new operation and returns it. If it cannot be
 // created, it returns an error.
 func OperationCreate(s *state.State, projectName string, opClass OperationClass, opType operationtype.Type, opResources map[string][]api.URL, opMetadata any, onRun func(*Operation) error, onCancel func(*Operation) error, onConnect func(*Operation, *http.Request, http.ResponseWriter) error, r *http.Request) (*Operation, error) {
+	return operationCreate(s, projectName, opClass, opType, opResources, opMetadata, onRun, onCancel, onConnect, r, false)
+}
+
+// OperationCreateExclusive creates a new operation like OperationCreate, unless an operation of the same type
+// already exists anywhere in the cluster. The check and the registration of the operation happen in the same
+// database transaction, so concurrent callers can't both succeed.
+func OperationCreateExclusive(s *state.State, projectName string, opClass OperationClass, opType operationtype.Type, opResources map[string][]api.URL, opMetadata any, onRun func(*Operation) error, onCancel func(*Operation) error, onConnect func(*Operation, *http.Request, http.ResponseWriter) error, r *http.Request) (*Operation, error) {
+	return operationCreate(s, projectName, opClass, opType, opResources, opMetadata, onRun, onCancel, onConnect, r, true)
+}
+
+func operationCreate(s *state.State, projectName string, opClass OperationClass, opType operationtype.Type, opResources map[string][]api.URL, opMetadata any, onRun func(*Operation) error, onCancel func(*Operation) error, onConnect func(*Operation, *http.Request, http.ResponseWriter) error, r *http.Request, exclusive bool) (*Operation, error) {
 	// Don't allow new operations when Incus is shutting down.
 	if s != nil && s.ShutdownCtx.Err() == context.Canceled {
 		return nil, fmt.Errorf("Incus is shutting down")
@@ -181,14 +195,21 @@ func OperationCreate(s *state.State, projectName string, opClass OperationClass,
 	// Set requestor if request was provided.
 	if r != nil {
 		op.SetRequestor(r)
+
+		// Keep the request's trace context so the operation is traced as part of it.
+		op.traceCtx = context.WithoutCancel(r.Context())
 	}
 
 	operationsLock.Lock()
 	operations[op.id] = &op
 	operationsLock.Unlock()
 
-	err = registerDBOperation(&op, opType)
+	err = registerDBOperation(&op, opType, exclusive)
 	if err != nil {
+		operationsLock.Lock()
+		delete(operations, op.id)
+		operationsLock.Unlock()
+
 		return nil, err
 	}
 
@@ -217,6 +238,15 @@ func (op *Operation) CopyRequestor(otherOp *Operation) {
 	op.requestor = otherOp.requestor
 }
 
+// TraceContext returns the context carrying the trace span of the operation, so sub-steps can be traced as part of it.
+func (op *Operation) TraceContext() context.Context {
+	if op == nil || op.traceCtx == nil {
+		return context.Background()
+	}
+
+	return op.traceCtx
+}
+
 // Requestor returns the initial requestor for this operation.
 func (op *Operation) Requestor() *api.EventLifecycleRequestor {
 	return op.requestor
@@ -282,8 +312,18 @@ func (op *Operation) Start() error {
 	op.status = api.Running
 
 	if op.onRun != nil {
+		ctx, span := tracing.Start(op.TraceContext(), op.description,
+			attribute.String("incus.operation.id", op.id),
+			attribute.String("incus.operation.class", op.class.String()),
+			attribute.String("incus.project", op.projectName),
+		)
+
+		op.traceCtx = ctx
+
 		go func(op *Operation) {
 			err := op.onRun(op)
+			tracing.End(span, err)
+
 			if err != nil {
 				op.lock.Lock()
 				op.status = api.Failure
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/idmap"
 )
 
@@ -75,3 +76,80 @@ func TestParseHostIDMapRange(t *testing.T) {
 		assert.Equal(t, idmaps, expected)
 	}
 }
+
+func TestDeviceCountLimits(t *testing.T) {
+	instances := []api.Instance{
+		{
+			Name: "c1",
+			InstancePut: api.InstancePut{
+				Devices: map[string]map[string]string{
+					"gpu0": {"type": "gpu", "vendorid": "10de"},
+					"gpu1": {"type": "gpu", "vendorid": "1002"},
+					"eth0": {"type": "nic", "network": "incusbr0"},
+				},
+			},
+		},
+		{
+			Name: "v1",
+			InstancePut: api.InstancePut{
+				Devices: map[string]map[string]string{
+					"gpu0": {"type": "gpu"},
+					"nic0": {"type": "pci", "address": "0000:01:00.0"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, int64(3), getDeviceCount(instances, "limits.devices.gpu"))
+	assert.Equal(t, int64(1), getDeviceCount(instances, "limits.devices.pci"))
+
+	// GPUs without a vendor count against all vendor limits.
+	assert.Equal(t, int64(2), getDeviceCount(instances, "limits.devices.gpu.10de"))
+	assert.Equal(t, int64(2), getDeviceCount(instances, "limits.devices.gpu.1002"))
+	assert.Equal(t, int64(1), getDeviceCount(instances, "limits.devices.gpu.8086"))
+
+	info := &projectInfo{
+		Project: api.Project{
+			Name: "ml",
+			ProjectPut: api.ProjectPut{
+				Config: map[string]string{
+					"limits.devices.gpu":      "3",
+					"limits.devices.gpu.10de": "1",
+				},
+			},
+		},
+		Instances: instances,
+	}
+
+	assert.NoError(t, checkDeviceCountLimits(info, []string{"limits.devices.gpu"}))
+	assert.Error(t, checkDeviceCountLimits(info, []string{"limits.devices.gpu.10de"}))
+
+	assert.NoError(t, validateDeviceCountLimit(instances, "limits.devices.gpu", "3"))
+	assert.Error(t, validateDeviceCountLimit(instances, "limits.devices.gpu", "2"))
+}
+
+func TestValidateNetworkCountLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		config  map[string]string
+		wantErr bool
+	}{
+		{"Default project", "default", map[string]string{"limits.networks.forwards": "2"}, false},
+		{"Own networks", "p1", map[string]string{"features.networks": "true", "limits.network_load_balancers": "2"}, false},
+		{"Forwards without own networks", "p1", map[string]string{"limits.networks.forwards": "2"}, true},
+		{"Load balancers without own networks", "p1", map[string]string{"features.networks": "false", "limits.network_load_balancers": "2"}, true},
+		{"No network limits", "p1", map[string]string{"limits.snapshots": "2"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNetworkCountLimits(&api.Project{Name: tt.project, ProjectPut: api.ProjectPut{Config: tt.config}})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -338,6 +338,7 @@ func checkRestrictionsAndAggregateLimits(tx *db.ClusterTx, info *projectInfo) er
 	// List of config keys for which we need to check aggregate values
 	// across all project instances.
 	aggregateKeys := []string{}
+	deviceKeys := []string{}
 	isRestricted := false
 
 	for key, value := range info.Project.Config {
@@ -346,13 +347,18 @@ func checkRestrictionsAndAggregateLimits(tx *db.ClusterTx, info *projectInfo) er
 			continue
 		}
 
+		if isDeviceCountLimit(key) {
+			deviceKeys = append(deviceKeys, key)
+			continue
+		}
+
 		if key == "restricted" && util.IsTrue(value) {
 			isRestricted = true
 			continue
 		}
 	}
 
-	if len(aggregateKeys) == 0 && !isRestricted {
+	if len(aggregateKeys) == 0 && len(deviceKeys) == 0 && !isRestricted {
 		return nil
 	}
 
@@ -368,6 +374,11 @@ func checkRestrictionsAndAggregateLimits(tx *db.ClusterTx, info *projectInfo) er
 		return err
 	}
 
+	err = checkDeviceCountLimits(info, deviceKeys)
+	if err != nil {
+		return err
+	}
+
 	if isRestricted {
 		err = checkRestrictions(info.Project, info.Instances, info.Profiles)
 		if err != nil {
@@ -451,6 +462,153 @@ func checkAggregateLimits(info *projectInfo, aggregateKeys []string) error {
 	return nil
 }
 
+// allDeviceCountLimits maps the config keys limiting the number of devices of the project instances to the device type.
+var allDeviceCountLimits = map[string]string{
+	"limits.devices.gpu": "gpu",
+	"limits.devices.pci": "pci",
+}
+
+// isDeviceCountLimit returns whether the config key limits the number of devices of the project instances.
+func isDeviceCountLimit(key string) bool {
+	_, ok := allDeviceCountLimits[key]
+
+	return ok || strings.HasPrefix(key, projectLimitGPUVendor)
+}
+
+// getDeviceCount returns the number of devices of the instances counting against the given limit.
+// GPU devices which don't select a vendor count against all the vendor-specific limits.
+func getDeviceCount(instances []api.Instance, key string) int64 {
+	deviceType := allDeviceCountLimits[key]
+	vendorID := ""
+
+	if strings.HasPrefix(key, projectLimitGPUVendor) {
+		deviceType = "gpu"
+		vendorID = strings.TrimPrefix(key, projectLimitGPUVendor)
+	}
+
+	count := int64(0)
+	for _, inst := range instances {
+		for _, device := range inst.Devices {
+			if device["type"] != deviceType {
+				continue
+			}
+
+			if vendorID != "" && device["vendorid"] != "" && !strings.EqualFold(device["vendorid"], vendorID) {
+				continue
+			}
+
+			count++
+		}
+	}
+
+	return count
+}
+
+// getDeviceCountLimit returns the number of devices counting against the given limit along with the limit (-1 if unset).
+func getDeviceCountLimit(info *projectInfo, key string) (int64, int64, error) {
+	count := getDeviceCount(info.Instances, key)
+
+	value, ok := info.Project.Config[key]
+	if !ok {
+		return count, -1, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return -1, -1, fmt.Errorf("Unexpected %q value: %q", key, value)
+	}
+
+	return count, int64(limit), nil
+}
+
+func checkDeviceCountLimits(info *projectInfo, deviceKeys []string) error {
+	for _, key := range deviceKeys {
+		count, limit, err := getDeviceCountLimit(info, key)
+		if err != nil {
+			return err
+		}
+
+		if limit >= 0 && count > limit {
+			return fmt.Errorf("Reached maximum number of devices %q for %q in project %q", info.Project.Config[key], key, info.Project.Name)
+		}
+	}
+
+	return nil
+}
+
+// allCountLimits maps the config keys limiting the number of some project entities to the function counting them.
+var allCountLimits = map[string]func(tx *db.ClusterTx, ctx context.Context, projectName string) (int, error){
+	"limits.network_load_balancers": (*db.ClusterTx).GetProjectNetworkLoadBalancersCount,
+	"limits.networks.forwards":      (*db.ClusterTx).GetProjectNetworkForwardsCount,
+	"limits.snapshots":              (*db.ClusterTx).GetProjectSnapshotsCount,
+}
+
+// ValidateNetworkCountLimits returns an error if a project without its own networks limits the number of network
+// forwards or load balancers, as those would belong to the networks of the default project.
+func ValidateNetworkCountLimits(p *api.Project) error {
+	if NetworkProjectFromRecord(p) == p.Name {
+		return nil
+	}
+
+	for _, key := range []string{"limits.networks.forwards", "limits.network_load_balancers"} {
+		if p.Config[key] != "" {
+			return fmt.Errorf("%q can only be set on projects with features.networks enabled", key)
+		}
+	}
+
+	return nil
+}
+
+// getCountLimit returns the number of entities counting against the given limit along with the limit (-1 if unset).
+func getCountLimit(ctx context.Context, tx *db.ClusterTx, p *api.Project, key string) (int, int, error) {
+	count, err := allCountLimits[key](tx, ctx, p.Name)
+	if err != nil {
+		return -1, -1, fmt.Errorf("Failed counting entities for %q in project %q: %w", key, p.Name, err)
+	}
+
+	value, ok := p.Config[key]
+	if !ok {
+		return count, -1, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return -1, -1, fmt.Errorf("Unexpected %q value: %q", key, value)
+	}
+
+	return count, limit, nil
+}
+
+// checkCountLimit returns an error if creating another entity would exceed the given limit.
+func checkCountLimit(ctx context.Context, tx *db.ClusterTx, p *api.Project, key string, entities string) error {
+	if p.Config[key] == "" {
+		return nil
+	}
+
+	count, limit, err := getCountLimit(ctx, tx, p, key)
+	if err != nil {
+		return err
+	}
+
+	if count >= limit {
+		return fmt.Errorf("Reached maximum number of %s in project %q", entities, p.Name)
+	}
+
+	return nil
+}
+
+// AllowNetworkForwardCreation returns an error if creating a new network forward
+// would exceed the project limits.
+func AllowNetworkForwardCreation(ctx context.Context, tx *db.ClusterTx, p *api.Project) error {
+	return checkCountLimit(ctx, tx, p, "limits.networks.forwards", "network forwards")
+}
+
+// AllowNetworkLoadBalancerCreation returns an error if creating a new network load balancer
+// would exceed the project limits.
+func AllowNetworkLoadBalancerCreation(ctx context.Context, tx *db.ClusterTx, p *api.Project) error {
+	return checkCountLimit(ctx, tx, p, "limits.network_load_balancers", "network load balancers")
+}
+
 // parseHostIDMapRange parse the supplied list of host ID map ranges into a idmap.Entry slice.
 func parseHostIDMapRange(isUID bool, isGID bool, listValue string) ([]idmap.Entry, error) {
 	var idmaps []idmap.Entry
@@ -863,6 +1021,7 @@ var allRestrictions = map[string]string{
 var allowableIntercept = []string{
 	"security.syscalls.intercept.bpf",
 	"security.syscalls.intercept.bpf.devices",
+	"security.syscalls.intercept.keyctl",
 	"security.syscalls.intercept.mknod",
 	"security.syscalls.intercept.mount",
 	"security.syscalls.intercept.mount.fuse",
@@ -1080,6 +1239,31 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 			fallthrough
 		case "limits.disk":
 			aggregateKeys = append(aggregateKeys, key)
+
+		case "limits.network_load_balancers":
+			fallthrough
+		case "limits.networks.forwards":
+			fallthrough
+		case "limits.snapshots":
+			project := api.Project{
+				Name: projectName,
+				ProjectPut: api.ProjectPut{
+					Config: config,
+				},
+			}
+
+			err := validateCountLimit(tx, &project, key)
+			if err != nil {
+				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
+			}
+
+		default:
+			if isDeviceCountLimit(key) {
+				err := validateDeviceCountLimit(info.Instances, key, config[key])
+				if err != nil {
+					return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
+				}
+			}
 		}
 	}
 
@@ -1153,6 +1337,43 @@ func validateInstanceCountLimit(instances []api.Instance, key, value, project st
 	return nil
 }
 
+// Check that a device count limit is equal or above the current number of devices.
+func validateDeviceCountLimit(instances []api.Instance, key, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	count := getDeviceCount(instances, key)
+	if int64(limit) < count {
+		return fmt.Errorf("%q is too low: there currently are %d matching devices", key, count)
+	}
+
+	return nil
+}
+
+// Check that a count limit is equal or above the current number of entities.
+func validateCountLimit(tx *db.ClusterTx, p *api.Project, key string) error {
+	if p.Config[key] == "" {
+		return nil
+	}
+
+	count, limit, err := getCountLimit(context.Background(), tx, p, key)
+	if err != nil {
+		return err
+	}
+
+	if limit < count {
+		return fmt.Errorf("%q is too low: there currently are %d", key, count)
+	}
+
+	return nil
+}
+
 var countConfigInstanceType = map[string]api.InstanceType{
 	"limits.containers":       api.InstanceTypeContainer,
 	"limits.virtual-machines": api.InstanceTypeVM,
@@ -1630,14 +1851,14 @@ func AllowBackupCreation(tx *db.ClusterTx, projectName string) error {
 	return nil
 }
 
-// AllowSnapshotCreation returns an error if any project-specific restriction is violated
+// AllowSnapshotCreation returns an error if any project-specific limit or restriction is violated
 // when creating a new snapshot in a project.
-func AllowSnapshotCreation(p *api.Project) error {
+func AllowSnapshotCreation(ctx context.Context, tx *db.ClusterTx, p *api.Project) error {
 	if projectHasRestriction(p, "restricted.snapshots", "block") {
 		return fmt.Errorf("Project %q doesn't allow for snapshot creation", p.Name)
 	}
 
-	return nil
+	return checkCountLimit(ctx, tx, p, "limits.snapshots", "snapshots")
 }
 
 // GetRestrictedClusterGroups returns a slice of restricted cluster groups for the given project.
@@ -18,6 +18,9 @@ const separator = "_"
 // projectLimitDiskPool is the prefix used for pool-specific disk limits.
 var projectLimitDiskPool = "limits.disk.pool."
 
+// projectLimitGPUVendor is the prefix used for vendor-specific GPU limits.
+var projectLimitGPUVendor = "limits.devices.gpu."
+
 // Instance adds the "<project>_" prefix to instance name when the given project name is not "default".
 func Instance(projectName string, instanceName string) string {
 	if projectName != api.ProjectDefaultName {
@@ -204,6 +207,33 @@ func NetworkProjectFromRecord(p *api.Project) string {
 	return api.ProjectDefaultName
 }
 
+// RestrictionsChanged returns the first restriction or limit key whose value differs between the two project
+// configurations, or an empty string if they all match.
+func RestrictionsChanged(oldConfig map[string]string, newConfig map[string]string) string {
+	keys := make([]string, 0, len(oldConfig)+len(newConfig))
+	for key := range oldConfig {
+		keys = append(keys, key)
+	}
+
+	for key := range newConfig {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "restricted") && !strings.HasPrefix(key, "limits.") {
+			continue
+		}
+
+		if oldConfig[key] != newConfig[key] {
+			return key
+		}
+	}
+
+	return ""
+}
+
 // NetworkAllowed returns whether access is allowed to a particular network based on projectConfig.
 func NetworkAllowed(reqProjectConfig map[string]string, networkName string, isManaged bool) bool {
 	// If project is not restricted, then access to network is allowed.
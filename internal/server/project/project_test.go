@@ -45,3 +45,42 @@ func ExampleStorageVolume() {
 	// Output: default_test
 	// project_name_test1
 }
+
+func ExampleRestrictionsChanged() {
+	current := map[string]string{
+		"restricted":                           "true",
+		"restricted.containers.privilege":      "unprivileged",
+		"limits.instances":                     "5",
+		"user.owner":                           "alice",
+		"features.profiles":                    "true",
+		"restricted.devices.disk.paths":        "",
+		"restricted.virtual-machines.lowlevel": "block",
+	}
+
+	updated := func(key string, value string) map[string]string {
+		config := map[string]string{}
+		for k, v := range current {
+			config[k] = v
+		}
+
+		if value == "" {
+			delete(config, key)
+		} else {
+			config[key] = value
+		}
+
+		return config
+	}
+
+	fmt.Printf("%q\n", project.RestrictionsChanged(current, updated("user.owner", "bob")))
+	fmt.Printf("%q\n", project.RestrictionsChanged(current, updated("restricted", "false")))
+	fmt.Printf("%q\n", project.RestrictionsChanged(current, updated("restricted.containers.privilege", "allow")))
+	fmt.Printf("%q\n", project.RestrictionsChanged(current, updated("limits.instances", "")))
+	fmt.Printf("%q\n", project.RestrictionsChanged(current, updated("restricted.devices.disk.paths", "")))
+
+	// Output: ""
+	// "restricted"
+	// "restricted.containers.privilege"
+	// "limits.instances"
+	// ""
+}
@@ -110,5 +110,42 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 		Usage: int64(len(networks[projectName])),
 	}
 
+	// Get the device limits and usage, including the vendor-specific GPU limits.
+	deviceKeys := []string{}
+	for k := range allDeviceCountLimits {
+		deviceKeys = append(deviceKeys, k)
+	}
+
+	for k := range info.Project.Config {
+		if strings.HasPrefix(k, projectLimitGPUVendor) {
+			deviceKeys = append(deviceKeys, k)
+		}
+	}
+
+	for _, k := range deviceKeys {
+		count, limit, err := getDeviceCountLimit(info, k)
+		if err != nil {
+			return nil, err
+		}
+
+		result[strings.TrimPrefix(k, "limits.")] = api.ProjectStateResource{
+			Limit: limit,
+			Usage: count,
+		}
+	}
+
+	// Get the network forward, network load balancer and snapshot limits and usage.
+	for k := range allCountLimits {
+		count, limit, err := getCountLimit(ctx, tx, &info.Project, k)
+		if err != nil {
+			return nil, err
+		}
+
+		result[strings.TrimPrefix(k, "limits.")] = api.ProjectStateResource{
+			Limit: int64(limit),
+			Usage: int64(count),
+		}
+	}
+
 	return result, nil
 }
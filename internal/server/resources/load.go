@@ -1,13 +1,40 @@
 package resources
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/lxc/incus/v6/shared/api"
 )
 
+var procPressure = "/proc/pressure"
+
+// Network throughput is measured over a window of recent samples rather than since the previous call,
+// so that concurrent and infrequent callers all get a meaningful rate. The samples are taken by a
+// background task calling SampleNetworkLoad every NetworkLoadSampleInterval.
+const (
+	NetworkLoadSampleInterval = 5 * time.Second
+
+	networkLoadWindow      = 10 * time.Second
+	networkLoadMinInterval = time.Second
+	networkLoadMaxAge      = time.Minute
+)
+
+// networkLoadSample is the total traffic of the physical network interfaces at a given time.
+type networkLoadSample struct {
+	bytes uint64
+	time  time.Time
+}
+
+var muNetworkLoad sync.Mutex
+var networkLoadSamples []networkLoadSample
+
 // GetLoad returns the system load information.
 func GetLoad() (*api.ResourcesLoad, error) {
 	loadAvgs, err := getLoadAvgs()
@@ -27,6 +54,21 @@ func GetLoad() (*api.ResourcesLoad, error) {
 		Processes:    processes,
 	}
 
+	// Pressure stall information isn't available on all kernels.
+	pressure, err := getPressure()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	loadAverage.Pressure = pressure
+
+	network, err := getNetworkLoad()
+	if err != nil {
+		return nil, err
+	}
+
+	loadAverage.Network = network
+
 	return &loadAverage, nil
 }
 
@@ -80,3 +122,167 @@ func getProcessCount() (int, error) {
 
 	return total, nil
 }
+
+// getPressure returns the host's pressure stall information from /proc/pressure.
+func getPressure() (*api.ResourcesLoadPressure, error) {
+	pressure := api.ResourcesLoadPressure{}
+
+	for name, value := range map[string]*float64{"cpu": &pressure.CPU, "memory": &pressure.Memory, "io": &pressure.IO} {
+		content, err := os.ReadFile(filepath.Join(procPressure, name))
+		if err != nil {
+			return nil, err
+		}
+
+		// Look for the "some" line and its 60s average.
+		for _, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 || fields[0] != "some" {
+				continue
+			}
+
+			for _, field := range fields[1:] {
+				key, avg, ok := strings.Cut(field, "=")
+				if !ok || key != "avg60" {
+					continue
+				}
+
+				*value, err = strconv.ParseFloat(avg, 64)
+				if err != nil {
+					return nil, fmt.Errorf("Failed parsing %q pressure: %w", name, err)
+				}
+			}
+		}
+	}
+
+	return &pressure, nil
+}
+
+// SampleNetworkLoad records the current traffic of the physical network interfaces.
+func SampleNetworkLoad() error {
+	total, _, err := getNetworkBytes()
+	if err != nil {
+		return err
+	}
+
+	if total == nil {
+		return nil
+	}
+
+	muNetworkLoad.Lock()
+	defer muNetworkLoad.Unlock()
+
+	networkLoadAddSample(networkLoadSample{bytes: *total, time: time.Now()})
+
+	return nil
+}
+
+// networkLoadAddSample records a sample and returns the previous sample to measure the throughput against.
+// That's the most recent sample at least networkLoadWindow old, falling back to any sample at least
+// networkLoadMinInterval old. The caller must hold muNetworkLoad.
+func networkLoadAddSample(current networkLoadSample) (networkLoadSample, bool) {
+	var reference networkLoadSample
+	found := false
+
+	// Forget the samples which are too old to be representative or predate a counter reset.
+	samples := make([]networkLoadSample, 0, len(networkLoadSamples)+1)
+	for _, sample := range networkLoadSamples {
+		age := current.time.Sub(sample.time)
+		if age > networkLoadMaxAge || sample.bytes > current.bytes {
+			continue
+		}
+
+		if age >= networkLoadWindow || (!found && age >= networkLoadMinInterval) {
+			reference = sample
+			found = true
+		}
+
+		samples = append(samples, sample)
+	}
+
+	// Only keep the newest sample old enough to serve as a reference and the ones taken since.
+	for len(samples) > 1 && current.time.Sub(samples[1].time) >= networkLoadWindow {
+		samples = samples[1:]
+	}
+
+	// Samples taken in quick succession don't bring anything.
+	if len(samples) == 0 || current.time.Sub(samples[len(samples)-1].time) >= networkLoadMinInterval {
+		samples = append(samples, current)
+	}
+
+	networkLoadSamples = samples
+
+	return reference, found
+}
+
+// getNetworkLoad returns the throughput of the physical network interfaces over the recent past.
+// Nothing is returned until enough samples were taken to measure it.
+func getNetworkLoad() (*api.ResourcesLoadNetwork, error) {
+	total, capacity, err := getNetworkBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if total == nil {
+		return nil, nil
+	}
+
+	current := networkLoadSample{bytes: *total, time: time.Now()}
+
+	muNetworkLoad.Lock()
+	reference, found := networkLoadAddSample(current)
+	muNetworkLoad.Unlock()
+
+	if !found {
+		return nil, nil
+	}
+
+	network := api.ResourcesLoadNetwork{Capacity: capacity}
+
+	elapsed := current.time.Sub(reference.time).Seconds()
+	if elapsed > 0 {
+		network.Throughput = uint64(float64(current.bytes-reference.bytes) / elapsed)
+	}
+
+	return &network, nil
+}
+
+// getNetworkBytes returns the total traffic and the capacity, in bytes per second, of the physical network interfaces.
+func getNetworkBytes() (*uint64, uint64, error) {
+	entries, err := os.ReadDir(sysClassNet)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, nil
+		}
+
+		return nil, 0, err
+	}
+
+	total := uint64(0)
+	capacity := uint64(0)
+
+	for _, entry := range entries {
+		devPath := filepath.Join(sysClassNet, entry.Name())
+
+		// Only consider physical interfaces.
+		if !sysfsExists(filepath.Join(devPath, "device")) {
+			continue
+		}
+
+		for _, counter := range []string{"rx_bytes", "tx_bytes"} {
+			value, err := readUint(filepath.Join(devPath, "statistics", counter))
+			if err != nil {
+				continue
+			}
+
+			total += value
+		}
+
+		// The link speed is reported in Mbit/s and isn't available on interfaces that are down.
+		speed, err := readInt(filepath.Join(devPath, "speed"))
+		if err == nil && speed > 0 {
+			capacity += uint64(speed) * 1000 * 1000 / 8
+		}
+	}
+
+	return &total, capacity, nil
+}
@@ -0,0 +1,119 @@
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestGetPressure(t *testing.T) {
+	defer func(path string) { procPressure = path }(procPressure)
+
+	procPressure = t.TempDir()
+
+	writePressure := func(name string, content string) {
+		err := os.WriteFile(filepath.Join(procPressure, name), []byte(content), 0o644)
+		require.NoError(t, err)
+	}
+
+	writePressure("cpu", `some avg10=1.50 avg60=2.25 avg300=3.00 total=12345
+full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+`)
+	writePressure("memory", `some avg10=0.10 avg60=0.20 avg300=0.30 total=42
+full avg10=5.00 avg60=6.00 avg300=7.00 total=42
+`)
+
+	// Missing pressure files are reported as such.
+	_, err := getPressure()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	writePressure("io", `full avg10=9.00 avg60=9.00 avg300=9.00 total=1
+some avg10=0.00 avg60=12.50 avg300=0.00 total=1
+`)
+
+	pressure, err := getPressure()
+	require.NoError(t, err)
+	assert.Equal(t, &api.ResourcesLoadPressure{CPU: 2.25, Memory: 0.2, IO: 12.5}, pressure)
+
+	writePressure("io", "some avg10=0.00 avg60=invalid avg300=0.00 total=1\n")
+
+	_, err = getPressure()
+	assert.Error(t, err)
+}
+
+func TestNetworkLoadAddSample(t *testing.T) {
+	defer func(samples []networkLoadSample) { networkLoadSamples = samples }(networkLoadSamples)
+
+	start := time.Now()
+	at := func(offset time.Duration, bytes uint64) networkLoadSample {
+		return networkLoadSample{bytes: bytes, time: start.Add(offset)}
+	}
+
+	tests := []struct {
+		name      string
+		samples   []networkLoadSample
+		current   networkLoadSample
+		reference *networkLoadSample
+		remaining []networkLoadSample
+	}{
+		{
+			name:      "No previous sample",
+			current:   at(0, 100),
+			remaining: []networkLoadSample{at(0, 100)},
+		},
+		{
+			name:      "Samples too recent",
+			samples:   []networkLoadSample{at(0, 100)},
+			current:   at(500*time.Millisecond, 200),
+			remaining: []networkLoadSample{at(0, 100)},
+		},
+		{
+			name:      "Short interval",
+			samples:   []networkLoadSample{at(0, 100), at(2*time.Second, 200)},
+			current:   at(4*time.Second, 300),
+			reference: &networkLoadSample{bytes: 100, time: start},
+			remaining: []networkLoadSample{at(0, 100), at(2*time.Second, 200), at(4*time.Second, 300)},
+		},
+		{
+			name:      "Full window",
+			samples:   []networkLoadSample{at(0, 100), at(5*time.Second, 200), at(10*time.Second, 300), at(15*time.Second, 400)},
+			current:   at(21*time.Second, 500),
+			reference: &networkLoadSample{bytes: 300, time: start.Add(10 * time.Second)},
+			remaining: []networkLoadSample{at(10*time.Second, 300), at(15*time.Second, 400), at(21*time.Second, 500)},
+		},
+		{
+			name:      "Expired samples",
+			samples:   []networkLoadSample{at(0, 100)},
+			current:   at(2*time.Minute, 200),
+			remaining: []networkLoadSample{at(2*time.Minute, 200)},
+		},
+		{
+			name:      "Counter reset",
+			samples:   []networkLoadSample{at(0, 1000), at(5*time.Second, 2000)},
+			current:   at(10*time.Second, 100),
+			remaining: []networkLoadSample{at(10*time.Second, 100)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networkLoadSamples = tt.samples
+
+			reference, found := networkLoadAddSample(tt.current)
+			if tt.reference == nil {
+				assert.False(t, found)
+			} else {
+				assert.True(t, found)
+				assert.Equal(t, *tt.reference, reference)
+			}
+
+			assert.Equal(t, tt.remaining, networkLoadSamples)
+		})
+	}
+}
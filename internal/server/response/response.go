@@ -3,6 +3,7 @@ package response
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,7 +12,10 @@ import (
 	"os"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -533,8 +537,11 @@ func (r *forwardedResponse) Render(w http.ResponseWriter) error {
 		return err
 	}
 
+	ctx, span := tracing.Start(r.request.Context(), "Forward request", attribute.String("server.address", info.Addresses[0]))
+	defer span.End()
+
 	url := fmt.Sprintf("%s%s", info.Addresses[0], r.request.URL.RequestURI())
-	forwarded, err := http.NewRequest(r.request.Method, url, r.request.Body)
+	forwarded, err := http.NewRequestWithContext(context.WithoutCancel(ctx), r.request.Method, url, r.request.Body)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,91 @@
+package admission
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.starlark.net/starlark"
+
+	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/log"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/marshal"
+	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// AdmissionRun runs the admission scriptlet against a requested object.
+// It returns the configuration the object should be given instead of the requested one, or nil to leave it
+// unchanged. A scriptlet rejecting the request results in a forbidden status error holding its message.
+func AdmissionRun(l logger.Logger, req *apiScriptlet.AdmissionRequest) (map[string]string, error) {
+	logFunc := log.CreateLogger(l, "Admission scriptlet")
+
+	// Remember to match the entries in scriptletLoad.AdmissionCompile() with this list so Starlark can
+	// perform compile time validation of functions used.
+	env := starlark.StringDict{
+		"log_info":  starlark.NewBuiltin("log_info", logFunc),
+		"log_warn":  starlark.NewBuiltin("log_warn", logFunc),
+		"log_error": starlark.NewBuiltin("log_error", logFunc),
+	}
+
+	prog, thread, err := scriptletLoad.AdmissionProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	globals, err := prog.Init(thread, env)
+	if err != nil {
+		return nil, fmt.Errorf("Failed initializing: %w", err)
+	}
+
+	globals.Freeze()
+
+	// Retrieve a global variable from starlark environment.
+	admit := globals["admit"]
+	if admit == nil {
+		return nil, fmt.Errorf("Scriptlet missing admit function")
+	}
+
+	reqv, err := marshal.StarlarkMarshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("Marshalling request failed: %w", err)
+	}
+
+	// Call starlark function from Go.
+	v, err := starlark.Call(thread, admit, nil, []starlark.Tuple{
+		{
+			starlark.String("request"),
+			reqv,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to run: %w", err)
+	}
+
+	switch rv := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.String:
+		return nil, api.StatusErrorf(http.StatusForbidden, "Rejected by admission scriptlet: %s", rv.GoString())
+	case *starlark.Dict:
+		value, err := marshal.StarlarkUnmarshal(rv)
+		if err != nil {
+			return nil, err
+		}
+
+		values, _ := value.(map[string]any)
+		config := make(map[string]string, len(values))
+		for key, value := range values {
+			configValue, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("Failed with unexpected value for configuration key %q: %v", key, value)
+			}
+
+			config[key] = configValue
+		}
+
+		return config, nil
+	}
+
+	return nil, fmt.Errorf("Failed with unexpected return value: %v", v)
+}
@@ -0,0 +1,62 @@
+package admission
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+const admissionTestScriptlet = `
+def admit(request):
+    config = request.object.config
+
+    if "raw.lxc" in config:
+        return "raw.lxc isn't allowed for " + request.identity.username
+
+    if request.type == "instance" and request.action == "create" and "limits.cpu" not in config:
+        config["limits.cpu"] = "1"
+        return config
+
+    return None
+`
+
+func TestAdmissionRun(t *testing.T) {
+	require.Error(t, scriptletLoad.AdmissionValidate("def admit(object):\n    return None\n"))
+	require.NoError(t, scriptletLoad.AdmissionValidate(admissionTestScriptlet))
+	require.NoError(t, scriptletLoad.AdmissionSet(admissionTestScriptlet))
+	defer func() { _ = scriptletLoad.AdmissionSet("") }()
+
+	newRequest := func(action string, config map[string]string) *apiScriptlet.AdmissionRequest {
+		return &apiScriptlet.AdmissionRequest{
+			Action:   action,
+			Type:     "instance",
+			Project:  api.ProjectDefaultName,
+			Name:     "c1",
+			Object:   &api.InstancesPost{InstancePut: api.InstancePut{Config: config}},
+			Identity: &api.EventLifecycleRequestor{Username: "user1", Protocol: "tls"},
+		}
+	}
+
+	// The configuration is patched.
+	config, err := AdmissionRun(logger.Log, newRequest(apiScriptlet.AdmissionActionCreate, map[string]string{"user.foo": "bar"}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user.foo": "bar", "limits.cpu": "1"}, config)
+
+	// The request is admitted as is.
+	config, err = AdmissionRun(logger.Log, newRequest(apiScriptlet.AdmissionActionUpdate, map[string]string{"user.foo": "bar"}))
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	// The request is rejected.
+	_, err = AdmissionRun(logger.Log, newRequest(apiScriptlet.AdmissionActionUpdate, map[string]string{"raw.lxc": "lxc.init.cmd = /bin/sh"}))
+	require.Error(t, err)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+	assert.Contains(t, err.Error(), "raw.lxc isn't allowed for user1")
+}
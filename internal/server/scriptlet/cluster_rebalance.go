@@ -0,0 +1,92 @@
+package scriptlet
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/log"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/marshal"
+	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// ClusterRebalanceScoreRun runs the cluster re-balancing scriptlet and returns the score (0-100) of a cluster member.
+func ClusterRebalanceScoreRun(l logger.Logger, member string, res *api.Resources, usage *apiScriptlet.ClusterRebalanceUsage) (uint8, error) {
+	logFunc := log.CreateLogger(l, "Cluster re-balancing scriptlet")
+
+	// Remember to match the entries in scriptletLoad.ClusterRebalanceCompile() with this list so Starlark can
+	// perform compile time validation of functions used.
+	env := starlark.StringDict{
+		"log_info":  starlark.NewBuiltin("log_info", logFunc),
+		"log_warn":  starlark.NewBuiltin("log_warn", logFunc),
+		"log_error": starlark.NewBuiltin("log_error", logFunc),
+	}
+
+	prog, thread, err := scriptletLoad.ClusterRebalanceProgram()
+	if err != nil {
+		return 0, err
+	}
+
+	globals, err := prog.Init(thread, env)
+	if err != nil {
+		return 0, fmt.Errorf("Failed initializing: %w", err)
+	}
+
+	globals.Freeze()
+
+	// Retrieve a global variable from starlark environment.
+	rebalanceScore := globals["rebalance_score"]
+	if rebalanceScore == nil {
+		return 0, fmt.Errorf("Scriptlet missing rebalance_score function")
+	}
+
+	resv, err := marshal.StarlarkMarshal(res)
+	if err != nil {
+		return 0, fmt.Errorf("Marshalling resources failed: %w", err)
+	}
+
+	usagev, err := marshal.StarlarkMarshal(usage)
+	if err != nil {
+		return 0, fmt.Errorf("Marshalling usage failed: %w", err)
+	}
+
+	// Call starlark function from Go.
+	v, err := starlark.Call(thread, rebalanceScore, nil, []starlark.Tuple{
+		{
+			starlark.String("member"),
+			starlark.String(member),
+		}, {
+			starlark.String("resources"),
+			resv,
+		}, {
+			starlark.String("usage"),
+			usagev,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Failed to run: %w", err)
+	}
+
+	var score int
+	switch value := v.(type) {
+	case starlark.Int:
+		err = starlark.AsInt(value, &score)
+		if err != nil {
+			return 0, fmt.Errorf("Failed with invalid score %v: %w", v, err)
+		}
+
+	case starlark.Float:
+		score = int(value)
+	default:
+		return 0, fmt.Errorf("Failed with unexpected return value: %v", v)
+	}
+
+	if score < 0 || score > 100 {
+		return 0, fmt.Errorf("Failed with out of range score: %d", score)
+	}
+
+	return uint8(score), nil
+}
@@ -9,12 +9,18 @@ import (
 // nameInstancePlacement is the name used in Starlark for the instance placement scriptlet.
 const nameInstancePlacement = "instance_placement"
 
+// nameClusterRebalance is the name used in Starlark for the cluster re-balancing scriptlet.
+const nameClusterRebalance = "cluster_rebalance"
+
 // prefixQEMU is the prefix used in Starlark for the QEMU scriptlet.
 const prefixQEMU = "qemu"
 
 // nameAuthorization is the name used in Starlark for the Authorization scriptlet.
 const nameAuthorization = "authorization"
 
+// nameAdmission is the name used in Starlark for the admission scriptlet.
+const nameAdmission = "admission"
+
 var programsMu sync.Mutex
 var programs = make(map[string]*starlark.Program)
 
@@ -53,6 +59,33 @@ func InstancePlacementProgram() (*starlark.Program, *starlark.Thread, error) {
 	return program("Instance placement", nameInstancePlacement)
 }
 
+// ClusterRebalanceCompile compiles the cluster re-balancing scriptlet.
+func ClusterRebalanceCompile(name string, src string) (*starlark.Program, error) {
+	return compile(name, src, []string{
+		"log_info",
+		"log_warn",
+		"log_error",
+	})
+}
+
+// ClusterRebalanceValidate validates the cluster re-balancing scriptlet.
+func ClusterRebalanceValidate(src string) error {
+	return validate(ClusterRebalanceCompile, nameClusterRebalance, src, declaration{
+		required("rebalance_score"): {"member", "resources", "usage"},
+	})
+}
+
+// ClusterRebalanceSet compiles the cluster re-balancing scriptlet into memory for use with ClusterRebalanceScoreRun.
+// If empty src is provided the current program is deleted.
+func ClusterRebalanceSet(src string) error {
+	return set(ClusterRebalanceCompile, nameClusterRebalance, src)
+}
+
+// ClusterRebalanceProgram returns the precompiled cluster re-balancing scriptlet program.
+func ClusterRebalanceProgram() (*starlark.Program, *starlark.Thread, error) {
+	return program("Cluster re-balancing", nameClusterRebalance)
+}
+
 // QEMUCompile compiles the QEMU scriptlet.
 func QEMUCompile(name string, src string) (*starlark.Program, error) {
 	return compile(name, src, []string{
@@ -130,3 +163,30 @@ func AuthorizationSet(src string) error {
 func AuthorizationProgram() (*starlark.Program, *starlark.Thread, error) {
 	return program("Authorization", nameAuthorization)
 }
+
+// AdmissionCompile compiles the admission scriptlet.
+func AdmissionCompile(name string, src string) (*starlark.Program, error) {
+	return compile(name, src, []string{
+		"log_info",
+		"log_warn",
+		"log_error",
+	})
+}
+
+// AdmissionValidate validates the admission scriptlet.
+func AdmissionValidate(src string) error {
+	return validate(AdmissionCompile, nameAdmission, src, declaration{
+		required("admit"): {"request"},
+	})
+}
+
+// AdmissionSet compiles the admission scriptlet into memory for use with AdmissionRun.
+// If empty src is provided the current program is deleted.
+func AdmissionSet(src string) error {
+	return set(AdmissionCompile, nameAdmission, src)
+}
+
+// AdmissionProgram returns the precompiled admission scriptlet program.
+func AdmissionProgram() (*starlark.Program, *starlark.Thread, error) {
+	return program("Admission", nameAdmission)
+}
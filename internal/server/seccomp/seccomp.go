@@ -99,6 +99,10 @@ struct incus_seccomp_data_arch {
 	int nr_bpf;
 	int nr_sched_setscheduler;
 	int nr_sysinfo;
+	int nr_fsopen;
+	int nr_fsconfig;
+	int nr_fsmount;
+	int nr_keyctl;
 };
 
 #define INCUS_SECCOMP_NOTIFY_MKNOD    0
@@ -108,69 +112,73 @@ struct incus_seccomp_data_arch {
 #define INCUS_SECCOMP_NOTIFY_BPF 4
 #define INCUS_SECCOMP_NOTIFY_SCHED_SETSCHEDULER 5
 #define INCUS_SECCOMP_NOTIFY_SYSINFO 6
+#define INCUS_SECCOMP_NOTIFY_FSOPEN 7
+#define INCUS_SECCOMP_NOTIFY_FSCONFIG 8
+#define INCUS_SECCOMP_NOTIFY_FSMOUNT 9
+#define INCUS_SECCOMP_NOTIFY_KEYCTL 10
 
 // ordered by likelihood of usage...
 static const struct incus_seccomp_data_arch seccomp_notify_syscall_table[] = {
-	{ -1, INCUS_SECCOMP_NOTIFY_MKNOD, INCUS_SECCOMP_NOTIFY_MKNODAT, INCUS_SECCOMP_NOTIFY_SETXATTR, INCUS_SECCOMP_NOTIFY_MOUNT, INCUS_SECCOMP_NOTIFY_BPF, INCUS_SECCOMP_NOTIFY_SCHED_SETSCHEDULER, INCUS_SECCOMP_NOTIFY_SYSINFO},
+	{ -1, INCUS_SECCOMP_NOTIFY_MKNOD, INCUS_SECCOMP_NOTIFY_MKNODAT, INCUS_SECCOMP_NOTIFY_SETXATTR, INCUS_SECCOMP_NOTIFY_MOUNT, INCUS_SECCOMP_NOTIFY_BPF, INCUS_SECCOMP_NOTIFY_SCHED_SETSCHEDULER, INCUS_SECCOMP_NOTIFY_SYSINFO, INCUS_SECCOMP_NOTIFY_FSOPEN, INCUS_SECCOMP_NOTIFY_FSCONFIG, INCUS_SECCOMP_NOTIFY_FSMOUNT, INCUS_SECCOMP_NOTIFY_KEYCTL },
 #ifdef AUDIT_ARCH_X86_64
-	{ AUDIT_ARCH_X86_64,      133, 259, 188, 165, 321, 144, 99 },
+	{ AUDIT_ARCH_X86_64,      133, 259, 188, 165, 321, 144, 99, 430, 431, 432, 250 },
 #endif
 #ifdef AUDIT_ARCH_I386
-	{ AUDIT_ARCH_I386,         14, 297, 226,  21, 357, 156, 116 },
+	{ AUDIT_ARCH_I386,         14, 297, 226,  21, 357, 156, 116, 430, 431, 432, 288 },
 #endif
 #ifdef AUDIT_ARCH_AARCH64
-	{ AUDIT_ARCH_AARCH64,      -1,  33,   5,  40, 280, 119, 179 },
+	{ AUDIT_ARCH_AARCH64,      -1,  33,   5,  40, 280, 119, 179, 430, 431, 432, 219 },
 #endif
 #ifdef AUDIT_ARCH_ARM
-	{ AUDIT_ARCH_ARM,          14, 324, 226,  21, 386, 156, 116 },
+	{ AUDIT_ARCH_ARM,          14, 324, 226,  21, 386, 156, 116, 430, 431, 432, 311 },
 #endif
 #ifdef AUDIT_ARCH_ARMEB
-	{ AUDIT_ARCH_ARMEB,        14, 324, 226,  21, 386, 156, 116 },
+	{ AUDIT_ARCH_ARMEB,        14, 324, 226,  21, 386, 156, 116, 430, 431, 432, 311 },
 #endif
 #ifdef AUDIT_ARCH_S390
-	{ AUDIT_ARCH_S390,         14, 290, 224,  21, 351, 156, 116 },
+	{ AUDIT_ARCH_S390,         14, 290, 224,  21, 351, 156, 116, 430, 431, 432, 280 },
 #endif
 #ifdef AUDIT_ARCH_S390X
-	{ AUDIT_ARCH_S390X,        14, 290, 224,  21, 351, 156, 116 },
+	{ AUDIT_ARCH_S390X,        14, 290, 224,  21, 351, 156, 116, 430, 431, 432, 280 },
 #endif
 #ifdef AUDIT_ARCH_PPC
-	{ AUDIT_ARCH_PPC,          14, 288, 209,  21, 361, 156, 116 },
+	{ AUDIT_ARCH_PPC,          14, 288, 209,  21, 361, 156, 116, 430, 431, 432, 271 },
 #endif
 #ifdef AUDIT_ARCH_PPC64
-	{ AUDIT_ARCH_PPC64,        14, 288, 209,  21, 361, 156, 116 },
+	{ AUDIT_ARCH_PPC64,        14, 288, 209,  21, 361, 156, 116, 430, 431, 432, 271 },
 #endif
 #ifdef AUDIT_ARCH_PPC64LE
-	{ AUDIT_ARCH_PPC64LE,      14, 288, 209,  21, 361, 156, 116 },
+	{ AUDIT_ARCH_PPC64LE,      14, 288, 209,  21, 361, 156, 116, 430, 431, 432, 271 },
 #endif
 #ifdef AUDIT_ARCH_RISCV64
-	{ AUDIT_ARCH_RISCV64,      -1,  33,   5,  40, 280, 119, 179 },
+	{ AUDIT_ARCH_RISCV64,      -1,  33,   5,  40, 280, 119, 179, 430, 431, 432, 219 },
 #endif
 #ifdef AUDIT_ARCH_SPARC
-	{ AUDIT_ARCH_SPARC,        14, 286, 169, 167, 349, 243, 214 },
+	{ AUDIT_ARCH_SPARC,        14, 286, 169, 167, 349, 243, 214, 430, 431, 432, 283 },
 #endif
 #ifdef AUDIT_ARCH_SPARC64
-	{ AUDIT_ARCH_SPARC64,      14, 286, 169, 167, 349, 243, 214 },
+	{ AUDIT_ARCH_SPARC64,      14, 286, 169, 167, 349, 243, 214, 430, 431, 432, 283 },
 #endif
 #ifdef AUDIT_ARCH_MIPS
-	{ AUDIT_ARCH_MIPS,         14, 290, 224,  21,  -1, 141, 4116 },
+	{ AUDIT_ARCH_MIPS,         14, 290, 224,  21,  -1, 141, 4116, 4430, 4431, 4432, 4282 },
 #endif
 #ifdef AUDIT_ARCH_MIPSEL
-	{ AUDIT_ARCH_MIPSEL,       14, 290, 224,  21,  -1, 141, 4116 },
+	{ AUDIT_ARCH_MIPSEL,       14, 290, 224,  21,  -1, 141, 4116, 4430, 4431, 4432, 4282 },
 #endif
 #ifdef AUDIT_ARCH_MIPS64
-	{ AUDIT_ARCH_MIPS64,      131, 249, 180, 160,  -1, 141, 5097 },
+	{ AUDIT_ARCH_MIPS64,      131, 249, 180, 160,  -1, 141, 5097, 5430, 5431, 5432, 5241 },
 #endif
 #ifdef AUDIT_ARCH_MIPS64N32
-	{ AUDIT_ARCH_MIPS64N32,   131, 253, 180, 160,  -1, 141, 4116 },
+	{ AUDIT_ARCH_MIPS64N32,   131, 253, 180, 160,  -1, 141, 4116, 6430, 6431, 6432, 6245 },
 #endif
 #ifdef AUDIT_ARCH_MIPSEL64
-	{ AUDIT_ARCH_MIPSEL64,    131, 249, 180, 160,  -1, 141, 5097 },
+	{ AUDIT_ARCH_MIPSEL64,    131, 249, 180, 160,  -1, 141, 5097, 5430, 5431, 5432, 5241 },
 #endif
 #ifdef AUDIT_ARCH_MIPSEL64N32
-	{ AUDIT_ARCH_MIPSEL64N32, 131, 253, 180, 160,  -1, 141, 4116 },
+	{ AUDIT_ARCH_MIPSEL64N32, 131, 253, 180, 160,  -1, 141, 4116, 6430, 6431, 6432, 6245 },
 #endif
 #ifdef AUDIT_ARCH_LOONGARCH64
-	{ AUDIT_ARCH_LOONGARCH64, -1,  33,   5,  40, 280, 119, 179 },
+	{ AUDIT_ARCH_LOONGARCH64, -1,  33,   5,  40, 280, 119, 179, 430, 431, 432, 219 },
 #endif
 };
 
@@ -211,6 +219,18 @@ static int seccomp_notify_get_syscall(struct seccomp_notif *req,
 		if (entry->nr_sysinfo == req->data.nr)
 			return INCUS_SECCOMP_NOTIFY_SYSINFO;
 
+		if (entry->nr_fsopen == req->data.nr)
+			return INCUS_SECCOMP_NOTIFY_FSOPEN;
+
+		if (entry->nr_fsconfig == req->data.nr)
+			return INCUS_SECCOMP_NOTIFY_FSCONFIG;
+
+		if (entry->nr_fsmount == req->data.nr)
+			return INCUS_SECCOMP_NOTIFY_FSMOUNT;
+
+		if (entry->nr_keyctl == req->data.nr)
+			return INCUS_SECCOMP_NOTIFY_KEYCTL;
+
 		break;
 	}
 
@@ -461,6 +481,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -491,6 +512,10 @@ const incusSeccompNotifyMount = C.INCUS_SECCOMP_NOTIFY_MOUNT
 const incusSeccompNotifyBpf = C.INCUS_SECCOMP_NOTIFY_BPF
 const incusSeccompNotifySchedSetscheduler = C.INCUS_SECCOMP_NOTIFY_SCHED_SETSCHEDULER
 const incusSeccompNotifySysinfo = C.INCUS_SECCOMP_NOTIFY_SYSINFO
+const incusSeccompNotifyFsopen = C.INCUS_SECCOMP_NOTIFY_FSOPEN
+const incusSeccompNotifyFsconfig = C.INCUS_SECCOMP_NOTIFY_FSCONFIG
+const incusSeccompNotifyFsmount = C.INCUS_SECCOMP_NOTIFY_FSMOUNT
+const incusSeccompNotifyKeyctl = C.INCUS_SECCOMP_NOTIFY_KEYCTL
 
 const seccompHeader = `2
 `
@@ -532,9 +557,26 @@ fsmount errno 38
 fspick errno 38
 open_tree errno 38
 move_mount errno 38
+mount_setattr errno 38
 openat2 errno 38
 `
 
+// The new mount API is intercepted when creating file system contexts and superblocks.
+// open_tree, move_mount and mount_setattr only operate on existing mounts and are
+// left to the kernel just like bind mounts, moves and remounts are.
+//
+// 6 == FSCONFIG_CMD_CREATE
+const seccompNotifyNewMountAPI = `fsopen notify
+fsconfig notify [1,6,SCMP_CMP_EQ]
+`
+
+const seccompNotifyFsmount = `fsmount notify
+`
+
+// 1 == KEYCTL_JOIN_SESSION_KEYRING
+const seccompNotifyKeyctl = `keyctl notify [0,1,SCMP_CMP_EQ]
+`
+
 // We don't want to filter any of the following flag combinations since they do
 // not cause the creation of a new superblock:
 //
@@ -665,6 +707,7 @@ func InstanceNeedsPolicy(c Instance) bool {
 		"security.syscalls.intercept.sysinfo",
 		"security.syscalls.intercept.mount",
 		"security.syscalls.intercept.bpf",
+		"security.syscalls.intercept.keyctl",
 	}
 
 	for _, k := range keys {
@@ -707,6 +750,7 @@ func InstanceNeedsIntercept(s *state.State, c Instance) (bool, error) {
 		"security.syscalls.intercept.sysinfo":            lxcSupportSeccompNotify,
 		"security.syscalls.intercept.mount":              lxcSupportSeccompNotifyContinue,
 		"security.syscalls.intercept.bpf":                lxcSupportSeccompNotifyAddfd,
+		"security.syscalls.intercept.keyctl":             lxcSupportSeccompNotifyContinue,
 	}
 
 	needed := false
@@ -740,6 +784,59 @@ func MakePidFd(pid int, s *state.State) (int, *os.File) {
 	return -1, nil
 }
 
+// seccompInterceptPolicy returns the notify rules for the syscalls that the
+// instance configuration asks to intercept.
+func seccompInterceptPolicy(s *state.State, config map[string]string) string {
+	// Prevent the container from overriding our syscall
+	// supervision.
+	policy := seccompNotifyDisallow
+
+	if util.IsTrue(config["security.syscalls.intercept.mknod"]) {
+		policy += seccompNotifyMknod
+	}
+
+	if util.IsTrue(config["security.syscalls.intercept.sched_setscheduler"]) {
+		policy += seccompNotifySchedSetscheduler
+	}
+
+	if util.IsTrue(config["security.syscalls.intercept.setxattr"]) {
+		policy += seccompNotifySetxattr
+	}
+
+	if util.IsTrue(config["security.syscalls.intercept.sysinfo"]) {
+		policy += seccompNotifySysinfo
+	}
+
+	if util.IsTrue(config["security.syscalls.intercept.mount"]) {
+		policy += seccompNotifyMount
+
+		if s.OS.SeccompListenerAddfd {
+			// File system contexts are created on behalf of the
+			// container and handed over to it.
+			policy += seccompNotifyNewMountAPI
+
+			if util.IsTrue(config["security.syscalls.intercept.mount.shift"]) {
+				policy += seccompNotifyFsmount
+			}
+		} else {
+			// Without a way to hand over file descriptors, we
+			// block the new mount api as it keeps state over
+			// multiple syscalls.
+			policy += seccompBlockNewMountAPI
+		}
+	}
+
+	if util.IsTrue(config["security.syscalls.intercept.bpf"]) {
+		policy += seccompNotifyBpf
+	}
+
+	if util.IsTrue(config["security.syscalls.intercept.keyctl"]) {
+		policy += seccompNotifyKeyctl
+	}
+
+	return policy
+}
+
 func seccompGetPolicyContent(s *state.State, c Instance) (string, error) {
 	config := c.ExpandedConfig()
 
@@ -788,38 +885,7 @@ func seccompGetPolicyContent(s *state.State, c Instance) (string, error) {
 	}
 
 	if ok {
-		// Prevent the container from overriding our syscall
-		// supervision.
-		policy += seccompNotifyDisallow
-
-		if util.IsTrue(config["security.syscalls.intercept.mknod"]) {
-			policy += seccompNotifyMknod
-		}
-
-		if util.IsTrue(config["security.syscalls.intercept.sched_setscheduler"]) {
-			policy += seccompNotifySchedSetscheduler
-		}
-
-		if util.IsTrue(config["security.syscalls.intercept.setxattr"]) {
-			policy += seccompNotifySetxattr
-		}
-
-		if util.IsTrue(config["security.syscalls.intercept.sysinfo"]) {
-			policy += seccompNotifySysinfo
-		}
-
-		if util.IsTrue(config["security.syscalls.intercept.mount"]) {
-			policy += seccompNotifyMount
-			// We block the new mount api for now to simplify mount
-			// syscall interception. Since it keeps state over
-			// multiple syscalls we'd need more invasive changes to
-			// make this work.
-			policy += seccompBlockNewMountAPI
-		}
-
-		if util.IsTrue(config["security.syscalls.intercept.bpf"]) {
-			policy += seccompNotifyBpf
-		}
+		policy += seccompInterceptPolicy(s, config)
 	}
 
 	if allowlist != "" {
@@ -891,6 +957,10 @@ type Server struct {
 	s    *state.State
 	path string
 	l    net.Listener
+
+	// File system contexts created through the new mount API on behalf of instances.
+	fsContexts   map[string][]*fsContext
+	fsContextsMu sync.Mutex
 }
 
 // Iovec defines an iovec to move data between kernel and userspace.
@@ -1070,9 +1140,10 @@ func NewSeccompServer(s *state.State, path string, findPID func(pid int32, state
 
 	// Start the server
 	server := Server{
-		s:    s,
-		path: path,
-		l:    l,
+		s:          s,
+		path:       path,
+		l:          l,
+		fsContexts: map[string][]*fsContext{},
 	}
 
 	go func() {
@@ -2327,6 +2398,485 @@ func (s *Server) HandleBpfSyscall(c Instance, siov *Iovec) int {
 	return 0
 }
 
+// fsContext is a file system context created through the new mount API on behalf of an instance.
+type fsContext struct {
+	file   *os.File
+	fstype string
+
+	// Number of syscall handlers using the context and whether it's no longer tracked, protected by fsContextsMu.
+	refs     int
+	released bool
+}
+
+// fsContextsMax is the maximum number of file system contexts tracked per instance.
+const fsContextsMax = 16
+
+// fsContextRelease stops tracking a file system context, closing it unless a syscall handler is still using it.
+// The caller must hold fsContextsMu.
+func fsContextRelease(fsCtx *fsContext) {
+	fsCtx.released = true
+	if fsCtx.refs == 0 {
+		_ = fsCtx.file.Close()
+	}
+}
+
+// fsContextAdd tracks a file system context created on behalf of the instance.
+// The oldest contexts are released once the instance has too many pending ones.
+func (s *Server) fsContextAdd(c Instance, fsCtx *fsContext) {
+	key := project.Instance(c.Project().Name, c.Name())
+
+	s.fsContextsMu.Lock()
+	defer s.fsContextsMu.Unlock()
+
+	contexts := append(s.fsContexts[key], fsCtx)
+	for len(contexts) > fsContextsMax {
+		fsContextRelease(contexts[0])
+		contexts = contexts[1:]
+	}
+
+	s.fsContexts[key] = contexts
+}
+
+// fsContextGet returns the tracked file system context referred to by a file descriptor of the process.
+// The returned context must be passed to fsContextPut once no longer used.
+func (s *Server) fsContextGet(c Instance, pidFd *os.File, fd int) *fsContext {
+	key := project.Instance(c.Project().Name, c.Name())
+
+	targetFd, err := unix.PidfdGetfd(int(pidFd.Fd()), fd, 0)
+	if err != nil {
+		return nil
+	}
+
+	defer func() { _ = unix.Close(targetFd) }()
+
+	s.fsContextsMu.Lock()
+	defer s.fsContextsMu.Unlock()
+
+	pid := C.pid_t(os.Getpid())
+	for _, fsCtx := range s.fsContexts[key] {
+		// Compare the open files as file descriptors can be duplicated or reused.
+		if C.kcmp(pid, pid, C.KCMP_FILE, C.ulong(targetFd), C.ulong(fsCtx.file.Fd())) == 0 {
+			fsCtx.refs++
+			return fsCtx
+		}
+	}
+
+	return nil
+}
+
+// fsContextPut releases a file system context returned by fsContextGet.
+func (s *Server) fsContextPut(fsCtx *fsContext) {
+	s.fsContextsMu.Lock()
+	defer s.fsContextsMu.Unlock()
+
+	fsCtx.refs--
+	if fsCtx.released && fsCtx.refs == 0 {
+		_ = fsCtx.file.Close()
+	}
+}
+
+// fsContextRemove stops tracking a file system context.
+func (s *Server) fsContextRemove(c Instance, fsCtx *fsContext) {
+	key := project.Instance(c.Project().Name, c.Name())
+
+	s.fsContextsMu.Lock()
+	defer s.fsContextsMu.Unlock()
+
+	if fsCtx.released {
+		return
+	}
+
+	contexts := s.fsContexts[key]
+	for i, entry := range contexts {
+		if entry == fsCtx {
+			contexts = append(contexts[:i], contexts[i+1:]...)
+			break
+		}
+	}
+
+	if len(contexts) == 0 {
+		delete(s.fsContexts, key)
+	} else {
+		s.fsContexts[key] = contexts
+	}
+
+	fsContextRelease(fsCtx)
+}
+
+// ReleaseInstance releases the file system contexts tracked on behalf of an instance.
+// It must be called when the instance stops, is renamed or is deleted.
+func (s *Server) ReleaseInstance(projectName string, instanceName string) {
+	key := project.Instance(projectName, instanceName)
+
+	s.fsContextsMu.Lock()
+	defer s.fsContextsMu.Unlock()
+
+	for _, fsCtx := range s.fsContexts[key] {
+		fsContextRelease(fsCtx)
+	}
+
+	delete(s.fsContexts, key)
+}
+
+// callForksyscallFd runs a forksyscall command which hands over a file descriptor to the process.
+// It returns the file descriptor number in the process or a negative errno.
+func callForksyscallFd(files []*os.File, args ...string) int {
+	stdout, stderr, err := subprocess.RunCommandSplit(context.TODO(), nil, files, localUtil.GetExecPath(), append([]string{"forksyscall"}, args...)...)
+	if err != nil {
+		errno, err := strconv.Atoi(stderr)
+		if err != nil || errno == C.ENOANO {
+			return int(-C.EPERM)
+		}
+
+		return -errno
+	}
+
+	fd, err := strconv.Atoi(strings.TrimSpace(stdout))
+	if err != nil {
+		return int(-C.EBADF)
+	}
+
+	return fd
+}
+
+// HandleFsopenSyscall handles fsopen syscalls.
+func (s *Server) HandleFsopenSyscall(c Instance, siov *Iovec) int {
+	ctx := logger.Ctx{"container": c.Name(),
+		"project":               c.Project().Name,
+		"syscall_number":        siov.req.data.nr,
+		"audit_architecture":    siov.req.data.arch,
+		"seccomp_notify_id":     siov.req.id,
+		"seccomp_notify_flags":  siov.req.flags,
+		"seccomp_notify_pid":    siov.req.pid,
+		"seccomp_notify_fd":     siov.notifyFd,
+		"seccomp_notify_mem_fd": siov.memFd,
+	}
+
+	defer logger.Debug("Handling fsopen syscall", ctx)
+
+	pid := int(siov.req.pid)
+
+	// const char *fsname
+	fsName := [unix.PathMax]C.char{}
+	_, err := C.pread(C.int(siov.memFd), unsafe.Pointer(&fsName[0]), C.size_t(unix.PathMax), C.off_t(siov.req.data.args[0]))
+	if err != nil {
+		ctx["err"] = fmt.Sprintf("Failed to read fstype for of fsopen syscall: %s", err)
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	fstype := C.GoString(&fsName[0])
+	ctx["fstype"] = fstype
+
+	// Only the allowed file systems are handled, fuse isn't available through the new mount API.
+	fsMap, err := SyscallInterceptMountFilter(c.ExpandedConfig())
+	fuseBinary, ok := fsMap[fstype]
+	if err != nil || !ok || fuseBinary != "" {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	uid, gid, fsuid, fsgid, err := TaskIDs(pid)
+	if err != nil {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	pidFdNr, pidFd := MakePidFd(pid, s.s)
+	if pidFdNr < 0 {
+		ctx["syscall_continue"] = "true"
+		ctx["syscall_handler_reason"] = "Could not open pidfd"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	defer func() { _ = pidFd.Close() }()
+
+	notifyFd, err := unix.Dup(siov.notifyFd)
+	if err != nil {
+		return int(-C.EPERM)
+	}
+
+	notifyFile := os.NewFile(uintptr(notifyFd), "seccomp-notify")
+	defer func() { _ = notifyFile.Close() }()
+
+	// The file system context is created with the credentials of the caller from within the instance
+	// and directly installed in the process.
+	fd := callForksyscallFd(
+		[]*os.File{pidFd, notifyFile},
+		"fsopen",
+		fmt.Sprintf("%d", pid),
+		fmt.Sprintf("%d", pidFdNr),
+		fmt.Sprintf("%d", pidFdNr+1),
+		fmt.Sprintf("%d", uint64(siov.req.id)),
+		fstype,
+		fmt.Sprintf("%d", uint64(siov.req.data.args[1])),
+		fmt.Sprintf("%d", uid),
+		fmt.Sprintf("%d", gid),
+		fmt.Sprintf("%d", fsuid),
+		fmt.Sprintf("%d", fsgid))
+	if fd < 0 {
+		ctx["syscall_handler_error"] = fmt.Sprintf("%s - Failed to create file system context", unix.Errno(-fd))
+		return fd
+	}
+
+	ctx["fs_fd"] = fd
+
+	// Keep a reference to the context to recognize it in later fsconfig and fsmount syscalls.
+	contextFd, err := unix.PidfdGetfd(int(pidFd.Fd()), fd, 0)
+	if err == nil {
+		s.fsContextAdd(c, &fsContext{file: os.NewFile(uintptr(contextFd), "fscontext"), fstype: fstype})
+	}
+
+	siov.resp.val = C.__s64(fd)
+	return 0
+}
+
+// HandleFsconfigSyscall handles fsconfig syscalls creating a superblock.
+func (s *Server) HandleFsconfigSyscall(c Instance, siov *Iovec) int {
+	ctx := logger.Ctx{"container": c.Name(),
+		"project":               c.Project().Name,
+		"syscall_number":        siov.req.data.nr,
+		"audit_architecture":    siov.req.data.arch,
+		"seccomp_notify_id":     siov.req.id,
+		"seccomp_notify_flags":  siov.req.flags,
+		"seccomp_notify_pid":    siov.req.pid,
+		"seccomp_notify_fd":     siov.notifyFd,
+		"seccomp_notify_mem_fd": siov.memFd,
+	}
+
+	defer logger.Debug("Handling fsconfig syscall", ctx)
+
+	pid := int(siov.req.pid)
+
+	pidFdNr, pidFd := MakePidFd(pid, s.s)
+	if pidFdNr < 0 {
+		ctx["syscall_continue"] = "true"
+		ctx["syscall_handler_reason"] = "Could not open pidfd"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	defer func() { _ = pidFd.Close() }()
+
+	// Contexts created by the instance itself are left to the kernel.
+	fsCtx := s.fsContextGet(c, pidFd, int(siov.req.data.args[0]))
+	if fsCtx == nil {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	defer s.fsContextPut(fsCtx)
+
+	ctx["fstype"] = fsCtx.fstype
+
+	uid, gid, fsuid, fsgid, err := TaskIDs(pid)
+	if err != nil {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	_, stderr, err := subprocess.RunCommandSplit(
+		context.TODO(),
+		nil,
+		[]*os.File{pidFd, fsCtx.file},
+		localUtil.GetExecPath(),
+		"forksyscall",
+		"fsconfig",
+		fmt.Sprintf("%d", pid),
+		fmt.Sprintf("%d", pidFdNr),
+		fmt.Sprintf("%d", pidFdNr+1),
+		fmt.Sprintf("%d", uid),
+		fmt.Sprintf("%d", gid),
+		fmt.Sprintf("%d", fsuid),
+		fmt.Sprintf("%d", fsgid))
+	if err != nil {
+		errno, err := strconv.Atoi(stderr)
+		if err != nil || errno == C.ENOANO {
+			return int(-C.EPERM)
+		}
+
+		ctx["syscall_handler_error"] = fmt.Sprintf("%s - Failed to create superblock", unix.Errno(errno))
+		return -errno
+	}
+
+	// Shifted file systems get mounted on behalf of the instance too.
+	if s.MountSyscallShift(c, "", fsCtx.fstype) != idmap.IdmapStorageIdmapped {
+		s.fsContextRemove(c, fsCtx)
+	}
+
+	return 0
+}
+
+// HandleFsmountSyscall handles fsmount syscalls.
+func (s *Server) HandleFsmountSyscall(c Instance, siov *Iovec) int {
+	ctx := logger.Ctx{"container": c.Name(),
+		"project":               c.Project().Name,
+		"syscall_number":        siov.req.data.nr,
+		"audit_architecture":    siov.req.data.arch,
+		"seccomp_notify_id":     siov.req.id,
+		"seccomp_notify_flags":  siov.req.flags,
+		"seccomp_notify_pid":    siov.req.pid,
+		"seccomp_notify_fd":     siov.notifyFd,
+		"seccomp_notify_mem_fd": siov.memFd,
+	}
+
+	defer logger.Debug("Handling fsmount syscall", ctx)
+
+	pid := int(siov.req.pid)
+
+	pidFdNr, pidFd := MakePidFd(pid, s.s)
+	if pidFdNr < 0 {
+		ctx["syscall_continue"] = "true"
+		ctx["syscall_handler_reason"] = "Could not open pidfd"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	defer func() { _ = pidFd.Close() }()
+
+	fsCtx := s.fsContextGet(c, pidFd, int(siov.req.data.args[0]))
+	if fsCtx == nil {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	defer func() {
+		s.fsContextRemove(c, fsCtx)
+		s.fsContextPut(fsCtx)
+	}()
+
+	ctx["fstype"] = fsCtx.fstype
+
+	uid, gid, fsuid, fsgid, err := TaskIDs(pid)
+	if err != nil || s.MountSyscallShift(c, "", fsCtx.fstype) != idmap.IdmapStorageIdmapped {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	notifyFd, err := unix.Dup(siov.notifyFd)
+	if err != nil {
+		return int(-C.EPERM)
+	}
+
+	notifyFile := os.NewFile(uintptr(notifyFd), "seccomp-notify")
+	defer func() { _ = notifyFile.Close() }()
+
+	// The mount is created and idmapped on behalf of the caller and then installed in the process.
+	fd := callForksyscallFd(
+		[]*os.File{pidFd, notifyFile, fsCtx.file},
+		"fsmount",
+		fmt.Sprintf("%d", pid),
+		fmt.Sprintf("%d", pidFdNr),
+		fmt.Sprintf("%d", pidFdNr+1),
+		fmt.Sprintf("%d", uint64(siov.req.id)),
+		fmt.Sprintf("%d", pidFdNr+2),
+		fmt.Sprintf("%d", uint64(siov.req.data.args[1])),
+		fmt.Sprintf("%d", uint64(siov.req.data.args[2])),
+		fmt.Sprintf("%d", uid),
+		fmt.Sprintf("%d", gid),
+		fmt.Sprintf("%d", fsuid),
+		fmt.Sprintf("%d", fsgid))
+	if fd < 0 {
+		ctx["syscall_handler_error"] = fmt.Sprintf("%s - Failed to create idmapped mount", unix.Errno(-fd))
+		return fd
+	}
+
+	ctx["mount_fd"] = fd
+
+	siov.resp.val = C.__s64(fd)
+	return 0
+}
+
+// keyQuotaAvailable checks whether the given user can still allocate a key according to the
+// key quotas listed in /proc/key-users.
+func keyQuotaAvailable(keyUsers string, uid int64) (bool, error) {
+	for _, line := range strings.Split(keyUsers, "\n") {
+		// Format: <uid>: <usage> <nkeys>/<nikeys> <qnkeys>/<maxkeys> <qnbytes>/<maxbytes>
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != fmt.Sprintf("%d:", uid) {
+			continue
+		}
+
+		for _, field := range fields[3:5] {
+			usedValue, maxValue, ok := strings.Cut(field, "/")
+			if !ok {
+				return false, fmt.Errorf("Invalid key quota %q", field)
+			}
+
+			used, err := strconv.ParseInt(usedValue, 10, 64)
+			if err != nil {
+				return false, err
+			}
+
+			maximum, err := strconv.ParseInt(maxValue, 10, 64)
+			if err != nil {
+				return false, err
+			}
+
+			if used >= maximum {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+
+	// Users without any key don't have an entry.
+	return true, nil
+}
+
+// HandleKeyctlSyscall handles keyctl syscalls joining a session keyring.
+func (s *Server) HandleKeyctlSyscall(c Instance, siov *Iovec) int {
+	ctx := logger.Ctx{"container": c.Name(),
+		"project":               c.Project().Name,
+		"syscall_number":        siov.req.data.nr,
+		"audit_architecture":    siov.req.data.arch,
+		"seccomp_notify_id":     siov.req.id,
+		"seccomp_notify_flags":  siov.req.flags,
+		"seccomp_notify_pid":    siov.req.pid,
+		"seccomp_notify_fd":     siov.notifyFd,
+		"seccomp_notify_mem_fd": siov.memFd,
+	}
+
+	defer logger.Debug("Handling keyctl syscall", ctx)
+
+	uid, _, _, _, err := TaskIDs(int(siov.req.pid))
+	if err != nil {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	ctx["host_uid"] = uid
+
+	keyUsers, err := os.ReadFile("/proc/key-users")
+	if err != nil {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	available, err := keyQuotaAvailable(string(keyUsers), uid)
+	if err != nil || available {
+		ctx["syscall_continue"] = "true"
+		C.seccomp_notify_update_response(siov.resp, 0, C.uint32_t(seccompUserNotifFlagContinue))
+		return 0
+	}
+
+	// Container runtimes fall back to not using a session keyring when keyrings aren't supported,
+	// rather than failing on the exhausted quota of the host user.
+	ctx["syscall_handler_reason"] = "Key quota exhausted"
+	return int(-C.ENOSYS)
+}
+
 func (s *Server) handleSyscall(c Instance, siov *Iovec) int {
 	switch int(C.seccomp_notify_get_syscall(siov.req, siov.resp)) {
 	case incusSeccompNotifyMknod:
@@ -2343,6 +2893,14 @@ func (s *Server) handleSyscall(c Instance, siov *Iovec) int {
 		return s.HandleSchedSetschedulerSyscall(c, siov)
 	case incusSeccompNotifySysinfo:
 		return s.HandleSysinfoSyscall(c, siov)
+	case incusSeccompNotifyFsopen:
+		return s.HandleFsopenSyscall(c, siov)
+	case incusSeccompNotifyFsconfig:
+		return s.HandleFsconfigSyscall(c, siov)
+	case incusSeccompNotifyFsmount:
+		return s.HandleFsmountSyscall(c, siov)
+	case incusSeccompNotifyKeyctl:
+		return s.HandleKeyctlSyscall(c, siov)
 	}
 
 	return int(-C.EINVAL)
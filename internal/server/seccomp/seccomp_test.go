@@ -4,7 +4,16 @@ package seccomp
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"testing"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/sys"
+	"github.com/lxc/incus/v6/shared/api"
 )
 
 func TestMountFlagsToOpts(t *testing.T) {
@@ -18,3 +27,209 @@ func TestMountFlagsToOpts(t *testing.T) {
 		t.Fatal(fmt.Errorf("Mount options parsing failed with invalid option string: %s", opts))
 	}
 }
+
+func TestKeyQuotaAvailable(t *testing.T) {
+	keyUsers := `    0:   133 132/132 127/1000000 2853/25000000
+1000000:     5 5/5 5/200 92/20000
+1065536:   200 200/200 200/200 4012/20000
+`
+
+	for uid, expected := range map[int64]bool{0: true, 1000000: true, 1065536: false, 1131072: true} {
+		available, err := keyQuotaAvailable(keyUsers, uid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if available != expected {
+			t.Fatal(fmt.Errorf("Key quota of uid %d reported as available=%v", uid, available))
+		}
+	}
+}
+
+func TestInterceptPolicyNewMountAPI(t *testing.T) {
+	config := map[string]string{"security.syscalls.intercept.mount": "true"}
+
+	s := &state.State{OS: &sys.OS{SeccompListenerAddfd: true}}
+	policy := seccompInterceptPolicy(s, config)
+	if !strings.Contains(policy, "fsopen notify\n") || !strings.Contains(policy, "fsconfig notify [1,6,SCMP_CMP_EQ]\n") {
+		t.Fatal(fmt.Errorf("New mount API isn't intercepted: %s", policy))
+	}
+
+	if strings.Contains(policy, "errno 38") || strings.Contains(policy, "fsmount notify") {
+		t.Fatal(fmt.Errorf("Unexpected new mount API rules: %s", policy))
+	}
+
+	config["security.syscalls.intercept.mount.shift"] = "true"
+	policy = seccompInterceptPolicy(s, config)
+	if !strings.Contains(policy, "fsmount notify\n") {
+		t.Fatal(fmt.Errorf("fsmount isn't intercepted with shifting: %s", policy))
+	}
+
+	s.OS.SeccompListenerAddfd = false
+	policy = seccompInterceptPolicy(s, config)
+	if strings.Contains(policy, "fsopen notify") || strings.Contains(policy, "fsconfig notify") || strings.Contains(policy, "fsmount notify") {
+		t.Fatal(fmt.Errorf("New mount API intercepted without file descriptor handover: %s", policy))
+	}
+
+	for _, syscall := range []string{"fsopen", "fsconfig", "fsmount", "fspick", "open_tree", "move_mount", "mount_setattr"} {
+		if !strings.Contains(policy, syscall+" errno 38\n") {
+			t.Fatal(fmt.Errorf("%s isn't blocked without file descriptor handover: %s", syscall, policy))
+		}
+	}
+
+	delete(config, "security.syscalls.intercept.mount")
+	s.OS.SeccompListenerAddfd = true
+	policy = seccompInterceptPolicy(s, config)
+	if strings.Contains(policy, "fsopen") || strings.Contains(policy, "fsconfig") {
+		t.Fatal(fmt.Errorf("New mount API handled without mount interception: %s", policy))
+	}
+}
+
+type testInstance struct {
+	Instance
+
+	name string
+}
+
+func (c *testInstance) Name() string {
+	return c.name
+}
+
+func (c *testInstance) Project() api.Project {
+	return api.Project{Name: "default"}
+}
+
+func testFsContext(t *testing.T) *fsContext {
+	file, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &fsContext{file: file, fstype: "tmpfs"}
+}
+
+func testFsContextClosed(fsCtx *fsContext) bool {
+	_, err := fsCtx.file.Stat()
+	return err != nil
+}
+
+func TestFsContextTracking(t *testing.T) {
+	s := &Server{fsContexts: map[string][]*fsContext{}}
+	c1 := &testInstance{name: "c1"}
+	c2 := &testInstance{name: "c2"}
+
+	pidFd, err := unix.PidfdOpen(os.Getpid(), 0)
+	if err != nil {
+		t.Skipf("pidfd isn't supported: %v", err)
+	}
+
+	pidFdFile := os.NewFile(uintptr(pidFd), "pidfd")
+	defer func() { _ = pidFdFile.Close() }()
+
+	fsCtx := testFsContext(t)
+	s.fsContextAdd(c1, fsCtx)
+
+	// Contexts are looked up through any file descriptor of the same open file.
+	fd, err := unix.Dup(int(fsCtx.file.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = unix.Close(fd) }()
+
+	if s.fsContextGet(c2, pidFdFile, fd) != nil {
+		t.Fatal("Context found for another instance")
+	}
+
+	other, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = other.Close() }()
+
+	if s.fsContextGet(c1, pidFdFile, int(other.Fd())) != nil {
+		t.Fatal("Context found for an unrelated file")
+	}
+
+	if s.fsContextGet(c1, pidFdFile, fd) != fsCtx {
+		t.Fatal("Tracked context not found")
+	}
+
+	// Contexts removed while in use are only closed once no longer used.
+	s.fsContextRemove(c1, fsCtx)
+	if testFsContextClosed(fsCtx) {
+		t.Fatal("Context closed while in use")
+	}
+
+	if s.fsContextGet(c1, pidFdFile, fd) != nil {
+		t.Fatal("Removed context still tracked")
+	}
+
+	s.fsContextPut(fsCtx)
+	if !testFsContextClosed(fsCtx) {
+		t.Fatal("Removed context not closed")
+	}
+
+	_, ok := s.fsContexts[project.Instance("default", "c1")]
+	if ok {
+		t.Fatal("Instance still tracked without contexts")
+	}
+}
+
+func TestFsContextLimit(t *testing.T) {
+	s := &Server{fsContexts: map[string][]*fsContext{}}
+	c1 := &testInstance{name: "c1"}
+	c2 := &testInstance{name: "c2"}
+
+	contexts := []*fsContext{}
+	for range fsContextsMax + 2 {
+		fsCtx := testFsContext(t)
+		contexts = append(contexts, fsCtx)
+		s.fsContextAdd(c1, fsCtx)
+	}
+
+	// Keep the oldest tracked context in use when it gets evicted.
+	contexts[2].refs++
+
+	s.fsContextAdd(c1, testFsContext(t))
+	s.fsContextAdd(c2, testFsContext(t))
+
+	tracked := s.fsContexts[project.Instance("default", "c1")]
+	if len(tracked) != fsContextsMax {
+		t.Fatal(fmt.Errorf("Unexpected number of tracked contexts: %d", len(tracked)))
+	}
+
+	for i, fsCtx := range contexts {
+		evicted := i < 3
+		if fsCtx.released != evicted {
+			t.Fatal(fmt.Errorf("Context %d reported as released=%v", i, fsCtx.released))
+		}
+
+		if testFsContextClosed(fsCtx) != (evicted && i != 2) {
+			t.Fatal(fmt.Errorf("Context %d has an unexpected state", i))
+		}
+	}
+
+	s.fsContextPut(contexts[2])
+	if !testFsContextClosed(contexts[2]) {
+		t.Fatal("Evicted context not closed once no longer used")
+	}
+
+	// Releasing an instance only closes its own contexts.
+	s.ReleaseInstance("default", "c1")
+	for i, fsCtx := range tracked {
+		if !fsCtx.released || !testFsContextClosed(fsCtx) {
+			t.Fatal(fmt.Errorf("Context %d not released with the instance", i))
+		}
+	}
+
+	_, ok := s.fsContexts[project.Instance("default", "c1")]
+	if ok {
+		t.Fatal("Released instance still tracked")
+	}
+
+	if len(s.fsContexts[project.Instance("default", "c2")]) != 1 {
+		t.Fatal("Contexts of other instances released")
+	}
+}
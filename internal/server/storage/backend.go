@@ -20,7 +20,9 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
 
@@ -49,6 +51,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/storage/memorypipe"
 	"github.com/lxc/incus/v6/internal/server/storage/s3"
 	"github.com/lxc/incus/v6/internal/server/storage/s3/miniod"
+	"github.com/lxc/incus/v6/internal/server/tracing"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
@@ -1016,6 +1019,9 @@ func (b *backend) CreateInstanceFromCopy(inst instance.Instance, src instance.In
 	l.Debug("CreateInstanceFromCopy started")
 	defer l.Debug("CreateInstanceFromCopy finished")
 
+	_, span := tracing.Start(op.TraceContext(), "Copy instance volume", attribute.String("incus.storage.pool", b.name), attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name()))
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -1908,6 +1914,9 @@ func (b *backend) CreateInstanceFromMigration(inst instance.Instance, conn io.Re
 	l.Debug("CreateInstanceFromMigration started")
 	defer l.Debug("CreateInstanceFromMigration finished")
 
+	_, span := tracing.Start(op.TraceContext(), "Receive instance volume", attribute.String("incus.storage.pool", b.name), attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name()))
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
@@ -2503,6 +2512,9 @@ func (b *backend) MigrateInstance(inst instance.Instance, conn io.ReadWriteClose
 	l.Debug("MigrateInstance started")
 	defer l.Debug("MigrateInstance finished")
 
+	_, span := tracing.Start(op.TraceContext(), "Send instance volume", attribute.String("incus.storage.pool", b.name), attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name()))
+	defer span.End()
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -2665,6 +2677,9 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	_, span := tracing.Start(op.TraceContext(), "Backup instance volume", attribute.String("incus.storage.pool", b.name), attribute.String("incus.project", inst.Project().Name), attribute.String("incus.instance", inst.Name()))
+	defer span.End()
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -2707,6 +2722,40 @@ func (b *backend) BackupInstance(inst instance.Instance, tarWriter *instancewrit
 		}
 	}
 
+	// Export running virtual machines from a temporary snapshot, so their file systems only need to be
+	// frozen while the snapshot is taken rather than for the whole export.
+	if inst.Type() == instancetype.VM && inst.IsRunning() {
+		snapName := fmt.Sprintf("backup-%s", uuid.New().String())
+		snapVol, err := vol.NewSnapshot(snapName)
+		if err != nil {
+			return err
+		}
+
+		thaw, err := inst.(instance.VM).FreezeFilesystems("backup")
+		if err != nil {
+			return err
+		}
+
+		err = b.driver.CreateVolumeSnapshot(snapVol, op)
+		thawErr := thaw()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			err := b.driver.DeleteVolumeSnapshot(snapVol, op)
+			if err != nil {
+				l.Warn("Failed deleting temporary snapshot for backup", logger.Ctx{"snapshot": snapName, "err": err})
+			}
+		}()
+
+		if thawErr != nil {
+			return fmt.Errorf("Failed taking a consistent snapshot: %w", thawErr)
+		}
+
+		vol.SetBackupSnapshot(snapName)
+	}
+
 	err = b.driver.BackupVolume(vol, tarWriter, optimized, snapNames, op)
 	if err != nil {
 		return err
@@ -5018,6 +5067,9 @@ func (b *backend) MigrateCustomVolume(projectName string, conn io.ReadWriteClose
 	l.Debug("MigrateCustomVolume started")
 	defer l.Debug("MigrateCustomVolume finished")
 
+	_, span := tracing.Start(op.TraceContext(), "Send custom volume", attribute.String("incus.storage.pool", b.name), attribute.String("incus.project", projectName), attribute.String("incus.volume", args.Name))
+	defer span.End()
+
 	// Get the volume name on storage.
 	volStorageName := project.StorageVolume(projectName, args.Name)
 
@@ -5068,6 +5120,9 @@ func (b *backend) CreateCustomVolumeFromMigration(projectName string, conn io.Re
 	l.Debug("CreateCustomVolumeFromMigration started")
 	defer l.Debug("CreateCustomVolumeFromMigration finished")
 
+	_, span := tracing.Start(op.TraceContext(), "Receive custom volume", attribute.String("incus.storage.pool", b.name), attribute.String("incus.project", projectName), attribute.String("incus.volume", args.Name))
+	defer span.End()
+
 	err := b.isStatusReady()
 	if err != nil {
 		return err
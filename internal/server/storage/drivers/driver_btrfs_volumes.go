@@ -1725,8 +1725,17 @@ func (d *btrfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWr
 		lastVolPath = snapVol.MountPath()
 	}
 
-	// Make a temporary copy of the instance.
+	// Make a temporary copy of the instance, or of the snapshot the backup is taken from.
 	sourceVolume := vol.MountPath()
+	if vol.backupSnapshot != "" {
+		snapVol, err := vol.NewSnapshot(vol.backupSnapshot)
+		if err != nil {
+			return err
+		}
+
+		sourceVolume = snapVol.MountPath()
+	}
+
 	instancesPath := GetVolumeMountPath(d.name, vol.volType, "")
 
 	tmpInstanceMntPoint, err := os.MkdirTemp(instancesPath, "backup.")
@@ -2837,20 +2837,31 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 		}
 	}
 
-	// Create a temporary read-only snapshot.
-	srcSnapshot := fmt.Sprintf("%s@backup-%s", d.dataset(vol, false), uuid.New().String())
-	_, err := subprocess.RunCommand("zfs", "snapshot", "-r", srcSnapshot)
-	if err != nil {
-		return err
-	}
+	var srcSnapshot string
+	if vol.backupSnapshot != "" {
+		// Send the existing snapshot the backup is taken from.
+		snapVol, err := vol.NewSnapshot(vol.backupSnapshot)
+		if err != nil {
+			return err
+		}
 
-	defer func() {
-		// Delete snapshot (or mark for deferred deletion if cannot be deleted currently).
-		_, err := subprocess.RunCommand("zfs", "destroy", "-r", "-d", srcSnapshot)
+		srcSnapshot = d.dataset(snapVol, false)
+	} else {
+		// Create a temporary read-only snapshot.
+		srcSnapshot = fmt.Sprintf("%s@backup-%s", d.dataset(vol, false), uuid.New().String())
+		_, err := subprocess.RunCommand("zfs", "snapshot", "-r", srcSnapshot)
 		if err != nil {
-			d.logger.Warn("Failed deleting temporary snapshot for backup", logger.Ctx{"snapshot": srcSnapshot, "err": err})
+			return err
 		}
-	}()
+
+		defer func() {
+			// Delete snapshot (or mark for deferred deletion if cannot be deleted currently).
+			_, err := subprocess.RunCommand("zfs", "destroy", "-r", "-d", srcSnapshot)
+			if err != nil {
+				d.logger.Warn("Failed deleting temporary snapshot for backup", logger.Ctx{"snapshot": srcSnapshot, "err": err})
+			}
+		}()
+	}
 
 	// Dump the container to a file.
 	fileName := "container.bin"
@@ -2864,7 +2875,7 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 		fileName = "volume.bin"
 	}
 
-	err = sendToFile(srcSnapshot, finalParent, fmt.Sprintf("backup/%s", fileName))
+	err := sendToFile(srcSnapshot, finalParent, fmt.Sprintf("backup/%s", fileName))
 	if err != nil {
 		return err
 	}
@@ -107,6 +107,7 @@ type Volume struct {
 	mountFilesystemProbe bool   // Probe filesystem type when mounting volume (when needed).
 	hasSource            bool   // Whether the volume is created from a source volume.
 	isDeleted            bool   // Whether we're dealing with a hidden volume (kept until all references are gone).
+	backupSnapshot       string // Back up the volume from this snapshot rather than from its live content.
 }
 
 // NewVolume instantiates a new Volume struct.
@@ -468,8 +469,9 @@ func (v Volume) NewVMBlockFilesystemVolume() Volume {
 
 	vol := NewVolume(v.driver, v.pool, v.volType, ContentTypeFS, v.name, newConf, v.poolConfig)
 
-	// Propagate filesystem probe mode of parent volume.
+	// Propagate filesystem probe mode and backup snapshot of parent volume.
 	vol.SetMountFilesystemProbe(v.mountFilesystemProbe)
+	vol.SetBackupSnapshot(v.backupSnapshot)
 
 	return vol
 }
@@ -613,6 +615,11 @@ func (v *Volume) SetHasSource(hasSource bool) {
 	v.hasSource = hasSource
 }
 
+// SetBackupSnapshot sets the existing snapshot the volume is read from when backing it up.
+func (v *Volume) SetBackupSnapshot(snapshotName string) {
+	v.backupSnapshot = snapshotName
+}
+
 // Clone returns a copy of the volume.
 func (v Volume) Clone() Volume {
 	// Copy the config map to avoid internal modifications affecting external state.
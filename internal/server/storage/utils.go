@@ -1013,6 +1013,7 @@ func VolumeUsedByExclusiveRemoteInstancesWithProfiles(s *state.State, poolName s
 func VolumeUsedByDaemon(s *state.State, poolName string, volumeName string) (bool, error) {
 	var storageBackups string
 	var storageImages string
+	var storageRecordings string
 	err := s.DB.Node.Transaction(context.TODO(), func(ctx context.Context, tx *db.NodeTx) error {
 		nodeConfig, err := node.ConfigLoad(ctx, tx)
 		if err != nil {
@@ -1021,6 +1022,7 @@ func VolumeUsedByDaemon(s *state.State, poolName string, volumeName string) (boo
 
 		storageBackups = nodeConfig.StorageBackupsVolume()
 		storageImages = nodeConfig.StorageImagesVolume()
+		storageRecordings = nodeConfig.StorageRecordingsVolume()
 
 		return nil
 	})
@@ -1029,7 +1031,7 @@ func VolumeUsedByDaemon(s *state.State, poolName string, volumeName string) (boo
 	}
 
 	fullName := fmt.Sprintf("%s/%s", poolName, volumeName)
-	if storageBackups == fullName || storageImages == fullName {
+	if storageBackups == fullName || storageImages == fullName || storageRecordings == fullName {
 		return true, nil
 	}
 
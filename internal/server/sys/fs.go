@@ -49,9 +49,11 @@ func (s *OS) initDirs() error {
 		{filepath.Join(s.VarDir, "devices"), 0711},
 		{filepath.Join(s.VarDir, "disks"), 0700},
 		{filepath.Join(s.VarDir, "guestapi"), 0755},
+		{filepath.Join(s.VarDir, "history"), 0700},
 		{filepath.Join(s.VarDir, "images"), 0700},
 		{s.LogDir, 0700},
 		{filepath.Join(s.VarDir, "networks"), 0711},
+		{filepath.Join(s.VarDir, "recordings"), 0700},
 		{s.RunDir, 0711},
 		{filepath.Join(s.VarDir, "security"), 0700},
 		{filepath.Join(s.VarDir, "security", "apparmor"), 0700},
@@ -0,0 +1,154 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/lxc/incus/v6/internal/version"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// tracerName is the instrumentation scope used for all spans.
+const tracerName = "github.com/lxc/incus/v6"
+
+// propagator carries the trace context between cluster members using the W3C trace context headers.
+var propagator = propagation.TraceContext{}
+
+var providerMu sync.RWMutex
+var provider *sdktrace.TracerProvider
+var tracer trace.Tracer = noop.NewTracerProvider().Tracer(tracerName)
+
+// Setup configures the export of spans to the OTLP endpoint, replacing any previous configuration.
+// An empty endpoint disables tracing.
+func Setup(endpoint string, headers map[string]string, caCert string, location string) error {
+	var newProvider *sdktrace.TracerProvider
+
+	if endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("Failed parsing OTLP endpoint: %w", err)
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("Unsupported OTLP endpoint scheme %q", u.Scheme)
+		}
+
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(u.Host),
+			otlptracehttp.WithURLPath(strings.TrimSuffix(u.Path, "/") + "/v1/traces"),
+		}
+
+		if len(headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(headers))
+		}
+
+		if u.Scheme == "http" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else if caCert != "" {
+			tlsConfig, err := localtls.GetTLSConfigMem("", "", caCert, "", false)
+			if err != nil {
+				return fmt.Errorf("Failed setting up OTLP TLS configuration: %w", err)
+			}
+
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+		}
+
+		// The exporter only connects when sending spans so this doesn't block.
+		exporter, err := otlptracehttp.New(context.Background(), opts...)
+		if err != nil {
+			return fmt.Errorf("Failed creating OTLP exporter: %w", err)
+		}
+
+		res := resource.NewSchemaless(
+			attribute.String("service.name", "incus"),
+			attribute.String("service.version", version.Version),
+			attribute.String("service.instance.id", location),
+		)
+
+		newProvider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	}
+
+	providerMu.Lock()
+	oldProvider := provider
+	provider = newProvider
+	if newProvider != nil {
+		tracer = newProvider.Tracer(tracerName)
+	} else {
+		tracer = noop.NewTracerProvider().Tracer(tracerName)
+	}
+
+	providerMu.Unlock()
+
+	// Flush and stop the previous exporter.
+	if oldProvider != nil {
+		_ = oldProvider.Shutdown(context.Background())
+	}
+
+	return nil
+}
+
+// Shutdown flushes any pending span and disables tracing.
+func Shutdown(ctx context.Context) error {
+	providerMu.Lock()
+	oldProvider := provider
+	provider = nil
+	tracer = noop.NewTracerProvider().Tracer(tracerName)
+	providerMu.Unlock()
+
+	if oldProvider == nil {
+		return nil
+	}
+
+	return oldProvider.Shutdown(ctx)
+}
+
+// Start starts a new span as a child of any span found in the context.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	providerMu.RLock()
+	t := tracer
+	providerMu.RUnlock()
+
+	return t.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span, recording the error if there is one.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Inject adds the trace context of the span found in the context (if any) to the HTTP headers.
+func Inject(ctx context.Context, header http.Header) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract returns a context carrying the remote trace context found in the HTTP headers (if any).
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// HasSpan returns whether the context carries a valid span.
+func HasSpan(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
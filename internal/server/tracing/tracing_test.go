@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Spans are exported to the collector and the trace context survives a round trip through HTTP headers.
+func TestSetup(t *testing.T) {
+	received := make(chan *http.Request, 10)
+
+	// Local stand-in for an OTLP collector.
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 0 {
+			received <- r
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	err := Setup(collector.URL, map[string]string{"X-Scope-OrgID": "incus"}, "", "server01")
+	require.NoError(t, err)
+
+	ctx, span := Start(context.Background(), "parent")
+	require.True(t, HasSpan(ctx))
+
+	// Propagate the trace context like a cluster member would.
+	header := http.Header{}
+	Inject(ctx, header)
+	require.NotEmpty(t, header.Get("traceparent"))
+
+	remoteCtx := Extract(context.Background(), header)
+	_, child := Start(remoteCtx, "child")
+	require.Equal(t, span.SpanContext().TraceID(), child.SpanContext().TraceID())
+
+	End(child, nil)
+	End(span, nil)
+
+	err = Shutdown(context.Background())
+	require.NoError(t, err)
+
+	r := <-received
+	require.Equal(t, "/v1/traces", r.URL.Path)
+	require.Equal(t, "incus", r.Header.Get("X-Scope-OrgID"))
+
+	// Once disabled, no span gets recorded.
+	ctx, span = Start(context.Background(), "disabled")
+	require.False(t, span.IsRecording())
+	require.False(t, HasSpan(ctx))
+}
+
+func TestSetupInvalid(t *testing.T) {
+	err := Setup("ftp://collector.example.net", nil, "", "server01")
+	require.Error(t, err)
+}
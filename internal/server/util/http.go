@@ -162,7 +162,7 @@ func CheckTrustState(cert x509.Certificate, trustedCerts map[string]x509.Certifi
 		return false, ""
 	}
 
-	if networkCert != nil && trustCACertificates {
+	if networkCert != nil {
 		ca := networkCert.CA()
 
 		if ca != nil && cert.CheckSignatureFrom(ca) == nil {
@@ -181,8 +181,15 @@ func CheckTrustState(cert x509.Certificate, trustedCerts map[string]x509.Certifi
 				}
 			}
 
+			// Revocations published through the configured CRL URL and OCSP apply to trusted certificates too.
+			if certificateRevoked(&cert, ca) {
+				return false, ""
+			}
+
 			// Certificate not revoked, so trust it as is signed by CA cert.
-			return true, localtls.CertFingerprint(&cert)
+			if trustCACertificates {
+				return true, localtls.CertFingerprint(&cert)
+			}
 		}
 	}
 
@@ -0,0 +1,275 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// OCSP responses are cached until their next update, within those bounds.
+// Failed lookups are cached too so that an unreachable responder doesn't slow down every request.
+const (
+	revocationOCSPMinCache     = time.Minute
+	revocationOCSPDefaultCache = 15 * time.Minute
+	revocationOCSPMaxCache     = time.Hour
+	revocationOCSPFailureCache = time.Minute
+	revocationOCSPTimeout      = 3 * time.Second
+	revocationTimeout          = 10 * time.Second
+)
+
+// revocationOCSPEntry is a cached OCSP answer.
+type revocationOCSPEntry struct {
+	revoked bool
+	expiry  time.Time
+}
+
+// revocationOCSPLookup is an OCSP lookup in progress, shared by all the requests for the same certificate.
+type revocationOCSPLookup struct {
+	done  chan struct{}
+	entry revocationOCSPEntry
+}
+
+// revocationCache holds the revocation state of the certificates signed by the trusted CA, as retrieved from the
+// configured CRL distribution point and OCSP responders.
+type revocationCache struct {
+	mu sync.Mutex
+
+	crlURL string
+	ocsp   bool
+	client *http.Client
+
+	crl         *x509.RevocationList
+	ocspEntries map[string]revocationOCSPEntry
+	ocspLookups map[string]*revocationOCSPLookup
+}
+
+var revocation = &revocationCache{ocspEntries: map[string]revocationOCSPEntry{}, ocspLookups: map[string]*revocationOCSPLookup{}}
+
+// SetRevocationConfig configures the live revocation checks of the certificates signed by the trusted CA.
+// An empty crlURL disables the fetching of the CRL and ocspEnabled toggles the OCSP checks. Cached results are
+// dropped when the configuration changes.
+func SetRevocationConfig(crlURL string, ocspEnabled bool, client *http.Client) {
+	revocation.mu.Lock()
+	defer revocation.mu.Unlock()
+
+	if crlURL != revocation.crlURL {
+		revocation.crl = nil
+	}
+
+	if ocspEnabled != revocation.ocsp {
+		revocation.ocspEntries = map[string]revocationOCSPEntry{}
+		revocation.ocspLookups = map[string]*revocationOCSPLookup{}
+	}
+
+	revocation.crlURL = crlURL
+	revocation.ocsp = ocspEnabled
+	revocation.client = client
+}
+
+// RefreshRevocationList fetches the CRL from the configured URL and caches it once validated against the CA.
+// The previously fetched CRL is kept if the new one can't be retrieved.
+// It also drops the cached OCSP answers which are too old to be used.
+func RefreshRevocationList(ctx context.Context, ca *x509.Certificate) error {
+	revocation.mu.Lock()
+	crlURL := revocation.crlURL
+	client := revocation.client
+
+	for serial, entry := range revocation.ocspEntries {
+		if time.Since(entry.expiry) > revocationOCSPMaxCache {
+			delete(revocation.ocspEntries, serial)
+		}
+	}
+
+	revocation.mu.Unlock()
+
+	if crlURL == "" || ca == nil {
+		return nil
+	}
+
+	body, err := revocationFetch(ctx, client, http.MethodGet, crlURL, "", nil)
+	if err != nil {
+		return fmt.Errorf("Failed fetching CRL from %q: %w", crlURL, err)
+	}
+
+	// Accept both PEM and DER encoded lists.
+	block, _ := pem.Decode(body)
+	if block != nil {
+		body = block.Bytes
+	}
+
+	crl, err := x509.ParseRevocationList(body)
+	if err != nil {
+		return fmt.Errorf("Failed parsing CRL from %q: %w", crlURL, err)
+	}
+
+	err = crl.CheckSignatureFrom(ca)
+	if err != nil {
+		return fmt.Errorf("CRL from %q isn't signed by the CA: %w", crlURL, err)
+	}
+
+	revocation.mu.Lock()
+	defer revocation.mu.Unlock()
+
+	// Ignore the list if the configuration changed in the meantime.
+	if revocation.crlURL == crlURL {
+		revocation.crl = crl
+	}
+
+	return nil
+}
+
+// certificateRevoked returns whether a certificate signed by the CA was revoked according to the fetched CRL or
+// to the OCSP responders listed in the certificate.
+//
+// OCSP failures are logged and don't cause the certificate to be rejected. Expired OCSP answers keep being used
+// while they're refreshed in the background, so only the first check of a certificate waits for the responder.
+func certificateRevoked(cert *x509.Certificate, ca *x509.Certificate) bool {
+	revocation.mu.Lock()
+	crl := revocation.crl
+	ocspEnabled := revocation.ocsp
+	revocation.mu.Unlock()
+
+	if crl != nil {
+		for _, revoked := range crl.RevokedCertificateEntries {
+			if cert.SerialNumber.Cmp(revoked.SerialNumber) == 0 {
+				return true
+			}
+		}
+	}
+
+	if !ocspEnabled || len(cert.OCSPServer) == 0 {
+		return false
+	}
+
+	serial := cert.SerialNumber.String()
+
+	revocation.mu.Lock()
+	entry, cached := revocation.ocspEntries[serial]
+	if cached && time.Now().Before(entry.expiry) {
+		revocation.mu.Unlock()
+		return entry.revoked
+	}
+
+	lookup, pending := revocation.ocspLookups[serial]
+	if !pending {
+		lookup = &revocationOCSPLookup{done: make(chan struct{})}
+		revocation.ocspLookups[serial] = lookup
+
+		go revocationOCSPLookupRun(lookup, revocation.client, cert, ca)
+	}
+
+	revocation.mu.Unlock()
+
+	// Use the expired answer while it's being refreshed.
+	if cached && time.Since(entry.expiry) <= revocationOCSPMaxCache {
+		return entry.revoked
+	}
+
+	<-lookup.done
+
+	return lookup.entry.revoked
+}
+
+// revocationOCSPLookupRun performs an OCSP lookup and caches its result.
+func revocationOCSPLookupRun(lookup *revocationOCSPLookup, client *http.Client, cert *x509.Certificate, ca *x509.Certificate) {
+	serial := cert.SerialNumber.String()
+
+	entry, err := revocationOCSPCheck(client, cert, ca)
+	if err != nil {
+		logger.Warn("Failed checking certificate revocation through OCSP", logger.Ctx{"serial": serial, "subject": cert.Subject.String(), "err": err})
+		entry = revocationOCSPEntry{expiry: time.Now().Add(revocationOCSPFailureCache)}
+	}
+
+	revocation.mu.Lock()
+	defer revocation.mu.Unlock()
+
+	// Don't cache answers for a previous configuration.
+	if revocation.ocspLookups[serial] == lookup {
+		revocation.ocspEntries[serial] = entry
+		delete(revocation.ocspLookups, serial)
+	}
+
+	lookup.entry = entry
+	close(lookup.done)
+}
+
+// revocationOCSPCheck queries the OCSP responders of a certificate until one of them answers.
+func revocationOCSPCheck(client *http.Client, cert *x509.Certificate, ca *x509.Certificate) (revocationOCSPEntry, error) {
+	req, err := ocsp.CreateRequest(cert, ca, nil)
+	if err != nil {
+		return revocationOCSPEntry{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), revocationOCSPTimeout)
+	defer cancel()
+
+	for _, server := range cert.OCSPServer {
+		var body []byte
+		body, err = revocationFetch(ctx, client, http.MethodPost, server, "application/ocsp-request", req)
+		if err != nil {
+			continue
+		}
+
+		var resp *ocsp.Response
+		resp, err = ocsp.ParseResponseForCert(body, cert, ca)
+		if err != nil {
+			continue
+		}
+
+		if resp.Status == ocsp.Unknown {
+			err = fmt.Errorf("Certificate unknown to OCSP responder %q", server)
+			continue
+		}
+
+		cache := revocationOCSPDefaultCache
+		if !resp.NextUpdate.IsZero() {
+			cache = min(max(time.Until(resp.NextUpdate), revocationOCSPMinCache), revocationOCSPMaxCache)
+		}
+
+		return revocationOCSPEntry{revoked: resp.Status == ocsp.Revoked, expiry: time.Now().Add(cache)}, nil
+	}
+
+	return revocationOCSPEntry{}, err
+}
+
+// revocationFetch performs an HTTP request to a revocation service and returns the response body.
+func revocationFetch(ctx context.Context, client *http.Client, method string, url string, contentType string, data []byte) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, revocationTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected HTTP status: %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,183 @@
+package util
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// revocationTestCert creates a certificate signed by the given CA, or a self-signed CA if none is given.
+func revocationTestCert(t *testing.T, serial int64, ocspURL string, ca *x509.Certificate, caKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	if ocspURL != "" {
+		template.OCSPServer = []string{ocspURL}
+	}
+
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+		ca = template
+		caKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func TestCertificateRevokedCRL(t *testing.T) {
+	ca, caKey := revocationTestCert(t, 1, "", nil, nil)
+	revoked, _ := revocationTestCert(t, 2, "", ca, caKey)
+	valid, _ := revocationTestCert(t, 3, "", ca, caKey)
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                time.Now(),
+		NextUpdate:                time.Now().Add(time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{{SerialNumber: revoked.SerialNumber, RevocationTime: time.Now()}},
+	}, ca, caKey)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(crl)
+	}))
+	defer server.Close()
+
+	SetRevocationConfig(server.URL, false, nil)
+	defer SetRevocationConfig("", false, nil)
+
+	assert.False(t, certificateRevoked(revoked, ca))
+
+	err = RefreshRevocationList(context.Background(), ca)
+	require.NoError(t, err)
+
+	assert.True(t, certificateRevoked(revoked, ca))
+	assert.False(t, certificateRevoked(valid, ca))
+
+	// A list that isn't signed by the CA is rejected.
+	otherCA, _ := revocationTestCert(t, 4, "", nil, nil)
+	err = RefreshRevocationList(context.Background(), otherCA)
+	assert.Error(t, err)
+
+	// Changing the URL drops the list.
+	SetRevocationConfig("", false, nil)
+	assert.False(t, certificateRevoked(revoked, ca))
+}
+
+func TestCertificateRevokedOCSP(t *testing.T) {
+	ca, caKey := revocationTestCert(t, 1, "", nil, nil)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		status := ocsp.Good
+		if req.SerialNumber.Int64() == 2 {
+			status = ocsp.Revoked
+		}
+
+		resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now(),
+		}, caKey)
+		require.NoError(t, err)
+
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	revoked, _ := revocationTestCert(t, 2, server.URL, ca, caKey)
+	valid, _ := revocationTestCert(t, 3, server.URL, ca, caKey)
+
+	// OCSP is disabled by default.
+	assert.False(t, certificateRevoked(revoked, ca))
+	assert.Equal(t, 0, requests)
+
+	SetRevocationConfig("", true, nil)
+	defer SetRevocationConfig("", false, nil)
+
+	assert.True(t, certificateRevoked(revoked, ca))
+	assert.False(t, certificateRevoked(valid, ca))
+	assert.Equal(t, 2, requests)
+
+	// The answers are cached.
+	assert.True(t, certificateRevoked(revoked, ca))
+	assert.Equal(t, 2, requests)
+}
+
+func TestCertificateRevokedOCSPFailure(t *testing.T) {
+	ca, caKey := revocationTestCert(t, 1, "", nil, nil)
+
+	var requests atomic.Int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cert, _ := revocationTestCert(t, 2, server.URL, ca, caKey)
+
+	SetRevocationConfig("", true, nil)
+	defer SetRevocationConfig("", false, nil)
+
+	// Concurrent checks of the same certificate share a single lookup.
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.False(t, certificateRevoked(cert, ca))
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), requests.Load())
+
+	// Failures are cached too.
+	assert.False(t, certificateRevoked(cert, ca))
+	assert.Equal(t, int64(1), requests.Load())
+}
@@ -467,6 +467,31 @@ var APIExtensions = []string{
 	"acme_dns01",
 	"security_iommu",
 	"network_ipv4_dhcp_routes",
+	"instance_placement_rules",
+	"cluster_rollout",
+	"cluster_rebalance_scoring",
+	"instance_logging_loki",
+	"otlp_tracing",
+	"agent_filesystem_freeze",
+	"instance_healthcheck",
+	"instance_boot_dependencies",
+	"instance_socket_activation",
+	"vm_memory_hotplug",
+	"instance_ignition",
+	"container_syscall_intercept_new_mount_api",
+	"instance_session_recording",
+	"instance_state_history",
+	"instance_backup_stateful",
+	"auth_rbac",
+	"auth_oidc_groups",
+	"auth_tokens",
+	"audit_log",
+	"projects_limits_devices",
+	"projects_usage_report",
+	"project_templates",
+	"certificate_renewal",
+	"certificate_revocation_checks",
+	"admission_scriptlet",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
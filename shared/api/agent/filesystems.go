@@ -0,0 +1,26 @@
+package api
+
+// FilesystemsFreezePost contains the fields used to freeze the guest file systems.
+type FilesystemsFreezePost struct {
+	// Reason for the freeze, passed to the hook scripts
+	// Example: snapshot
+	Reason string `json:"reason" yaml:"reason"`
+
+	// Number of seconds after which the file systems get thawed automatically
+	// Example: 30
+	Timeout int `json:"timeout" yaml:"timeout"`
+}
+
+// FilesystemsThawPost contains the fields used to thaw the guest file systems.
+type FilesystemsThawPost struct {
+	// Reason for the freeze, passed to the hook scripts
+	// Example: snapshot
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// Filesystems represents the file systems frozen by the agent.
+type Filesystems struct {
+	// List of frozen mount points
+	// Example: ["/", "/home"]
+	Frozen []string `json:"frozen" yaml:"frozen"`
+}
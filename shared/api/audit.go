@@ -0,0 +1,68 @@
+package api
+
+import (
+	"time"
+)
+
+// AuditEntry represents an entry of the audit log
+//
+// swagger:model
+//
+// API extension: audit_log.
+type AuditEntry struct {
+	// When the request was handled
+	// Example: 2024-10-19T12:00:00Z
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// HTTP method of the request
+	// Example: POST
+	Method string `json:"method" yaml:"method"`
+
+	// URL of the request
+	// Example: /1.0/instances?project=default
+	URL string `json:"url" yaml:"url"`
+
+	// Project targeted by the request
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// User name or certificate fingerprint of the requestor
+	// Example: jane.doe@example.com
+	Username string `json:"username" yaml:"username"`
+
+	// Authentication protocol used by the requestor
+	// Example: oidc
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// Source address of the request
+	// Example: 10.0.2.15
+	Address string `json:"address" yaml:"address"`
+
+	// HTTP status code of the response
+	// Example: 202
+	StatusCode int `json:"status_code" yaml:"status_code"`
+
+	// ID of the operation created by the request (if any)
+	// Example: b8d84888-1dc2-44fd-b386-7f679e171ba5
+	OperationID string `json:"operation_id" yaml:"operation_id"`
+
+	// Cluster member which handled the request
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// Message recorded by the log itself, like why a new chain was started
+	// Example: Started a new chain as the previous one failed its integrity check
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// Position of the entry in the log, starting at 1
+	// Example: 42
+	Sequence int64 `json:"sequence" yaml:"sequence"`
+
+	// Hash of the previous entry of the log
+	// Example: 5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef
+	PreviousHash string `json:"previous_hash" yaml:"previous_hash"`
+
+	// Keyed hash (HMAC-SHA256) of this entry, covering all its other fields
+	// Example: 0e1d9ab4d2b0f0cf4cc0e4bd1c8b5b6b1a3a8a2b2e0c6f5b0d3e8f7c6a5b4c3d
+	Hash string `json:"hash" yaml:"hash"`
+}
@@ -1,9 +1,228 @@
 package api
 
+import (
+	"time"
+)
+
 const (
 	// AuthenticationMethodTLS is the default authentication method for interacting with Incus remotely.
 	AuthenticationMethodTLS = "tls"
 
 	// AuthenticationMethodOIDC is a token based authentication method.
 	AuthenticationMethodOIDC = "oidc"
+
+	// AuthenticationMethodToken is the authentication method used by short-lived scoped bearer tokens.
+	//
+	// API extension: auth_tokens.
+	AuthenticationMethodToken = "token"
+)
+
+const (
+	// AuthRoleViewer grants read-only access.
+	AuthRoleViewer = "viewer"
+
+	// AuthRoleOperator grants full access to the resources of a project, but not to the project itself.
+	AuthRoleOperator = "operator"
+
+	// AuthRoleAdmin grants full access, including to the project configuration.
+	AuthRoleAdmin = "admin"
 )
+
+// AuthGroupsPost represents the fields of a new authorization group
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupsPost struct {
+	AuthGroupPut `yaml:",inline"`
+
+	// The name of the group
+	// Example: developers
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthGroupPut represents the modifiable fields of an authorization group
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupPut struct {
+	// Description of the group
+	// Example: Developers of the web frontend
+	Description string `json:"description" yaml:"description"`
+
+	// Roles granted to the members of the group
+	Roles []AuthGroupRole `json:"roles" yaml:"roles"`
+
+	// Groups from the identity provider whose members are considered members of this group
+	// Example: ["idp-developers"]
+	IdentityProviderGroups []string `json:"identity_provider_groups" yaml:"identity_provider_groups"`
+}
+
+// AuthGroupRole represents a role granted to an authorization group
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupRole struct {
+	// Name of the role (viewer, operator or admin)
+	// Example: operator
+	Role string `json:"role" yaml:"role"`
+
+	// Project the role applies to (empty for all projects and the server itself)
+	// Example: frontend
+	Project string `json:"project" yaml:"project"`
+}
+
+// AuthGroup represents an authorization group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroup struct {
+	AuthGroupPut `yaml:",inline"`
+
+	// The name of the group
+	// Example: developers
+	Name string `json:"name" yaml:"name"`
+
+	// Identities which are members of the group, keyed by authentication method
+	// Read only: true
+	// Example: {"oidc": ["jane.doe@example.com"]}
+	Identities map[string][]string `json:"identities" yaml:"identities"`
+}
+
+// Writable converts a full AuthGroup struct into a AuthGroupPut struct (filters read-only fields).
+func (g *AuthGroup) Writable() AuthGroupPut {
+	return g.AuthGroupPut
+}
+
+// AuthGroupPost represents the fields required to rename an authorization group
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupPost struct {
+	// The new name for the group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthIdentitiesPost represents the fields of a new identity
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthIdentitiesPost struct {
+	AuthIdentityPut `yaml:",inline"`
+
+	// Authentication method of the identity (tls or oidc)
+	// Example: oidc
+	AuthenticationMethod string `json:"authentication_method" yaml:"authentication_method"`
+
+	// Identifier of the identity (certificate fingerprint or OIDC username)
+	// Example: jane.doe@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// AuthIdentityPut represents the modifiable fields of an identity
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthIdentityPut struct {
+	// Display name of the identity
+	// Example: Jane Doe
+	Name string `json:"name" yaml:"name"`
+
+	// Groups the identity is a member of
+	// Example: ["developers"]
+	Groups []string `json:"groups" yaml:"groups"`
+}
+
+// AuthIdentity represents an identity.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthIdentity struct {
+	AuthIdentityPut `yaml:",inline"`
+
+	// Authentication method of the identity (tls or oidc)
+	// Example: oidc
+	AuthenticationMethod string `json:"authentication_method" yaml:"authentication_method"`
+
+	// Identifier of the identity (certificate fingerprint or OIDC username)
+	// Example: jane.doe@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// Writable converts a full AuthIdentity struct into a AuthIdentityPut struct (filters read-only fields).
+func (i *AuthIdentity) Writable() AuthIdentityPut {
+	return i.AuthIdentityPut
+}
+
+// AuthTokensPost represents the fields of a new authentication token
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokensPost struct {
+	// Description of the token
+	// Example: CI pipeline for the frontend
+	Description string `json:"description" yaml:"description"`
+
+	// Entitlements granted by the token on the project and its resources
+	// Example: ["can_view", "can_exec"]
+	Entitlements []string `json:"entitlements" yaml:"entitlements"`
+
+	// When the token expires
+	// Example: 2024-10-19T13:00:00Z
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// AuthToken represents an authentication token
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthToken struct {
+	// Unique identifier of the token
+	// Example: 3f2c9d1e-8a4b-4c6d-9e0f-1a2b3c4d5e6f
+	ID string `json:"id" yaml:"id"`
+
+	// Description of the token
+	// Example: CI pipeline for the frontend
+	Description string `json:"description" yaml:"description"`
+
+	// Project the token is restricted to
+	// Example: frontend
+	Project string `json:"project" yaml:"project"`
+
+	// Entitlements granted by the token on the project and its resources
+	// Example: ["can_view", "can_exec"]
+	Entitlements []string `json:"entitlements" yaml:"entitlements"`
+
+	// When the token was created
+	// Example: 2024-10-19T12:00:00Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// When the token expires
+	// Example: 2024-10-19T13:00:00Z
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// AuthTokenSecret represents a newly created authentication token along with its secret value
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokenSecret struct {
+	// Unique identifier of the token
+	// Example: 3f2c9d1e-8a4b-4c6d-9e0f-1a2b3c4d5e6f
+	ID string `json:"id" yaml:"id"`
+
+	// The bearer token to pass in the Authorization header
+	// Example: eyJhbGciOiJFUzM4NCIsInR5cCI6IkpXVCJ9...
+	Token string `json:"token" yaml:"token"`
+}
@@ -96,6 +96,17 @@ func (c *Certificate) URL(apiVersion string) *URL {
 	return NewURL().Path(apiVersion, "certificates", c.Fingerprint)
 }
 
+// CertificateRenewPost represents the fields required to renew a trusted certificate
+//
+// swagger:model
+//
+// API extension: certificate_renewal.
+type CertificateRenewPost struct {
+	// The new certificate, as PEM encoded X509
+	// Example: X509 PEM certificate
+	Certificate string `json:"certificate" yaml:"certificate"`
+}
+
 // CertificateAddToken represents the fields contained within an encoded certificate add token.
 //
 // swagger:model
@@ -0,0 +1,87 @@
+package api
+
+// ClusterRolloutPost represents the fields required to start a cluster rollout.
+//
+// swagger:model
+//
+// API extension: cluster_rollout.
+type ClusterRolloutPost struct {
+	// List of cluster members to roll out (defaults to all members)
+	// Example: ["server01", "server02"]
+	Members []string `json:"members" yaml:"members"`
+
+	// Override the configured evacuation mode
+	// Example: live-migrate
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Condition to wait for once a member is evacuated ("version", "restart" or "hook")
+	// Example: version
+	Wait string `json:"wait" yaml:"wait"`
+
+	// How long to wait (in seconds) for a member to meet the wait condition
+	// Example: 3600
+	Timeout int64 `json:"timeout" yaml:"timeout"`
+}
+
+// ClusterRolloutPut represents the actions that can be performed on a running cluster rollout.
+//
+// swagger:model
+//
+// API extension: cluster_rollout.
+type ClusterRolloutPut struct {
+	// The action to be performed ("pause", "resume" or "continue")
+	// Example: pause
+	Action string `json:"action" yaml:"action"`
+}
+
+// ClusterRollout represents the state of a cluster rollout.
+//
+// swagger:model
+//
+// API extension: cluster_rollout.
+type ClusterRollout struct {
+	// Name of the cluster member coordinating the rollout
+	// Example: server01
+	Coordinator string `json:"coordinator" yaml:"coordinator"`
+
+	// UUID of the operation running the rollout
+	// Example: b8d84888-1dc2-44fd-b386-7f679e171ba5
+	Operation string `json:"operation" yaml:"operation"`
+
+	// Current status of the rollout ("running", "paused", "completed" or "failed")
+	// Example: running
+	Status string `json:"status" yaml:"status"`
+
+	// The options the rollout was started with
+	Options ClusterRolloutPost `json:"options" yaml:"options"`
+
+	// Per-member progress
+	Members []ClusterRolloutMember `json:"members" yaml:"members"`
+}
+
+// ClusterRolloutMember represents the rollout progress of a single cluster member.
+//
+// swagger:model
+//
+// API extension: cluster_rollout.
+type ClusterRolloutMember struct {
+	// Name of the cluster member
+	// Example: server01
+	Name string `json:"name" yaml:"name"`
+
+	// Current step for the member ("pending", "evacuating", "waiting", "restoring", "checking", "done", "handed-off" or "failed")
+	// Example: waiting
+	Status string `json:"status" yaml:"status"`
+
+	// Server version before the rollout
+	// Example: 6.10
+	OldVersion string `json:"old_version" yaml:"old_version"`
+
+	// Server version after the rollout
+	// Example: 6.11
+	NewVersion string `json:"new_version" yaml:"new_version"`
+
+	// Additional information (e.g. error message)
+	// Example: Timed out waiting for the member to report a new version
+	Message string `json:"message" yaml:"message"`
+}
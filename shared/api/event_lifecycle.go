@@ -2,8 +2,19 @@ package api
 
 // Define consts for all the lifecycle events.
 const (
+	EventLifecycleAuthGroupCreated                  = "auth-group-created"
+	EventLifecycleAuthGroupDeleted                  = "auth-group-deleted"
+	EventLifecycleAuthGroupRenamed                  = "auth-group-renamed"
+	EventLifecycleAuthGroupUpdated                  = "auth-group-updated"
+	EventLifecycleAuthIdentityCreated               = "auth-identity-created"
+	EventLifecycleAuthIdentityDeleted               = "auth-identity-deleted"
+	EventLifecycleAuthIdentityUpdated               = "auth-identity-updated"
+	EventLifecycleAuthTokenCreated                  = "auth-token-created"
+	EventLifecycleAuthTokenDeleted                  = "auth-token-deleted"
 	EventLifecycleCertificateCreated                = "certificate-created"
 	EventLifecycleCertificateDeleted                = "certificate-deleted"
+	EventLifecycleCertificateExpiring               = "certificate-expiring"
+	EventLifecycleCertificateRenewed                = "certificate-renewed"
 	EventLifecycleCertificateUpdated                = "certificate-updated"
 	EventLifecycleClusterCertificateUpdated         = "cluster-certificate-updated"
 	EventLifecycleClusterDisabled                   = "cluster-disabled"
@@ -44,6 +55,7 @@ const (
 	EventLifecycleInstanceFileDeleted               = "instance-file-deleted"
 	EventLifecycleInstanceFilePushed                = "instance-file-pushed"
 	EventLifecycleInstanceFileRetrieved             = "instance-file-retrieved"
+	EventLifecycleInstanceHealthy                   = "instance-healthy"
 	EventLifecycleInstanceLogDeleted                = "instance-log-deleted"
 	EventLifecycleInstanceLogRetrieved              = "instance-log-retrieved"
 	EventLifecycleInstanceMetadataRetrieved         = "instance-metadata-retrieved"
@@ -58,6 +70,9 @@ const (
 	EventLifecycleInstanceRestarted                 = "instance-restarted"
 	EventLifecycleInstanceRestored                  = "instance-restored"
 	EventLifecycleInstanceResumed                   = "instance-resumed"
+	EventLifecycleInstanceSessionFinished           = "instance-session-finished"
+	EventLifecycleInstanceSessionRetrieved          = "instance-session-retrieved"
+	EventLifecycleInstanceSessionStarted            = "instance-session-started"
 	EventLifecycleInstanceShutdown                  = "instance-shutdown"
 	EventLifecycleInstanceSnapshotCreated           = "instance-snapshot-created"
 	EventLifecycleInstanceSnapshotDeleted           = "instance-snapshot-deleted"
@@ -65,6 +80,7 @@ const (
 	EventLifecycleInstanceSnapshotUpdated           = "instance-snapshot-updated"
 	EventLifecycleInstanceStarted                   = "instance-started"
 	EventLifecycleInstanceStopped                   = "instance-stopped"
+	EventLifecycleInstanceUnhealthy                 = "instance-unhealthy"
 	EventLifecycleInstanceUpdated                   = "instance-updated"
 	EventLifecycleNetworkACLCreated                 = "network-acl-created"
 	EventLifecycleNetworkACLDeleted                 = "network-acl-deleted"
@@ -102,6 +118,10 @@ const (
 	EventLifecycleProjectDeleted                    = "project-deleted"
 	EventLifecycleProjectRenamed                    = "project-renamed"
 	EventLifecycleProjectUpdated                    = "project-updated"
+	EventLifecycleProjectTemplateCreated            = "project-template-created"
+	EventLifecycleProjectTemplateDeleted            = "project-template-deleted"
+	EventLifecycleProjectTemplateRenamed            = "project-template-renamed"
+	EventLifecycleProjectTemplateUpdated            = "project-template-updated"
 	EventLifecycleStorageBucketBackupCreated        = "storage-bucket-backup-created"
 	EventLifecycleStorageBucketBackupDeleted        = "storage-bucket-backup-deleted"
 	EventLifecycleStorageBucketBackupRenamed        = "storage-bucket-backup-renamed"
@@ -31,6 +31,12 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_compression_algorithm
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+
+	// Whether to include the runtime state of the running instance (stops it statefully)
+	// Example: false
+	//
+	// API extension: instance_backup_stateful
+	Stateful bool `json:"stateful" yaml:"stateful"`
 }
 
 // InstanceBackup represents an instance backup.
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"time"
+)
+
+// InstanceSession represents a recorded interactive session.
+//
+// swagger:model
+//
+// API extension: instance_session_recording.
+type InstanceSession struct {
+	// Session identifier
+	// Example: 1c0d7a0e-43f2-4b6e-a6ea-5b4a6d3b9b8c
+	ID string `json:"id" yaml:"id"`
+
+	// Type of session (exec or console)
+	// Example: exec
+	Type string `json:"type" yaml:"type"`
+
+	// Command that was run (exec sessions only)
+	// Example: ["bash"]
+	Command []string `json:"command" yaml:"command"`
+
+	// Authenticated identity that started the session
+	// Example: foo@example.com
+	Username string `json:"username" yaml:"username"`
+
+	// Authentication protocol used by the identity
+	// Example: oidc
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// Remote address the session was started from
+	// Example: 10.0.0.1:51234
+	Address string `json:"address" yaml:"address"`
+
+	// When the session started
+	// Example: 2024-06-01T10:00:00Z
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// When the session was last written to
+	// Example: 2024-06-01T10:05:00Z
+	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
+
+	// Size of the recording in bytes
+	// Example: 16384
+	Size int64 `json:"size" yaml:"size"`
+}
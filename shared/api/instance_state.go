@@ -70,6 +70,70 @@ type InstanceState struct {
 	//
 	// API extension: instances_state_os_info.
 	OSInfo *InstanceStateOSInfo `json:"os_info" yaml:"os_info"`
+
+	// Health check information (only set when a health check is configured).
+	//
+	// API extension: instance_healthcheck.
+	Health *InstanceStateHealth `json:"health,omitempty" yaml:"health,omitempty"`
+
+	// Resource usage history (only set when requested through the history parameter)
+	//
+	// API extension: instance_state_history.
+	History []InstanceStateSample `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+// InstanceStateSample represents a point in the resource usage history of an instance.
+//
+// swagger:model
+//
+// API extension: instance_state_history.
+type InstanceStateSample struct {
+	// Time at which the sample was taken
+	// Example: 2024-11-04T14:32:00Z
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// CPU time consumed so far (in ns)
+	// Example: 3637691016
+	CPUUsage int64 `json:"cpu_usage" yaml:"cpu_usage"`
+
+	// Memory usage (in bytes)
+	// Example: 73248768
+	MemoryUsage int64 `json:"memory_usage" yaml:"memory_usage"`
+
+	// Disk usage of all the instance's disks (in bytes)
+	// Example: 502239232
+	DiskUsage int64 `json:"disk_usage" yaml:"disk_usage"`
+
+	// Bytes received so far on all network interfaces
+	// Example: 192021
+	NetworkBytesReceived int64 `json:"network_bytes_received" yaml:"network_bytes_received"`
+
+	// Bytes sent so far on all network interfaces
+	// Example: 10888579
+	NetworkBytesSent int64 `json:"network_bytes_sent" yaml:"network_bytes_sent"`
+}
+
+// InstanceStateHealth represents the health check information section of an instance's state.
+//
+// swagger:model
+//
+// API extension: instance_healthcheck.
+type InstanceStateHealth struct {
+	// Current health status (starting, healthy or unhealthy)
+	// Example: healthy
+	Status string `json:"status" yaml:"status"`
+
+	// Number of consecutive failed checks
+	// Example: 0
+	Failures int `json:"failures" yaml:"failures"`
+
+	// Time of the last check
+	// Example: 2024-11-04T14:32:10.123456789Z
+	LastCheck time.Time `json:"last_check" yaml:"last_check"`
+
+	// Error returned by the last failed check
+	// Example: dial tcp 10.0.0.2:80: connect: connection refused
+	LastError string `json:"last_error" yaml:"last_error"`
 }
 
 // InstanceStateDisk represents the disk information section of an instance's state.
@@ -127,6 +191,12 @@ type InstanceStateMemory struct {
 	// Peak SWAP usage in bytes
 	// Example: 12297557
 	SwapUsagePeak int64 `json:"swap_usage_peak" yaml:"swap_usage_peak"`
+
+	// Memory hotplugged into the virtual machine in bytes
+	// Example: 1073741824
+	//
+	// API extension: vm_memory_hotplug
+	Hotplugged int64 `json:"hotplugged,omitempty" yaml:"hotplugged,omitempty"`
 }
 
 // InstanceStateNetwork represents the network information section of an instance's state.
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // ProjectDefaultName is the name of the default project that can never be deleted.
 const ProjectDefaultName = "default"
 
@@ -14,6 +18,12 @@ type ProjectsPost struct {
 	// The name of the new project
 	// Example: foo
 	Name string `json:"name" yaml:"name"`
+
+	// Project template to create the project from
+	// Example: team
+	//
+	// API extension: project_templates.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
 }
 
 // ProjectPost represents the fields required to rename a project
@@ -99,3 +109,44 @@ type ProjectStateResource struct {
 	// Example: 4
 	Usage int64
 }
+
+// ProjectUsage represents the resources consumed by a project over a period of time
+//
+// swagger:model
+//
+// API extension: projects_usage_report.
+type ProjectUsage struct {
+	// Start of the reporting period
+	// Example: 2024-11-01T00:00:00Z
+	From time.Time `json:"from" yaml:"from"`
+
+	// End of the reporting period
+	// Example: 2024-12-01T00:00:00Z
+	To time.Time `json:"to" yaml:"to"`
+
+	// Consumed resources
+	Resources []ProjectUsageResource `json:"resources" yaml:"resources"`
+}
+
+// ProjectUsageResource represents the consumption of a particular resource by a project
+//
+// swagger:model
+//
+// API extension: projects_usage_report.
+type ProjectUsageResource struct {
+	// Resource type (cpu, memory, storage, network-received or network-sent)
+	// Example: storage
+	Type string `json:"type" yaml:"type"`
+
+	// Storage pool (only set for storage)
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Consumed amount, integrated over the reporting period
+	// Example: 12.5
+	Usage float64 `json:"usage" yaml:"usage"`
+
+	// Unit of the consumed amount (seconds, GB-hours or bytes)
+	// Example: GB-hours
+	Unit string `json:"unit" yaml:"unit"`
+}
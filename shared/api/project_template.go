@@ -0,0 +1,68 @@
+package api
+
+// ProjectTemplatesPost represents the fields of a new project template
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplatesPost struct {
+	ProjectTemplatePut `yaml:",inline"`
+
+	// The name of the new project template
+	// Example: team
+	Name string `json:"name" yaml:"name"`
+}
+
+// ProjectTemplatePost represents the fields required to rename a project template
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplatePost struct {
+	// The new name for the project template
+	// Example: team-small
+	Name string `json:"name" yaml:"name"`
+}
+
+// ProjectTemplatePut represents the modifiable fields of a project template
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplatePut struct {
+	// Description of the project template
+	// Example: Project of a development team
+	Description string `json:"description" yaml:"description"`
+
+	// Configuration of the projects created from the template
+	// Example: {"features.networks": "true", "limits.instances": "10", "restricted": "true"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// Configuration and devices of the default profile of the projects created from the template
+	DefaultProfile ProfilePut `json:"default_profile" yaml:"default_profile"`
+
+	// Networks to create in the projects created from the template
+	Networks []NetworksPost `json:"networks" yaml:"networks"`
+
+	// Network ACLs to create in the projects created from the template
+	NetworkACLs []NetworkACLsPost `json:"network_acls" yaml:"network_acls"`
+}
+
+// ProjectTemplate represents a project template
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplate struct {
+	ProjectTemplatePut `yaml:",inline"`
+
+	// The name of the project template
+	// Read only: true
+	// Example: team
+	Name string `json:"name" yaml:"name"`
+}
+
+// Writable converts a full ProjectTemplate struct into a ProjectTemplatePut struct (filters read-only fields).
+func (t *ProjectTemplate) Writable() ProjectTemplatePut {
+	return t.ProjectTemplatePut
+}
@@ -1125,4 +1125,48 @@ type ResourcesLoad struct {
 	// The number of active processes
 	// Example: 1234
 	Processes int
+
+	// Pressure stall information (if supported by the kernel)
+	//
+	// API extension: cluster_rebalance_scoring
+	Pressure *ResourcesLoadPressure `json:"pressure,omitempty" yaml:"pressure,omitempty"`
+
+	// Network throughput of the physical network interfaces
+	//
+	// API extension: cluster_rebalance_scoring
+	Network *ResourcesLoadNetwork `json:"network,omitempty" yaml:"network,omitempty"`
+}
+
+// ResourcesLoadPressure represents the pressure stall information of the system
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_scoring.
+type ResourcesLoadPressure struct {
+	// Percentage of time in the past minute during which some tasks were stalled on CPU
+	// Example: 2.51
+	CPU float64 `json:"cpu" yaml:"cpu"`
+
+	// Percentage of time in the past minute during which some tasks were stalled on memory
+	// Example: 0.12
+	Memory float64 `json:"memory" yaml:"memory"`
+
+	// Percentage of time in the past minute during which some tasks were stalled on I/O
+	// Example: 4.20
+	IO float64 `json:"io" yaml:"io"`
+}
+
+// ResourcesLoadNetwork represents the network throughput of the system
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_scoring.
+type ResourcesLoadNetwork struct {
+	// Average throughput (received and transmitted, in bytes per second) over the past few seconds
+	// Example: 1048576
+	Throughput uint64 `json:"throughput" yaml:"throughput"`
+
+	// Combined link speed (in bytes per second) of the physical network interfaces
+	// Example: 125000000
+	Capacity uint64 `json:"capacity" yaml:"capacity"`
 }
@@ -0,0 +1,54 @@
+package scriptlet
+
+import (
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// AdmissionActionCreate is when a new object is being created.
+const AdmissionActionCreate = "create"
+
+// AdmissionActionUpdate is when an existing object is being updated.
+const AdmissionActionUpdate = "update"
+
+// AdmissionTypeInstance is for instances.
+const AdmissionTypeInstance = "instance"
+
+// AdmissionTypeProfile is for profiles.
+const AdmissionTypeProfile = "profile"
+
+// AdmissionTypeStorageVolume is for custom storage volumes.
+const AdmissionTypeStorageVolume = "storage_volume"
+
+// AdmissionTypeNetwork is for networks.
+const AdmissionTypeNetwork = "network"
+
+// AdmissionRequest represents an object creation or update submitted to the admission scriptlet.
+//
+// API extension: admission_scriptlet.
+type AdmissionRequest struct {
+	// Whether the object is being created or updated
+	// Example: create
+	Action string `json:"action"`
+
+	// Type of the object (instance, profile, storage_volume or network)
+	// Example: instance
+	Type string `json:"type"`
+
+	// Project of the object
+	// Example: default
+	Project string `json:"project"`
+
+	// Storage pool of the object (only set for storage volumes)
+	// Example: default
+	Pool string `json:"pool"`
+
+	// Name of the object (empty for instances whose name is generated)
+	// Example: c1
+	Name string `json:"name"`
+
+	// Requested object, as sent to the API
+	Object any `json:"object"`
+
+	// Identity making the request
+	Identity *api.EventLifecycleRequestor `json:"identity"`
+}
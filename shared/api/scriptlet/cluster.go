@@ -0,0 +1,13 @@
+package scriptlet
+
+// ClusterRebalanceUsage represents the resource usage of a cluster member as seen by the re-balancing logic.
+// All values are percentages between 0 and 100 and include any instance that's being considered for a move.
+//
+// API extension: cluster_rebalance_scoring.
+type ClusterRebalanceUsage struct {
+	CPU      float64 `json:"cpu"`
+	Memory   float64 `json:"memory"`
+	Storage  float64 `json:"storage"`
+	Pressure float64 `json:"pressure"`
+	Network  float64 `json:"network"`
+}
@@ -289,6 +289,11 @@ func (c *Config) getConnectionArgs(name string) (*incus.ConnectionArgs, error) {
 		args.TLSServerCert = string(content)
 	}
 
+	// Authentication token provided through the environment.
+	if remote.Protocol == "incus" && remote.AuthType != api.AuthenticationMethodOIDC {
+		args.BearerToken = os.Getenv("INCUS_AUTH_TOKEN")
+	}
+
 	// Stop here if no client certificate involved
 	if remote.Protocol != "incus" || slices.Contains([]string{api.AuthenticationMethodOIDC}, remote.AuthType) {
 		return &args, nil